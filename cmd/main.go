@@ -32,6 +32,22 @@ func main() {
 		log.Fatalf("Failed to create application: %v", err)
 	}
 
+	// `migrate <command>` manages the database schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.RunMigrate(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// `seed <file>...` loads development fixtures instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := app.RunSeed(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Seed failed: %v", err)
+		}
+		return
+	}
+
 	// Initialize the application
 	if err := app.Initialize(); err != nil {
 		log.Fatalf("Failed to initialize application: %v", err)
@@ -6,21 +6,33 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/auth"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/eventbus"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/notification"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/outbox"
 	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/storage"
 	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/controller"
 	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
 	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/route"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/job"
 	"github.com/kirklin/boot-backend-go-clean/internal/usecase"
 	"github.com/kirklin/boot-backend-go-clean/pkg/configs"
 	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/migrate"
 	"github.com/kirklin/boot-backend-go-clean/pkg/database/mysql"
 	"github.com/kirklin/boot-backend-go-clean/pkg/database/postgres"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
 	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/fieldcrypt"
 	snowflakeutils "github.com/kirklin/boot-backend-go-clean/pkg/utils/snowflake"
 )
 
@@ -30,6 +42,12 @@ type Application struct {
 	Router     *gin.Engine
 	DB         database.Database
 	httpServer *http.Server
+	jobs       []*job.Periodic
+	jobsWG     sync.WaitGroup
+	events     *eventbus.Bus
+	// started is set once the database is connected and migrated; until
+	// then readiness reports "starting" and other requests get 503
+	started atomic.Bool
 }
 
 // NewApplication creates and initializes a new Application instance
@@ -64,10 +82,15 @@ func NewApplication() (*Application, error) {
 	// Add timeout middleware
 	router.Use(middleware.TimeoutMiddleware(time.Duration(config.RequestTimeout) * time.Second))
 
+	// Keep reads of state-changing requests on the primary database
+	router.Use(middleware.ReadPreferenceMiddleware())
+
 	app := &Application{
 		Config: config,
 		Router: router,
 	}
+	// Serve only the infrastructure endpoints until the database is ready
+	router.Use(middleware.StartupGateMiddleware(app.started.Load, "/", "/metrics", "/health", "/debug/pprof"))
 
 	return app, nil
 }
@@ -83,37 +106,13 @@ func (app *Application) Initialize() error {
 		logger.GetLogger().Fatalf("failed to init snowflake node: %v", err)
 	}
 
-	// Initialize database
-	dbConfig := &database.Config{
-		Host:                   app.Config.DBHost,
-		Port:                   app.Config.DBPort,
-		User:                   app.Config.DBUser,
-		Password:               app.Config.DBPassword,
-		DBName:                 app.Config.DBName,
-		SSLMode:                app.Config.DBSSLMode,
-		MaxIdleConns:           app.Config.DBMaxIdleConns,
-		MaxOpenConns:           app.Config.DBMaxOpenConns,
-		ConnMaxLifetimeMinutes: app.Config.DBConnMaxLifetimeMinutes,
-	}
-
-	var err error
-	switch app.Config.DBType {
-	case "postgres":
-		app.DB = postgres.NewPostgresDB()
-	case "mysql":
-		app.DB = mysql.NewMySQLDB()
-	default:
-		logger.GetLogger().Fatalf("unsupported database type: %s", app.Config.DBType)
-	}
-
-	err = app.DB.Connect(dbConfig)
+	// The database is connected by Run, in the background: repositories only
+	// use it once serving starts (see StartupGateMiddleware)
+	db, err := app.newDatabase()
 	if err != nil {
-		logger.GetLogger().Fatalf("failed to connect to database: %v", err)
-	}
-
-	if err := persistence.AutoMigrate(app.DB); err != nil {
-		logger.GetLogger().Fatalf("failed to auto migrate: %v", err)
+		logger.GetLogger().Fatalf("failed to create database: %v", err)
 	}
+	app.DB = db
 
 	// Composition Root: build all dependencies in layer order.
 	// Only app.Initialize knows about concrete implementations;
@@ -129,31 +128,256 @@ func (app *Application) Initialize() error {
 		time.Duration(app.Config.RefreshTokenLifetime)*time.Hour,
 		tokenBlacklist,
 	)
+	objectStorage, err := app.newObjectStorage()
+	if err != nil {
+		logger.GetLogger().Fatalf("failed to init object storage: %v", err)
+	}
+	notifier := notification.NewLogNotifier()
+	keyring, err := app.newKeyring()
+	if err != nil {
+		logger.GetLogger().Fatalf("failed to init encryption keyring: %v", err)
+	}
+	// Domain events; features subscribe their handlers here, e.g.
+	// eventbus.On(app.events, func(ctx context.Context, e event.UserRegistered) error { ... })
+	app.events = eventbus.NewBus()
 
 	// Layer 2 — Repositories (depend on db)
-	userRepo := persistence.NewUserRepository(app.DB)
+	userRepo := persistence.NewUserRepository(app.DB, keyring)
 	txManager := persistence.NewTxManager(app.DB)
+	usernameHistoryRepo := persistence.NewUsernameHistoryRepository(app.DB)
+	dataExportRepo := persistence.NewDataExportRepository(app.DB)
+	emailChangeRepo := persistence.NewEmailChangeRepository(app.DB, keyring)
+	preferenceRepo := persistence.NewPreferenceRepository(app.DB)
+	outboxRepo := persistence.NewOutboxRepository(app.DB)
+	// Use cases publish events into the outbox, in their transaction; the
+	// outbox relay job delivers them to the event bus
+	eventPublisher := outbox.NewPublisher(outboxRepo)
 
 	// Layer 3 — Use Cases (depend on interfaces, not concrete types)
-	authUseCase := usecase.NewAuthUseCase(userRepo, authenticator, txManager, app.Config)
-	userUseCase := usecase.NewUserUseCase(userRepo)
+	authUseCase := usecase.NewAuthUseCase(userRepo, usernameHistoryRepo, authenticator, txManager, eventPublisher, app.Config)
+	userUseCase := usecase.NewUserUseCase(userRepo, usernameHistoryRepo, emailChangeRepo, preferenceRepo, dataExportRepo, txManager, objectStorage, eventPublisher, app.Config)
+	// Every subsystem that stores personal data registers an exporter here.
+	// There are no sessions (authentication is stateless JWT) nor an audit
+	// log to export yet; their subsystems must register an exporter when added
+	exporters := usecase.NewExporterRegistry(
+		usecase.NewProfileExporter(),
+		usecase.NewUploadedFilesExporter(objectStorage),
+		usecase.NewUsernameHistoryExporter(usernameHistoryRepo),
+		usecase.NewEmailChangesExporter(emailChangeRepo),
+		usecase.NewPreferencesExporter(preferenceRepo),
+	)
+	dataExportUseCase := usecase.NewDataExportUseCase(dataExportRepo, userRepo, objectStorage, notifier, exporters, app.Config)
+	emailChangeUseCase := usecase.NewEmailChangeUseCase(emailChangeRepo, userRepo, txManager, notifier, eventPublisher, app.Config)
+	// The preference schema; features add their own keys here
+	preferences := usecase.NewPreferenceRegistry(usecase.BuiltinPreferences()...)
+	preferenceUseCase := usecase.NewPreferenceUseCase(preferenceRepo, userRepo, txManager, preferences)
 
 	// Layer 4 — Controllers (depend on use case interfaces)
 	authCtrl := controller.NewAuthController(authUseCase)
 	userCtrl := controller.NewUserController(userUseCase)
-	infraCtrl := controller.NewInfraController(app.DB, app.Config)
+	dataExportCtrl := controller.NewDataExportController(dataExportUseCase)
+	emailChangeCtrl := controller.NewEmailChangeController(emailChangeUseCase)
+	preferenceCtrl := controller.NewPreferenceController(preferenceUseCase)
+	infraCtrl := controller.NewInfraController(app.DB, app.Config, app.started.Load)
 
 	// Set up routes — Router holds shared deps, each register method receives its own controller
 	router := route.NewRouter(authenticator, app.Config)
-	router.Setup(app.Router, authCtrl, userCtrl, infraCtrl)
+	router.Setup(app.Router, authCtrl, userCtrl, dataExportCtrl, emailChangeCtrl, preferenceCtrl, infraCtrl)
+
+	// Background jobs — started by Run, stopped with the server
+	app.jobs = append(app.jobs,
+		job.NewPeriodic("db-health", time.Duration(app.Config.DBHealthCheckSeconds)*time.Second,
+			database.NewConnectionMonitor(app.DB, "").Check),
+		job.NewAccountPurgeJob(userUseCase, time.Duration(app.Config.AccountPurgeIntervalMinutes)*time.Minute),
+		job.NewDataExportJob(dataExportUseCase, time.Duration(app.Config.DataExportPollSeconds)*time.Second),
+		job.NewPeriodic("outbox-relay", time.Duration(app.Config.OutboxPollSeconds)*time.Second,
+			outbox.NewRelay(outboxRepo, app.events, outbox.RelayConfig{
+				MaxAttempts: app.Config.OutboxMaxAttempts,
+				RetryBase:   time.Duration(app.Config.OutboxRetryBaseSeconds) * time.Second,
+				RetryMax:    time.Duration(app.Config.OutboxRetryMaxSeconds) * time.Second,
+				Retention:   time.Duration(app.Config.OutboxRetentionHours) * time.Hour,
+			}).Run),
+		job.NewPeriodic("email-key-rotation", time.Duration(app.Config.EncryptionRotationIntervalMinutes)*time.Minute,
+			persistence.NewEmailKeyRotation(app.DB, keyring, 0).Run),
+	)
+	if replicated, ok := app.DB.(*database.ReplicatedDB); ok {
+		app.jobs = append(app.jobs, job.NewPeriodic("replica-health",
+			time.Duration(app.Config.DBReplicaHealthCheckSeconds)*time.Second, replicated.CheckHealth))
+	}
 	return nil
 }
 
+// connectDatabase creates the database selected by DB_TYPE, unless
+// Initialize already did, and connects it, retrying per the DB_CONNECT_*
+// settings until ctx is done.
+func (app *Application) connectDatabase(ctx context.Context) error {
+	if app.DB == nil {
+		db, err := app.newDatabase()
+		if err != nil {
+			return err
+		}
+		app.DB = db
+	}
+	dbConfig, err := app.databaseConfig()
+	if err != nil {
+		return err
+	}
+	return database.ConnectWithRetry(ctx, app.DB, dbConfig, database.RetryPolicy{
+		MaxAttempts: app.Config.DBConnectMaxAttempts,
+		BaseDelay:   time.Duration(app.Config.DBConnectRetryBaseMS) * time.Millisecond,
+		MaxDelay:    time.Duration(app.Config.DBConnectRetryMaxMS) * time.Millisecond,
+	})
+}
+
+// databaseConfig returns the connection settings of the primary database,
+// taken from DB_URL when it is set.
+func (app *Application) databaseConfig() (*database.Config, error) {
+	params, err := app.Config.DriverParams()
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_PARAMS: %w", err)
+	}
+	config := &database.Config{
+		Host:     app.Config.DBHost,
+		Port:     app.Config.DBPort,
+		User:     app.Config.DBUser,
+		Password: app.Config.DBPassword,
+		DBName:   app.Config.DBName,
+		SSLMode:  app.Config.DBSSLMode,
+		TLS: database.TLSConfig{
+			CAFile:     app.Config.DBSSLRootCert,
+			CertFile:   app.Config.DBSSLCert,
+			KeyFile:    app.Config.DBSSLKey,
+			ServerName: app.Config.DBSSLServerName,
+		},
+		ApplicationName:        app.Config.DBApplicationName,
+		StatementTimeout:       app.Config.DBStatementTimeout(),
+		ConnectTimeout:         app.Config.DBConnectTimeout(),
+		Params:                 params,
+		MaxIdleConns:           app.Config.DBMaxIdleConns,
+		MaxOpenConns:           app.Config.DBMaxOpenConns,
+		ConnMaxLifetimeMinutes: app.Config.DBConnMaxLifetimeMinutes,
+		ConnMaxIdleTimeMinutes: app.Config.DBConnMaxIdleTimeMinutes,
+		SlowQueryThreshold:     app.Config.SlowQueryThreshold(),
+		LogSQLParams:           app.Config.DBLogSQLParams,
+	}
+	if app.Config.DBURL != "" {
+		// URL 中的参数覆盖 DB_PARAMS 和 DB_SSL_* 中的同名设置
+		if err := database.ParseURL(app.Config.DBURL, config); err != nil {
+			return nil, fmt.Errorf("invalid DB_URL: %w", err)
+		}
+	}
+	return config, nil
+}
+
+// newDatabase creates, without connecting it, the database selected by
+// DB_TYPE, with the configured read replicas.
+func (app *Application) newDatabase() (database.Database, error) {
+	var newDB func() database.Database
+	switch app.Config.DBType {
+	case "postgres":
+		newDB = postgres.NewPostgresDB
+	case "mysql":
+		newDB = mysql.NewMySQLDB
+	case "sqlite":
+		newDB = sqlite.NewSQLiteDB
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", app.Config.DBType)
+	}
+
+	if dsns := app.Config.ReplicaDSNs(); len(dsns) > 0 {
+		return database.NewReplicatedDB(newDB(), newDB, dsns), nil
+	}
+	return newDB(), nil
+}
+
+// start connects the database and brings its schema up to date, within
+// DB_STARTUP_TIMEOUT_SECONDS, then marks the application as started.
+func (app *Application) start(ctx context.Context) error {
+	ctx, cancel := context.WithTimeoutCause(ctx, app.Config.DBStartupTimeout(), errors.New("startup deadline exceeded"))
+	defer cancel()
+	if err := app.connectDatabase(ctx); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := app.migrateSchema(ctx); err != nil {
+		return fmt.Errorf("failed to migrate database schema: %w", err)
+	}
+	app.started.Store(true)
+	return nil
+}
+
+// migrateSchema brings the schema up to date on startup if DB_MIGRATE_ON_START
+// is set. Otherwise it refuses to start on an outdated schema, leaving the
+// migration to an explicit `migrate up` (e.g. a deploy step).
+//
+// A migration held back until a background job has backfilled data does not
+// stop the server, which runs that job.
+func (app *Application) migrateSchema(ctx context.Context) error {
+	migrator, err := persistence.NewMigrator(app.DB)
+	if err != nil {
+		return err
+	}
+
+	if app.Config.DBMigrateOnStart {
+		// 多个副本同时启动时由迁移锁串行化，每个迁移只会执行一次
+		applied, err := migrator.Up(ctx, 0)
+		for _, m := range applied {
+			logger.GetLogger().Infof("Applied migration %s", m)
+		}
+		if errors.Is(err, migrate.ErrBlocked) {
+			logger.GetLogger().Warnf("Deferred migration: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	if err := migrator.Check(ctx); err != nil {
+		if errors.Is(err, migrate.ErrBlocked) {
+			logger.GetLogger().Warnf("Deferred migration: %v", err)
+			return nil
+		}
+		if errors.Is(err, migrate.ErrPending) {
+			return fmt.Errorf("%w; run `migrate up` first", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// newKeyring builds the keyring personal data is encrypted with from
+// ENCRYPTION_KEYS, ENCRYPTION_ACTIVE_KEY and BLIND_INDEX_SECRET.
+func (app *Application) newKeyring() (*fieldcrypt.Keyring, error) {
+	keys, err := fieldcrypt.ParseKeys(app.Config.EncryptionKeys)
+	if err != nil {
+		return nil, err
+	}
+	return fieldcrypt.NewKeyring(keys, app.Config.EncryptionActiveKey, []byte(app.Config.BlindIndexSecret))
+}
+
+// newObjectStorage builds the object storage backend selected by STORAGE_DRIVER.
+func (app *Application) newObjectStorage() (gateway.ObjectStorage, error) {
+	switch app.Config.StorageDriver {
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:      app.Config.MinioEndpoint,
+			AccessKey:     app.Config.MinioAccessKey,
+			SecretKey:     app.Config.MinioSecretKey,
+			Bucket:        app.Config.MinioBucket,
+			Region:        app.Config.MinioRegion,
+			UseSSL:        app.Config.MinioUseSSL,
+			PublicBaseURL: app.Config.StoragePublicURL,
+		})
+	default:
+		return storage.NewLocalStorage(app.Config.StorageLocalDir, app.Config.StoragePublicURL)
+	}
+}
+
 // shutdownGracePeriod is the maximum time to wait for in-flight requests
 // to complete during graceful shutdown.
 const shutdownGracePeriod = 30 * time.Second
 
-// Run starts the HTTP server and blocks until ctx is canceled.
+// Run starts the HTTP server, then connects the database and starts the
+// background jobs, and blocks until ctx is canceled. It returns an error if
+// the database cannot be connected and migrated within the startup deadline.
 // When the parent context is canceled (e.g. via signal.NotifyContext),
 // it automatically performs graceful shutdown: stops accepting new connections,
 // waits for in-flight requests to drain, and closes the database.
@@ -183,6 +407,8 @@ func (app *Application) Run(ctx context.Context) error {
 	}
 
 	// Start the HTTP server in a goroutine so we can listen for ctx cancellation.
+	// It listens before the database is connected, so that probes can tell
+	// a starting instance from a dead one.
 	serverErr := make(chan error, 1)
 	go func() {
 		log.Infof("HTTP server listening on %s", app.Config.ServerAddress())
@@ -192,10 +418,39 @@ func (app *Application) Run(ctx context.Context) error {
 		close(serverErr)
 	}()
 
-	// Block until we receive a shutdown signal or the server fails to start.
+	// Connect the database, then start background jobs; they stop when ctx is
+	// canceled or the server fails. Jobs read rows and then act on them, so
+	// they never read from replicas.
+	jobsCtx, stopJobs := context.WithCancel(repository.WithPrimary(ctx))
+	defer stopJobs()
+	startErr := make(chan error, 1)
+	app.jobsWG.Go(func() {
+		if err := app.start(jobsCtx); err != nil {
+			if jobsCtx.Err() == nil {
+				startErr <- err
+			}
+			return
+		}
+		log.Info("Database ready, accepting requests")
+		for _, j := range app.jobs {
+			log.Infof("Starting background job %s", j)
+			app.jobsWG.Go(func() { j.Run(jobsCtx) })
+		}
+	})
+
+	// Block until we receive a shutdown signal, the server fails to start or
+	// the database cannot be reached in time.
 	select {
 	case err := <-serverErr:
 		// Server failed to start (e.g. port already in use). Clean up and return.
+		stopJobs()
+		app.jobsWG.Wait()
+		app.shutdown()
+		return err
+	case err := <-startErr:
+		// 只有探针与指标在服务，直接关闭；退出后由容器编排重启
+		_ = app.httpServer.Close()
+		app.jobsWG.Wait()
 		app.shutdown()
 		return err
 	case <-ctx.Done():
@@ -213,7 +468,11 @@ func (app *Application) Run(ctx context.Context) error {
 		log.Info("HTTP server drained successfully")
 	}
 
-	// 2. Close infrastructure resources (database, etc.).
+	// 2. Wait for background jobs and event handlers to finish their current run.
+	app.jobsWG.Wait()
+	app.events.Wait()
+
+	// 3. Close infrastructure resources (database, etc.).
 	app.shutdown()
 
 	log.Info("Application stopped")
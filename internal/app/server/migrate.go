@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/migrate"
+)
+
+// MigrateUsage describes the migrate subcommand.
+const MigrateUsage = `usage: migrate <command>
+
+commands:
+  up [N]     apply all pending migrations, or only the next N
+  down [N]   revert the last applied migration, or the last N
+  redo       revert and re-apply the last applied migration
+  status     list migrations and whether they are applied`
+
+// RunMigrate runs the migrate subcommand given by args against the configured
+// database and reports what it did to out. It does not start the server.
+func (app *Application) RunMigrate(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", MigrateUsage)
+	}
+	command, n, err := parseMigrateArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// 与服务启动相同，等待数据库就绪（如 docker compose 中同时启动的 Postgres）
+	connectCtx, cancel := context.WithTimeout(ctx, app.Config.DBStartupTimeout())
+	defer cancel()
+	if err := app.connectDatabase(connectCtx); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer app.shutdown()
+	migrator, err := persistence.NewMigrator(app.DB)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx, n)
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied  %s\n", m)
+		}
+		if errors.Is(err, migrate.ErrBlocked) {
+			// 被挡住的迁移会在之后的 `migrate up` 或启动时执行，不算失败
+			fmt.Fprintf(out, "deferred %v\n", err)
+			return nil
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, n)
+		for _, m := range reverted {
+			fmt.Fprintf(out, "reverted %s\n", m)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Fprintln(out, "no applied migrations")
+		}
+		return err
+	case "redo":
+		redone, err := migrator.Redo(ctx)
+		if redone != nil {
+			fmt.Fprintf(out, "redone   %s\n", redone)
+		} else if err == nil {
+			fmt.Fprintln(out, "no applied migrations")
+		}
+		return err
+	default: // status
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return writeMigrationStatus(out, statuses)
+	}
+}
+
+// parseMigrateArgs validates the subcommand and its optional count.
+func parseMigrateArgs(args []string) (command string, n int, err error) {
+	command = args[0]
+	switch command {
+	case "up", "down":
+		if len(args) > 2 {
+			return "", 0, fmt.Errorf("too many arguments\n%s", MigrateUsage)
+		}
+		if len(args) == 2 {
+			n, err = strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return "", 0, fmt.Errorf("N must be a positive integer, got %q", args[1])
+			}
+		}
+	case "redo", "status":
+		if len(args) > 1 {
+			return "", 0, fmt.Errorf("too many arguments\n%s", MigrateUsage)
+		}
+	default:
+		return "", 0, fmt.Errorf("unknown command %q\n%s", command, MigrateUsage)
+	}
+	return command, n, nil
+}
+
+// writeMigrationStatus prints statuses as a table.
+func writeMigrationStatus(out io.Writer, statuses []migrate.Status) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		state, appliedAt := "pending", "-"
+		if s.Applied {
+			state, appliedAt = "applied", s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			if s.Modified {
+				// 已执行的迁移文件被改动，其他命令会拒绝运行
+				state = "MODIFIED"
+			}
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/migrate"
+)
+
+func TestParseMigrateArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantCommand string
+		wantN       int
+		wantErr     bool
+	}{
+		{"up all", []string{"up"}, "up", 0, false},
+		{"up n", []string{"up", "2"}, "up", 2, false},
+		{"down default", []string{"down"}, "down", 0, false},
+		{"redo", []string{"redo"}, "redo", 0, false},
+		{"status", []string{"status"}, "status", 0, false},
+		{"non-positive n", []string{"down", "0"}, "", 0, true},
+		{"non-numeric n", []string{"up", "all"}, "", 0, true},
+		{"extra arguments", []string{"redo", "1"}, "", 0, true},
+		{"unknown command", []string{"force", "3"}, "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, n, err := parseMigrateArgs(tt.args)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCommand, command)
+			assert.Equal(t, tt.wantN, n)
+		})
+	}
+}
+
+func TestWriteMigrationStatus(t *testing.T) {
+	appliedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+
+	err := writeMigrationStatus(&out, []migrate.Status{
+		{Version: 1, Name: "create_users", Applied: true, AppliedAt: appliedAt},
+		{Version: 2, Name: "create_posts", Applied: true, AppliedAt: appliedAt, Modified: true},
+		{Version: 3, Name: "add_users_phone"},
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "000001   create_users     applied   2026-03-01 12:00:00")
+	assert.Contains(t, out.String(), "000002   create_posts     MODIFIED")
+	assert.Contains(t, out.String(), "000003   add_users_phone  pending   -")
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/migrate"
+	snowflakeutils "github.com/kirklin/boot-backend-go-clean/pkg/utils/snowflake"
+)
+
+// SeedUsage describes the seed subcommand.
+const SeedUsage = `usage: seed <file>...
+
+Loads the fixtures of each YAML or JSON file into the database. Records that
+already exist (users by username) are left untouched, so seeding is safe to
+repeat. Only allowed when APP_ENVIRONMENT is development, local or test.`
+
+// seedEnvironments are the values of APP_ENVIRONMENT seed runs in. It is an
+// allowlist so that a misspelled production environment is refused too.
+var seedEnvironments = []string{"development", "local", "test"}
+
+// Fixtures is the content of a fixture file. Sections are loaded in the
+// order of the fields; add new entities as new sections.
+type Fixtures struct {
+	Users []UserFixture `yaml:"users"`
+}
+
+// UserFixture describes a user to seed. Password is the plaintext password,
+// hashed on load.
+type UserFixture struct {
+	Username    string  `yaml:"username"`
+	Email       string  `yaml:"email"`
+	Password    string  `yaml:"password"`
+	DisplayName *string `yaml:"display_name"`
+	Bio         *string `yaml:"bio"`
+	Locale      *string `yaml:"locale"`
+	Timezone    *string `yaml:"timezone"`
+	Website     *string `yaml:"website"`
+}
+
+// SeedResult counts the records a seed created and skipped.
+type SeedResult struct {
+	Created int
+	Skipped int
+}
+
+// RunSeed loads the fixture files given by args into the configured database
+// and reports what it did to out. It does not start the server, and refuses
+// to run outside the development environments or on an outdated schema.
+func (app *Application) RunSeed(ctx context.Context, args []string, out io.Writer) error {
+	if !slices.Contains(seedEnvironments, app.Config.Environment) {
+		return fmt.Errorf("seed is disabled in environment %q (allowed: %v)", app.Config.Environment, seedEnvironments)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("missing fixture file\n%s", SeedUsage)
+	}
+	// 先解析全部文件，格式错误时不连接数据库
+	files := make([]*Fixtures, 0, len(args))
+	for _, path := range args {
+		fixtures, err := LoadFixtures(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, fixtures)
+	}
+
+	if err := snowflakeutils.InitNode(&snowflakeutils.Config{
+		Epoch:       app.Config.SnowflakeEpoch,
+		MachineBits: app.Config.SnowflakeMachineBits,
+		StepBits:    app.Config.SnowflakeStepBits,
+	}); err != nil {
+		return fmt.Errorf("failed to init snowflake node: %w", err)
+	}
+	keyring, err := app.newKeyring()
+	if err != nil {
+		return fmt.Errorf("failed to init encryption keyring: %w", err)
+	}
+	// 与服务启动相同，等待数据库就绪（如 docker compose 中同时启动的 Postgres）
+	connectCtx, cancel := context.WithTimeout(ctx, app.Config.DBStartupTimeout())
+	defer cancel()
+	if err := app.connectDatabase(connectCtx); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer app.shutdown()
+	migrator, err := persistence.NewMigrator(app.DB)
+	if err != nil {
+		return err
+	}
+	// 等待后台任务补齐数据的迁移不影响写入 fixture
+	if err := migrator.Check(ctx); err != nil && !errors.Is(err, migrate.ErrBlocked) {
+		return fmt.Errorf("%w; run `migrate up` first", err)
+	}
+
+	userRepo := persistence.NewUserRepository(app.DB, keyring)
+	usernameHistoryRepo := persistence.NewUsernameHistoryRepository(app.DB)
+	txManager := persistence.NewTxManager(app.DB)
+	for i, fixtures := range files {
+		result, err := SeedUsers(ctx, userRepo, usernameHistoryRepo, txManager, app.Config.UsernameReservationPeriod(), fixtures.Users)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[i], err)
+		}
+		fmt.Fprintf(out, "%s: users created %d, skipped %d\n", args[i], result.Created, result.Skipped)
+	}
+	return nil
+}
+
+// LoadFixtures reads a fixture file. JSON is parsed as YAML, of which it is
+// a subset. Unknown keys are rejected so that typos do not go unnoticed.
+func LoadFixtures(path string) (*Fixtures, error) {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("%s: fixture files must be .yaml, .yml or .json", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures Fixtures
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixtures); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &fixtures, nil
+}
+
+// SeedUsers creates the users that do not exist yet, in one transaction:
+// either every missing user is created or none is. A user awaiting purge
+// after deleting their account still exists. Users are validated like
+// registrations, including the reservation of recently released usernames,
+// but no UserRegistered event is published.
+func SeedUsers(ctx context.Context, users repository.UserRepository, history repository.UsernameHistoryRepository, txManager repository.TxManager, reservationPeriod time.Duration, fixtures []UserFixture) (SeedResult, error) {
+	// 校验与 bcrypt 在事务外完成，避免长时间占用事务
+	candidates := make([]*entity.User, 0, len(fixtures))
+	for i, f := range fixtures {
+		user := &entity.User{
+			Username:    f.Username,
+			Email:       f.Email,
+			Password:    f.Password,
+			DisplayName: f.DisplayName,
+			Bio:         f.Bio,
+			Locale:      f.Locale,
+			Timezone:    f.Timezone,
+			Website:     f.Website,
+		}
+		// 资料字段按资料修改接口的规则校验并规范化
+		profile := entity.UpdateProfileRequest{
+			DisplayName: entity.PatchField[string]{Value: user.DisplayName},
+			Bio:         entity.PatchField[string]{Value: user.Bio},
+			Locale:      entity.PatchField[string]{Value: user.Locale},
+			Timezone:    entity.PatchField[string]{Value: user.Timezone},
+			Website:     entity.PatchField[string]{Value: user.Website},
+		}
+		if err := errors.Join(user.Validate(), profile.Validate()); err != nil {
+			return SeedResult{}, fmt.Errorf("user #%d (%s): %w", i+1, f.Username, err)
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(f.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return SeedResult{}, err
+		}
+		user.Password = string(hashed)
+		candidates = append(candidates, user)
+	}
+
+	var result SeedResult
+	reservedSince := time.Now().Add(-reservationPeriod)
+	err := txManager.WithTx(ctx, func(txCtx context.Context) error {
+		result = SeedResult{}
+		for _, user := range candidates {
+			_, err := users.FindByUsername(txCtx, user.Username)
+			if errors.Is(err, domainerrors.ErrUserNotFound) {
+				// 已注销、等待清除的用户仍占用用户名
+				_, err = users.FindDeletedByUsername(txCtx, user.Username)
+			}
+			if err == nil {
+				result.Skipped++
+				continue
+			}
+			if !errors.Is(err, domainerrors.ErrUserNotFound) {
+				return err
+			}
+			// 其他用户刚释放的用户名仍在保留期内，与注册一样不能使用
+			reserved, err := history.IsReserved(txCtx, user.Username, reservedSince, 0)
+			if err != nil {
+				return err
+			}
+			if reserved {
+				return fmt.Errorf("user %s: %w", user.Username, domainerrors.ErrUsernameExists)
+			}
+			// 邮箱被其他用户名占用时 Create 返回 ErrEmailExists，整个文件回滚
+			if err := users.Create(txCtx, user); err != nil {
+				return fmt.Errorf("user %s: %w", user.Username, err)
+			}
+			result.Created++
+		}
+		return nil
+	})
+	if err != nil {
+		return SeedResult{}, err
+	}
+	return result, nil
+}
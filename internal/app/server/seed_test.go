@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence"
+	"github.com/kirklin/boot-backend-go-clean/pkg/configs"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/fieldcrypt"
+	snowflakeutils "github.com/kirklin/boot-backend-go-clean/pkg/utils/snowflake"
+)
+
+// writeFixture writes content to a file named name in a temporary directory.
+func writeFixture(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFixtures(t *testing.T) {
+	fromYAML, err := LoadFixtures(writeFixture(t, "users.yaml", `
+users:
+  - username: kirk
+    email: kirk@example.com
+    password: password123
+    display_name: James
+`))
+	require.NoError(t, err)
+	require.Len(t, fromYAML.Users, 1)
+	assert.Equal(t, "kirk", fromYAML.Users[0].Username)
+	require.NotNil(t, fromYAML.Users[0].DisplayName)
+	assert.Equal(t, "James", *fromYAML.Users[0].DisplayName)
+
+	fromJSON, err := LoadFixtures(writeFixture(t, "users.json",
+		`{"users": [{"username": "kirk", "email": "kirk@example.com", "password": "password123"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, fromYAML.Users[0].Email, fromJSON.Users[0].Email)
+
+	empty, err := LoadFixtures(writeFixture(t, "empty.yaml", ""))
+	require.NoError(t, err)
+	assert.Empty(t, empty.Users)
+
+	_, err = LoadFixtures(writeFixture(t, "typo.yaml", "users:\n  - usernme: kirk\n"))
+	assert.Error(t, err, "unknown keys are rejected")
+	_, err = LoadFixtures(writeFixture(t, "users.txt", "users: []"))
+	assert.Error(t, err)
+	_, err = LoadFixtures(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestSeedUsers(t *testing.T) {
+	require.NoError(t, snowflakeutils.InitNode(&snowflakeutils.Config{}))
+	db := sqlite.NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	t.Cleanup(func() { _ = db.Close() })
+	migrator, err := persistence.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background(), 0)
+	require.NoError(t, err)
+	keyring, err := fieldcrypt.NewKeyring(map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, "k1", []byte("index-secret"))
+	require.NoError(t, err)
+	users := persistence.NewUserRepository(db, keyring)
+	history := persistence.NewUsernameHistoryRepository(db)
+	txManager := persistence.NewTxManager(db)
+	ctx := context.Background()
+	seed := func(fixtures []UserFixture) (SeedResult, error) {
+		return SeedUsers(ctx, users, history, txManager, 30*24*time.Hour, fixtures)
+	}
+
+	locale := "zh-hans-cn"
+	fixtures := []UserFixture{
+		{Username: "kirk", Email: "Kirk@Example.com", Password: "password123", Locale: &locale},
+		{Username: "spock", Email: "spock@example.com", Password: "password456"},
+	}
+	result, err := seed(fixtures)
+	require.NoError(t, err)
+	assert.Equal(t, SeedResult{Created: 2}, result)
+
+	kirk, err := users.FindByUsername(ctx, "kirk")
+	require.NoError(t, err)
+	assert.Equal(t, "kirk@example.com", kirk.Email, "normalized like a registration")
+	assert.NotEqual(t, "password123", kirk.Password, "hashed on load")
+	require.NotNil(t, kirk.Locale)
+	assert.Equal(t, "zh-Hans-CN", *kirk.Locale)
+
+	// 再次执行时跳过已存在的用户
+	result, err = seed(fixtures)
+	require.NoError(t, err)
+	assert.Equal(t, SeedResult{Skipped: 2}, result)
+
+	// 已注销、等待清除的用户同样跳过，而不是在 Create 时因用户名冲突失败
+	require.NoError(t, users.SoftDelete(ctx, kirk.ID))
+	result, err = seed(fixtures)
+	require.NoError(t, err)
+	assert.Equal(t, SeedResult{Skipped: 2}, result)
+
+	// 保留期内的用户名与注册一样不能使用
+	require.NoError(t, history.Create(ctx, &entity.UsernameChange{UserID: kirk.ID, OldUsername: "jim", NewUsername: "kirk", ChangedAt: time.Now()}))
+	_, err = seed([]UserFixture{{Username: "jim", Email: "jim@example.com", Password: "password789"}})
+	assert.ErrorIs(t, err, domainerrors.ErrUsernameExists)
+
+	// 校验失败时不写入任何用户
+	_, err = seed([]UserFixture{
+		{Username: "mccoy", Email: "mccoy@example.com", Password: "password789"},
+		{Username: "scotty", Email: "not-an-email", Password: "password789"},
+	})
+	assert.Error(t, err)
+	// 邮箱被占用时整个文件回滚
+	_, err = seed([]UserFixture{
+		{Username: "mccoy", Email: "mccoy@example.com", Password: "password789"},
+		{Username: "james", Email: "kirk@example.com", Password: "password789"},
+	})
+	assert.ErrorIs(t, err, domainerrors.ErrEmailExists)
+	_, err = users.FindByUsername(ctx, "mccoy")
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+
+	// 仓库自带的开发数据保持可用
+	dev, err := LoadFixtures("../../../scripts/fixtures/dev.yaml")
+	require.NoError(t, err)
+	result, err = seed(dev.Users)
+	require.NoError(t, err)
+	assert.Equal(t, len(dev.Users), result.Created)
+}
+
+func TestRunSeed_EnvironmentGuard(t *testing.T) {
+	path := writeFixture(t, "users.yaml", "users: []")
+	for _, env := range []string{"production", "prod", "staging", ""} {
+		app := &Application{Config: &configs.AppConfig{Environment: env}}
+		err := app.RunSeed(context.Background(), []string{path}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "seed is disabled", env)
+		assert.Nil(t, app.DB, "refused before connecting")
+	}
+
+	app := &Application{Config: &configs.AppConfig{Environment: "development"}}
+	assert.ErrorContains(t, app.RunSeed(context.Background(), nil, &bytes.Buffer{}), "missing fixture file")
+}
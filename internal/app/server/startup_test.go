@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/configs"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+)
+
+func newStartupTestApp(dbName string) *Application {
+	return &Application{Config: &configs.AppConfig{
+		DBType:                  "sqlite",
+		DBName:                  dbName,
+		DBMigrateOnStart:        true,
+		DBConnectRetryBaseMS:    1,
+		DBConnectRetryMaxMS:     1,
+		DBConnectMaxAttempts:    3,
+		DBStartupTimeoutSeconds: 5,
+	}}
+}
+
+func TestApplication_Start(t *testing.T) {
+	app := newStartupTestApp(sqlite.MemoryDBName)
+	require.NoError(t, app.start(context.Background()))
+	t.Cleanup(app.shutdown)
+	assert.True(t, app.started.Load())
+
+	// 数据库不可达：用尽重试后返回错误，保持未启动状态
+	app = newStartupTestApp(filepath.Join(t.TempDir(), "missing", "app.db"))
+	err := app.start(context.Background())
+	assert.ErrorContains(t, err, "giving up after 3 attempts")
+	assert.False(t, app.started.Load())
+	app.shutdown() // 从未连接成功的数据库也能关闭
+}
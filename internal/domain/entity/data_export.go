@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// DataExportStatus is the lifecycle state of a personal-data export.
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"    // 已受理，等待后台任务处理
+	DataExportProcessing DataExportStatus = "processing" // 正在生成归档
+	DataExportReady      DataExportStatus = "ready"      // 归档已生成，可在 ExpiresAt 前下载
+	DataExportFailed     DataExportStatus = "failed"
+)
+
+// DataExport is a request to package everything held about a user into a
+// downloadable archive. Archives are built asynchronously and only kept
+// until ExpiresAt.
+type DataExport struct {
+	ID          int64            `json:"id,string"`
+	UserID      int64            `json:"user_id,string"`
+	Status      DataExportStatus `json:"status"`
+	ObjectKey   *string          `json:"-"` // 归档在对象存储中的 key，生成成功后才有值
+	Size        int64            `json:"size,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   *time.Time       `json:"updated_at,omitempty"`
+	Version     int64            `json:"-"`
+}
+
+// Active reports whether the export is still queued or being built.
+func (e *DataExport) Active() bool {
+	return e.Status == DataExportPending || e.Status == DataExportProcessing
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// EmailChangeStatus is the lifecycle state of an email change.
+type EmailChangeStatus string
+
+const (
+	EmailChangePending   EmailChangeStatus = "pending"   // 等待新邮箱确认，账号邮箱尚未改变
+	EmailChangeConfirmed EmailChangeStatus = "confirmed" // 已生效，旧邮箱仍可在撤销期内撤销
+	EmailChangeCancelled EmailChangeStatus = "cancelled" // 确认前被撤销，或被同一用户更新的申请取代
+	EmailChangeReverted  EmailChangeStatus = "reverted"  // 生效后被旧邮箱撤销，账号邮箱已恢复
+)
+
+// EmailChange is a request to move an account to a new email address. The
+// account keeps its current address until the new one is confirmed through
+// the link sent to it; the previous address gets a link to undo the change.
+type EmailChange struct {
+	ID          int64             `json:"id,string"`
+	UserID      int64             `json:"user_id,string"`
+	OldEmail    string            `json:"old_email"`
+	NewEmail    string            `json:"new_email"`
+	Status      EmailChangeStatus `json:"status"`
+	ExpiresAt   time.Time         `json:"expires_at"` // 确认链接的截止时间
+	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+	Version     int64             `json:"-"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+// Notification is a message sent to a user outside of the API, e.g. by email.
+type Notification struct {
+	UserID  int64
+	To      string // 收件地址
+	Subject string
+	Body    string
+}
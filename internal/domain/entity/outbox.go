@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// OutboxStatus is the delivery state of an outbox message.
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"   // 等待投递，或投递失败后等待重试
+	OutboxPublished OutboxStatus = "published" // 已投递
+	OutboxDead      OutboxStatus = "dead"      // 重试次数用尽，需要人工处理
+)
+
+// OutboxMessage is a domain event stored in the same transaction as the
+// change it describes, and delivered from there by a relay. It survives
+// crashes between the commit and the delivery.
+type OutboxMessage struct {
+	ID        int64
+	EventName string
+	Payload   []byte // 事件的 JSON 编码
+	// IdempotencyKey is unique per message and stays the same across
+	// redeliveries, so consumers can discard duplicates.
+	IdempotencyKey string
+	Status         OutboxStatus
+	Attempts       int       // 已尝试投递的次数
+	NextAttemptAt  time.Time // 最早的（下一次）投递时间
+	LastError      *string
+	PublishedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      *time.Time
+}
+
+// OutboxBacklog summarizes the messages still waiting to be delivered.
+type OutboxBacklog struct {
+	Pending       int64
+	OldestPending *time.Time // CreatedAt of the oldest pending message
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// PatchField captures one member of a JSON merge-patch document (RFC 7396).
+//
+// It distinguishes the three states a member can be in:
+//
+//   - absent:  the key was not sent; the stored value must stay unchanged (Set == false)
+//   - null:    the key was sent as null; the stored value must be cleared (Set == true, Value == nil)
+//   - present: the key was sent with a value; the stored value is replaced (Set == true, Value != nil)
+//
+// encoding/json only calls UnmarshalJSON for keys that are present, which is
+// what lets an untouched PatchField report Set == false.
+type PatchField[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		f.Value = nil
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	f.Value = &v
+	return nil
+}
+
+// IsNull reports whether the member was explicitly sent as null.
+func (f PatchField[T]) IsNull() bool {
+	return f.Set && f.Value == nil
+}
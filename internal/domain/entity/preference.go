@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// PreferenceType is the value type of a user preference.
+type PreferenceType string
+
+const (
+	PreferenceBool   PreferenceType = "bool"
+	PreferenceString PreferenceType = "string"
+	PreferenceInt    PreferenceType = "int"
+)
+
+// maxPreferenceStringLength bounds string preference values, counted in characters (runes).
+const maxPreferenceStringLength = 256
+
+// preferenceKeyRegex matches dotted lower-case keys such as "notifications.email".
+var preferenceKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// PreferenceDefinition is the server-side schema of one preference key.
+// Values sent by clients are checked against it before they are stored,
+// and Default is returned while a user has not set the key.
+type PreferenceDefinition struct {
+	Key     string
+	Type    PreferenceType
+	Default any      // bool、string 或 int64，必须与 Type 一致
+	Allowed []string // 仅对 string 生效：非空时值必须是其中之一
+
+	// Validate optionally applies further checks to a decoded value.
+	Validate func(value any) error
+}
+
+// Preferences maps preference keys to their effective values.
+type Preferences map[string]any
+
+// UpdatePreferencesRequest is a JSON merge-patch of the caller's preferences.
+// A key sent as null is reset to its default.
+type UpdatePreferencesRequest map[string]json.RawMessage
+
+// CheckDefinition reports whether d is a usable schema entry: the key is
+// well-formed and the default is a valid value of the declared type.
+func (d *PreferenceDefinition) CheckDefinition() error {
+	if len(d.Key) > 64 || !preferenceKeyRegex.MatchString(d.Key) {
+		return fmt.Errorf("preference key %q must be dotted lower-case identifiers", d.Key)
+	}
+	if d.Type != PreferenceString && len(d.Allowed) > 0 {
+		return fmt.Errorf("preference %q: allowed values only apply to strings", d.Key)
+	}
+	if err := d.check(d.Default); err != nil {
+		return fmt.Errorf("preference %q: invalid default: %w", d.Key, err)
+	}
+	return nil
+}
+
+// Decode parses a raw JSON value sent for this preference and validates it.
+func (d *PreferenceDefinition) Decode(raw json.RawMessage) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%s must be a valid JSON value", d.Key)
+	}
+
+	// JSON 数字统一按整数解析，拒绝小数和越界值
+	if n, ok := v.(json.Number); ok {
+		i, err := n.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", d.Key)
+		}
+		v = i
+	}
+	if err := d.check(v); err != nil {
+		return nil, fmt.Errorf("%s %w", d.Key, err)
+	}
+	return v, nil
+}
+
+// check validates a decoded value against the definition.
+func (d *PreferenceDefinition) check(v any) error {
+	switch d.Type {
+	case PreferenceBool:
+		if _, ok := v.(bool); !ok {
+			return errors.New("must be a boolean")
+		}
+	case PreferenceInt:
+		if _, ok := v.(int64); !ok {
+			return errors.New("must be an integer")
+		}
+	case PreferenceString:
+		s, ok := v.(string)
+		if !ok {
+			return errors.New("must be a string")
+		}
+		if utf8.RuneCountInString(s) > maxPreferenceStringLength {
+			return fmt.Errorf("must not exceed %d characters", maxPreferenceStringLength)
+		}
+		if len(d.Allowed) > 0 && !slices.Contains(d.Allowed, s) {
+			return fmt.Errorf("must be one of %v", d.Allowed)
+		}
+	default:
+		return fmt.Errorf("has unknown type %q", d.Type)
+	}
+	if d.Validate != nil {
+		return d.Validate(v)
+	}
+	return nil
+}
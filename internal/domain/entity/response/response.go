@@ -99,6 +99,25 @@ func NewPageResponseWithExtra[T any](message string, list []T, current, pageSize
 	}
 }
 
+// NewCursorPageResponse creates a new paginated success response for cursor-based
+// listings. HasNext is derived from whether a next page token was issued.
+func NewCursorPageResponse[T any](message string, list []T, current, pageSize, total int64, nextPageToken string) Response[PageData[T]] {
+	return Response[PageData[T]]{
+		Status:  StatusSuccess,
+		Message: message,
+		Data: PageData[T]{
+			List: list,
+			Pagination: Pagination{
+				Current:       current,
+				PageSize:      pageSize,
+				Total:         total,
+				HasNext:       nextPageToken != "",
+				NextPageToken: nextPageToken,
+			},
+		},
+	}
+}
+
 // NewErrorResponse creates a new error response.
 // If err is an *AppError, it extracts the code and user-safe message automatically.
 // Otherwise, it falls back to a generic "INTERNAL_ERROR" code.
@@ -101,6 +101,21 @@ func TestNewPageResponse_HasNext(t *testing.T) {
 	assert.True(t, resp.Data.Pagination.HasNext)
 }
 
+func TestNewCursorPageResponse(t *testing.T) {
+	resp := NewCursorPageResponse("OK", []string{"a", "b"}, 2, 2, 10, "next")
+
+	assert.True(t, resp.Data.Pagination.HasNext)
+	assert.Equal(t, "next", resp.Data.Pagination.NextPageToken)
+	assert.Equal(t, int64(2), resp.Data.Pagination.Current)
+}
+
+func TestNewCursorPageResponse_LastPage(t *testing.T) {
+	resp := NewCursorPageResponse("OK", []string{"a"}, 5, 2, 9, "")
+
+	assert.False(t, resp.Data.Pagination.HasNext)
+	assert.Empty(t, resp.Data.Pagination.NextPageToken)
+}
+
 func TestResponse_JSON(t *testing.T) {
 	resp := NewSuccessResponse("OK", map[string]string{"key": "value"})
 	bytes, err := resp.JSON()
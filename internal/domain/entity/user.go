@@ -5,23 +5,58 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type User struct {
-	ID        int64      `json:"id,string"`
-	Username  string     `json:"username"`
-	Email     string     `json:"email"`
-	Password  string     `json:"-"`                    // 不在 JSON 中显示密码
-	AvatarURL *string    `json:"avatar_url,omitempty"` // 头像 URL（可选）
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"` // 用于逻辑删除
+	ID          int64      `json:"id,string"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email"`
+	Password    string     `json:"-"`                    // 不在 JSON 中显示密码
+	AvatarURL   *string    `json:"avatar_url,omitempty"` // 头像 URL（可选）
+	AvatarKey   *string    `json:"-"`                    // 已上传头像在对象存储中的 key，外链头像为空
+	DisplayName *string    `json:"display_name,omitempty"`
+	Bio         *string    `json:"bio,omitempty"`
+	Locale      *string    `json:"locale,omitempty"`   // BCP 47 语言标签，如 "zh-CN"
+	Timezone    *string    `json:"timezone,omitempty"` // IANA 时区名，如 "Asia/Shanghai"
+	Website     *string    `json:"website,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // 用于逻辑删除
+	Version     int64      `json:"version"`              // 乐观锁版本号，每次修改递增；HTTP 层以 ETag 形式暴露
+}
+
+// PublicUser is the projection of a User that may be shown to other users.
+// Contact details (email) and account preferences (locale, timezone) are
+// only ever returned to the account owner.
+type PublicUser struct {
+	ID          int64     `json:"id,string"`
+	Username    string    `json:"username"`
+	AvatarURL   *string   `json:"avatar_url,omitempty"`
+	DisplayName *string   `json:"display_name,omitempty"`
+	Bio         *string   `json:"bio,omitempty"`
+	Website     *string   `json:"website,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Public returns the public projection of u.
+func (u *User) Public() *PublicUser {
+	return &PublicUser{
+		ID:          u.ID,
+		Username:    u.Username,
+		AvatarURL:   u.AvatarURL,
+		DisplayName: u.DisplayName,
+		Bio:         u.Bio,
+		Website:     u.Website,
+		CreatedAt:   u.CreatedAt,
+	}
 }
 
 // Validate 验证用户实体
 func (u *User) Validate() error {
-	if u.Username == "" {
-		return errors.New("username cannot be empty")
+	if err := ValidateUsername(u.Username); err != nil {
+		return err
 	}
 	if u.Email == "" {
 		return errors.New("email cannot be empty")
@@ -39,6 +74,31 @@ func (u *User) Validate() error {
 	return nil
 }
 
+// maxUsernameLength is the username limit, counted in characters (runes).
+const maxUsernameLength = 64
+
+// anonymizedUsernamePrefix starts the placeholder usernames given to purged
+// accounts ("deleted_<id>"). Users cannot pick such a name, otherwise they
+// could block the anonymization of another account.
+const anonymizedUsernamePrefix = "deleted_"
+
+// ValidateUsername checks the rules shared by registration and username changes.
+func ValidateUsername(username string) error {
+	if username == "" {
+		return errors.New("username cannot be empty")
+	}
+	if utf8.RuneCountInString(username) > maxUsernameLength {
+		return errors.New("username must not exceed 64 characters")
+	}
+	if strings.IndexFunc(username, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return errors.New("username must not contain whitespace or control characters")
+	}
+	if strings.HasPrefix(strings.ToLower(username), anonymizedUsernamePrefix) {
+		return errors.New(`username must not start with "deleted_"`)
+	}
+	return nil
+}
+
 // emailRegex is compiled once at package init. Supports:
 //   - Case-insensitive matching ((?i) flag)
 //   - TLD length 2-63 (covers .museum, .travel, .technology, etc.)
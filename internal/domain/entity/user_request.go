@@ -0,0 +1,187 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// ListUsersRequest holds the query parameters accepted by the user listing endpoint.
+//
+// Pagination is either offset-based (Page) or cursor-based (PageToken).
+// When PageToken is set it takes precedence and Page is ignored; the token
+// also pins the filters and order it was issued for.
+type ListUsersRequest struct {
+	Username      string     `form:"username"` // 用户名前缀匹配
+	CreatedAfter  *time.Time `form:"created_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+	Order         string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page          int64      `form:"page" binding:"omitempty,min=1"`
+	PageSize      int64      `form:"page_size" binding:"omitempty,min=1,max=100"`
+	PageToken     string     `form:"page_token"`
+}
+
+// ListUsersResponse is a single page of users.
+type ListUsersResponse struct {
+	Users         []User
+	Page          int64
+	PageSize      int64
+	Total         int64
+	NextPageToken string
+}
+
+// UpdateProfileRequest is a JSON merge-patch document for a user's profile.
+// Only members present in the request body are changed; a member sent as
+// null clears the stored value.
+//
+// Identity fields (username, email) and the password are deliberately not
+// part of this request: changing them needs uniqueness checks or
+// re-verification that a plain field patch cannot provide.
+type UpdateProfileRequest struct {
+	AvatarURL   PatchField[string] `json:"avatar_url"`
+	DisplayName PatchField[string] `json:"display_name"`
+	Bio         PatchField[string] `json:"bio"`
+	Locale      PatchField[string] `json:"locale"`
+	Timezone    PatchField[string] `json:"timezone"`
+	Website     PatchField[string] `json:"website"`
+}
+
+// ChangeUsernameRequest is the body of a username change.
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// Validate trims the requested username and checks it against the username rules.
+func (r *ChangeUsernameRequest) Validate() error {
+	r.Username = strings.TrimSpace(r.Username)
+	return ValidateUsername(r.Username)
+}
+
+// ChangeEmailRequest starts moving the caller's account to a new email address.
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"` // 需要重新输入密码确认
+}
+
+// Validate normalises the requested address and checks its format.
+func (r *ChangeEmailRequest) Validate() error {
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+	if !isValidEmail(r.Email) {
+		return errors.New("invalid email format")
+	}
+	return nil
+}
+
+// EmailChangeTokenRequest carries the token of an email confirmation or revert link.
+type EmailChangeTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Profile field limits.
+const (
+	maxAvatarURLLength   = 2048
+	maxWebsiteLength     = 2048
+	maxDisplayNameLength = 64  // 按字符（rune）计
+	maxBioLength         = 500 // 按字符（rune）计
+)
+
+// Validate checks the values present in the patch and normalises them in
+// place: display name and bio are trimmed, the locale is canonicalised
+// (e.g. "zh-hans-cn" becomes "zh-Hans-CN").
+//
+// Blank strings are rejected; clients clear a field by sending null.
+func (r *UpdateProfileRequest) Validate() error {
+	if v := r.AvatarURL.Value; v != nil {
+		if len(*v) > maxAvatarURLLength {
+			return errors.New("avatar_url must not exceed 2048 characters")
+		}
+		if !isValidHTTPURL(*v) {
+			return errors.New("avatar_url must be an absolute http(s) URL")
+		}
+	}
+
+	if v := r.DisplayName.Value; v != nil {
+		*v = strings.TrimSpace(*v)
+		if *v == "" {
+			return errors.New("display_name must not be blank")
+		}
+		if utf8.RuneCountInString(*v) > maxDisplayNameLength {
+			return fmt.Errorf("display_name must not exceed %d characters", maxDisplayNameLength)
+		}
+		if strings.ContainsFunc(*v, unicode.IsControl) {
+			return errors.New("display_name must not contain control characters")
+		}
+	}
+
+	if v := r.Bio.Value; v != nil {
+		*v = strings.TrimSpace(*v)
+		if *v == "" {
+			return errors.New("bio must not be blank")
+		}
+		if utf8.RuneCountInString(*v) > maxBioLength {
+			return fmt.Errorf("bio must not exceed %d characters", maxBioLength)
+		}
+	}
+
+	if v := r.Locale.Value; v != nil {
+		tag, err := language.Parse(*v)
+		if err != nil || tag == language.Und {
+			return errors.New("locale must be a valid BCP 47 language tag")
+		}
+		*v = tag.String()
+	}
+
+	if v := r.Timezone.Value; v != nil {
+		if !isValidTimezone(*v) {
+			return errors.New("timezone must be a valid IANA time zone name")
+		}
+	}
+
+	if v := r.Website.Value; v != nil {
+		if len(*v) > maxWebsiteLength {
+			return errors.New("website must not exceed 2048 characters")
+		}
+		if !isValidHTTPURL(*v) {
+			return errors.New("website must be an absolute http(s) URL")
+		}
+	}
+	return nil
+}
+
+// isValidTimezone 验证是否为可加载的 IANA 时区名称
+// "Local" 和空串虽然能被 time.LoadLocation 接受，但依赖服务器配置，这里拒绝
+func isValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// isValidHTTPURL 验证是否为绝对 http(s) URL
+func isValidHTTPURL(raw string) bool {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// DeleteAccountRequest confirms the deletion of the caller's own account.
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"` // 需要重新输入密码确认
+}
+
+// AvatarResponse describes a freshly uploaded avatar.
+// AvatarURL is the largest rendition; Sizes maps each edge length in pixels
+// (as a string, e.g. "128") to the URL of that thumbnail.
+type AvatarResponse struct {
+	AvatarURL string            `json:"avatar_url"`
+	Sizes     map[string]string `json:"sizes"`
+}
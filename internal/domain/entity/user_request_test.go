@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchField_States(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantSet   bool
+		wantNull  bool
+		wantValue string
+	}{
+		{name: "absent", body: `{}`, wantSet: false},
+		{name: "null", body: `{"avatar_url":null}`, wantSet: true, wantNull: true},
+		{name: "value", body: `{"avatar_url":"https://example.com/a.png"}`, wantSet: true, wantValue: "https://example.com/a.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req UpdateProfileRequest
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+			assert.Equal(t, tt.wantSet, req.AvatarURL.Set)
+			assert.Equal(t, tt.wantNull, req.AvatarURL.IsNull())
+			if tt.wantValue != "" {
+				assert.Equal(t, tt.wantValue, *req.AvatarURL.Value)
+			}
+		})
+	}
+}
+
+func TestPatchField_TypeMismatch(t *testing.T) {
+	var req UpdateProfileRequest
+	assert.Error(t, json.Unmarshal([]byte(`{"avatar_url":42}`), &req))
+}
+
+func TestUpdateProfileRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "empty patch", body: `{}`},
+		{name: "clear avatar", body: `{"avatar_url":null}`},
+		{name: "https avatar", body: `{"avatar_url":"https://cdn.example.com/a.png"}`},
+		{name: "relative avatar", body: `{"avatar_url":"/a.png"}`, wantErr: "avatar_url must be an absolute http(s) URL"},
+		{name: "javascript avatar", body: `{"avatar_url":"javascript:alert(1)"}`, wantErr: "avatar_url must be an absolute http(s) URL"},
+		{name: "oversized avatar", body: `{"avatar_url":"https://example.com/` + strings.Repeat("a", 2048) + `"}`, wantErr: "avatar_url must not exceed 2048 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req UpdateProfileRequest
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+			err := req.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileRequest_ValidateProfileFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "display name", body: `{"display_name":"  Kirk Lin  "}`},
+		{name: "blank display name", body: `{"display_name":"   "}`, wantErr: "display_name must not be blank"},
+		{name: "long display name", body: `{"display_name":"` + strings.Repeat("名", 65) + `"}`, wantErr: "display_name must not exceed 64 characters"},
+		{name: "control chars in display name", body: `{"display_name":"a\u0000b"}`, wantErr: "display_name must not contain control characters"},
+		{name: "bio at limit counts runes", body: `{"bio":"` + strings.Repeat("字", 500) + `"}`},
+		{name: "long bio", body: `{"bio":"` + strings.Repeat("a", 501) + `"}`, wantErr: "bio must not exceed 500 characters"},
+		{name: "clear bio", body: `{"bio":null}`},
+		{name: "locale", body: `{"locale":"zh-Hans-CN"}`},
+		{name: "invalid locale", body: `{"locale":"not a locale"}`, wantErr: "locale must be a valid BCP 47 language tag"},
+		{name: "undetermined locale", body: `{"locale":"und"}`, wantErr: "locale must be a valid BCP 47 language tag"},
+		{name: "timezone", body: `{"timezone":"Asia/Shanghai"}`},
+		{name: "utc timezone", body: `{"timezone":"UTC"}`},
+		{name: "unknown timezone", body: `{"timezone":"Mars/Olympus_Mons"}`, wantErr: "timezone must be a valid IANA time zone name"},
+		{name: "local timezone", body: `{"timezone":"Local"}`, wantErr: "timezone must be a valid IANA time zone name"},
+		{name: "website", body: `{"website":"https://kirk.example.com"}`},
+		{name: "ftp website", body: `{"website":"ftp://kirk.example.com"}`, wantErr: "website must be an absolute http(s) URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req UpdateProfileRequest
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+			err := req.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileRequest_ValidateNormalises(t *testing.T) {
+	var req UpdateProfileRequest
+	assert.NoError(t, json.Unmarshal([]byte(`{"display_name":"  Kirk  ","locale":"zh-hans-cn"}`), &req))
+
+	assert.NoError(t, req.Validate())
+	assert.Equal(t, "Kirk", *req.DisplayName.Value)
+	assert.Equal(t, "zh-Hans-CN", *req.Locale.Value)
+}
+
+func TestUser_PublicOmitsPrivateFields(t *testing.T) {
+	locale, tz := "en", "UTC"
+	u := &User{ID: 1, Username: "kirk", Email: "kirk@example.com", Password: "hash", Locale: &locale, Timezone: &tz}
+
+	data, err := json.Marshal(u.Public())
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "kirk@example.com")
+	assert.NotContains(t, string(data), "locale")
+	assert.NotContains(t, string(data), "timezone")
+	assert.Contains(t, string(data), `"username":"kirk"`)
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,6 +23,21 @@ func TestUser_Validate(t *testing.T) {
 			user:    User{Username: "", Email: "kirk@example.com", Password: "securepass"},
 			wantErr: "username cannot be empty",
 		},
+		{
+			name:    "username with whitespace",
+			user:    User{Username: "kirk lin", Email: "kirk@example.com", Password: "securepass"},
+			wantErr: "username must not contain whitespace or control characters",
+		},
+		{
+			name:    "username too long",
+			user:    User{Username: strings.Repeat("k", 65), Email: "kirk@example.com", Password: "securepass"},
+			wantErr: "username must not exceed 64 characters",
+		},
+		{
+			name:    "username imitating an anonymized account",
+			user:    User{Username: "Deleted_42", Email: "kirk@example.com", Password: "securepass"},
+			wantErr: `username must not start with "deleted_"`,
+		},
 		{
 			name:    "empty email",
 			user:    User{Username: "kirk", Email: "", Password: "securepass"},
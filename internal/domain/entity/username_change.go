@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// UsernameChange records one rename of a user account. The history backs the
+// change cooldown and keeps released names reserved for a while, so nobody
+// else can pick them up right away and impersonate the previous owner.
+type UsernameChange struct {
+	ID          int64     `json:"id,string"`
+	UserID      int64     `json:"user_id,string"`
+	OldUsername string    `json:"old_username"`
+	NewUsername string    `json:"new_username"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
@@ -6,6 +6,7 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -65,6 +66,18 @@ func (e *AppError) WithMessage(msg string) *AppError {
 	return &clone
 }
 
+// HasCode reports whether err is or wraps an AppError with the same code as
+// target. Use it instead of errors.Is for sentinels: Wrap and WithMessage
+// return copies, which errors.Is does not match.
+//
+// Usage:
+//
+//	if domainerrors.HasCode(err, domainerrors.ErrUserNotFound) { ... }
+func HasCode(err error, target *AppError) bool {
+	var appErr *AppError
+	return errors.As(err, &appErr) && appErr.Code == target.Code
+}
+
 // =============================================================================
 // Common / Shared Errors
 // =============================================================================
@@ -93,6 +106,12 @@ var (
 
 	// ErrRequestTimeout indicates the request took too long to process.
 	ErrRequestTimeout = &AppError{Code: "REQUEST_TIMEOUT", Message: "Request timeout", HTTPCode: http.StatusRequestTimeout}
+
+	// ErrPayloadTooLarge indicates the request body exceeds the allowed size.
+	ErrPayloadTooLarge = &AppError{Code: "PAYLOAD_TOO_LARGE", Message: "Request payload is too large", HTTPCode: http.StatusRequestEntityTooLarge}
+
+	// ErrUnsupportedMediaType indicates the uploaded content is not of an accepted type.
+	ErrUnsupportedMediaType = &AppError{Code: "UNSUPPORTED_MEDIA_TYPE", Message: "Unsupported media type", HTTPCode: http.StatusUnsupportedMediaType}
 )
 
 // =============================================================================
@@ -106,6 +125,9 @@ var (
 	ErrTokenBlacklisted   = &AppError{Code: "TOKEN_REVOKED", Message: "Token has been revoked", HTTPCode: http.StatusUnauthorized}
 	ErrTokenInvalid       = &AppError{Code: "TOKEN_INVALID", Message: "Invalid or expired token", HTTPCode: http.StatusUnauthorized}
 	ErrTokenSigningMethod = &AppError{Code: "TOKEN_SIGNING_INVALID", Message: "Unexpected token signing method", HTTPCode: http.StatusUnauthorized}
+	// ErrPasswordConfirmation is returned when a sensitive operation is re-confirmed with a wrong password.
+	// It is 403 rather than 401 so clients do not mistake it for an expired session.
+	ErrPasswordConfirmation = &AppError{Code: "PASSWORD_CONFIRMATION_FAILED", Message: "Password confirmation failed", HTTPCode: http.StatusForbidden}
 )
 
 // =============================================================================
@@ -114,6 +136,18 @@ var (
 
 var (
 	ErrUserNotFound = &AppError{Code: "USER_NOT_FOUND", Message: "User not found", HTTPCode: http.StatusNotFound}
+	// ErrUsernameChangeTooSoon is returned when a username is changed again within the cooldown period.
+	ErrUsernameChangeTooSoon = &AppError{Code: "USERNAME_CHANGE_TOO_SOON", Message: "Username was changed too recently", HTTPCode: http.StatusTooManyRequests}
+)
+
+// =============================================================================
+// Email Change Errors
+// =============================================================================
+
+var (
+	ErrEmailChangeNotFound = &AppError{Code: "EMAIL_CHANGE_NOT_FOUND", Message: "Email change not found", HTTPCode: http.StatusNotFound}
+	// ErrEmailChangeLinkInvalid covers forged, expired, superseded and already used email change links alike.
+	ErrEmailChangeLinkInvalid = &AppError{Code: "EMAIL_CHANGE_LINK_INVALID", Message: "Email change link is invalid or has expired", HTTPCode: http.StatusGone}
 )
 
 // =============================================================================
@@ -124,6 +158,28 @@ var (
 	ErrValidationFailed = &AppError{Code: "VALIDATION_FAILED", Message: "Validation failed", HTTPCode: http.StatusBadRequest}
 )
 
+// =============================================================================
+// Pagination Errors
+// =============================================================================
+
+var (
+	ErrInvalidPageToken = &AppError{Code: "INVALID_PAGE_TOKEN", Message: "Invalid or expired page token", HTTPCode: http.StatusBadRequest}
+)
+
+// =============================================================================
+// Concurrency Errors
+// =============================================================================
+
+var (
+	// ErrConcurrentModification is returned when a record changed between being read and written.
+	ErrConcurrentModification = &AppError{Code: "CONCURRENT_MODIFICATION", Message: "The resource was modified concurrently, please retry", HTTPCode: http.StatusConflict}
+	// ErrTxConflict is returned when a transaction failed because of a concurrent one
+	// (serialization failure, deadlock, lock timeout) and was not, or no longer, retried.
+	ErrTxConflict = &AppError{Code: "TRANSACTION_CONFLICT", Message: "The operation conflicted with a concurrent one, please retry", HTTPCode: http.StatusConflict}
+	// ErrPreconditionFailed is returned when the version a client sent in If-Match is not the current one.
+	ErrPreconditionFailed = &AppError{Code: "PRECONDITION_FAILED", Message: "The resource has changed since it was retrieved", HTTPCode: http.StatusPreconditionFailed}
+)
+
 // =============================================================================
 // Repository Errors
 // =============================================================================
@@ -133,6 +189,25 @@ var (
 	ErrPermissionDenied = &AppError{Code: "PERMISSION_DENIED", Message: "Permission denied for this operation", HTTPCode: http.StatusForbidden}
 )
 
+// =============================================================================
+// Storage Errors
+// =============================================================================
+
+var (
+	ErrObjectNotFound = &AppError{Code: "OBJECT_NOT_FOUND", Message: "Object not found", HTTPCode: http.StatusNotFound}
+	ErrInvalidImage   = &AppError{Code: "INVALID_IMAGE", Message: "Invalid or corrupted image", HTTPCode: http.StatusBadRequest}
+)
+
+// =============================================================================
+// Data Export Errors
+// =============================================================================
+
+var (
+	ErrExportNotFound = &AppError{Code: "EXPORT_NOT_FOUND", Message: "Data export not found", HTTPCode: http.StatusNotFound}
+	// ErrDownloadLinkInvalid covers forged, expired and already purged download links alike.
+	ErrDownloadLinkInvalid = &AppError{Code: "DOWNLOAD_LINK_INVALID", Message: "Download link is invalid or has expired", HTTPCode: http.StatusGone}
+)
+
 // =============================================================================
 // Moderation Errors
 // =============================================================================
@@ -76,6 +76,17 @@ func TestAppError_ErrorsAs(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, appErr.HTTPCode)
 }
 
+func TestHasCode(t *testing.T) {
+	// Copies made by Wrap and WithMessage keep the code, even when wrapped again
+	wrapped := fmt.Errorf("loading user: %w", ErrUserNotFound.Wrap(fmt.Errorf("some cause")))
+
+	assert.True(t, HasCode(wrapped, ErrUserNotFound))
+	assert.True(t, HasCode(ErrUserNotFound.WithMessage("custom"), ErrUserNotFound))
+	assert.False(t, HasCode(wrapped, ErrInternal))
+	assert.False(t, HasCode(fmt.Errorf("plain"), ErrUserNotFound))
+	assert.False(t, HasCode(nil, ErrUserNotFound))
+}
+
 func TestSentinelErrors_HTTPCodes(t *testing.T) {
 	tests := []struct {
 		err      *AppError
@@ -90,15 +101,29 @@ func TestSentinelErrors_HTTPCodes(t *testing.T) {
 		{ErrConflict, http.StatusConflict, "CONFLICT"},
 		{ErrTooManyRequests, http.StatusTooManyRequests, "RATE_LIMITED"},
 		{ErrRequestTimeout, http.StatusRequestTimeout, "REQUEST_TIMEOUT"},
+		{ErrPayloadTooLarge, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE"},
+		{ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
 		{ErrUsernameExists, http.StatusConflict, "USERNAME_ALREADY_EXISTS"},
 		{ErrEmailExists, http.StatusConflict, "EMAIL_ALREADY_EXISTS"},
 		{ErrInvalidCredentials, http.StatusUnauthorized, "INVALID_CREDENTIALS"},
 		{ErrTokenBlacklisted, http.StatusUnauthorized, "TOKEN_REVOKED"},
 		{ErrTokenInvalid, http.StatusUnauthorized, "TOKEN_INVALID"},
+		{ErrPasswordConfirmation, http.StatusForbidden, "PASSWORD_CONFIRMATION_FAILED"},
 		{ErrUserNotFound, http.StatusNotFound, "USER_NOT_FOUND"},
+		{ErrUsernameChangeTooSoon, http.StatusTooManyRequests, "USERNAME_CHANGE_TOO_SOON"},
+		{ErrEmailChangeNotFound, http.StatusNotFound, "EMAIL_CHANGE_NOT_FOUND"},
+		{ErrEmailChangeLinkInvalid, http.StatusGone, "EMAIL_CHANGE_LINK_INVALID"},
 		{ErrValidationFailed, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{ErrInvalidPageToken, http.StatusBadRequest, "INVALID_PAGE_TOKEN"},
+		{ErrConcurrentModification, http.StatusConflict, "CONCURRENT_MODIFICATION"},
+		{ErrTxConflict, http.StatusConflict, "TRANSACTION_CONFLICT"},
+		{ErrPreconditionFailed, http.StatusPreconditionFailed, "PRECONDITION_FAILED"},
 		{ErrNoRowsAffected, http.StatusNotFound, "NO_ROWS_AFFECTED"},
 		{ErrPermissionDenied, http.StatusForbidden, "PERMISSION_DENIED"},
+		{ErrObjectNotFound, http.StatusNotFound, "OBJECT_NOT_FOUND"},
+		{ErrInvalidImage, http.StatusBadRequest, "INVALID_IMAGE"},
+		{ErrExportNotFound, http.StatusNotFound, "EXPORT_NOT_FOUND"},
+		{ErrDownloadLinkInvalid, http.StatusGone, "DOWNLOAD_LINK_INVALID"},
 	}
 
 	for _, tt := range tests {
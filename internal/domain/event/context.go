@@ -0,0 +1,20 @@
+package event
+
+import "context"
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying the idempotency key of the
+// event being delivered.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// IdempotencyKey returns the key of the event being delivered, if it came from
+// the outbox. Outbox delivery is at least once: a handler with side effects
+// that must not repeat (sending mail, charging) records the keys it has
+// processed and skips the ones it has seen.
+func IdempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyKey{}).(string)
+	return key, ok
+}
@@ -0,0 +1,39 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// decoders maps every event name to the decoder of its type. Add new events
+// here so that they can be read back from the outbox.
+var decoders = map[string]func(data []byte) (Event, error){
+	NameUserRegistered:  decodeAs[UserRegistered],
+	NameUsernameChanged: decodeAs[UsernameChanged],
+	NameEmailChanged:    decodeAs[EmailChanged],
+	NameAccountDeleted:  decodeAs[AccountDeleted],
+	NameAccountRestored: decodeAs[AccountRestored],
+	NameAccountPurged:   decodeAs[AccountPurged],
+}
+
+// Decode rebuilds the event named name from its JSON encoding, as the same
+// type that was published.
+func Decode(name string, data []byte) (Event, error) {
+	decode, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	e, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode event %q: %w", name, err)
+	}
+	return e, nil
+}
+
+func decodeAs[E Event](data []byte) (Event, error) {
+	var e E
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,37 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode_RoundTrip(t *testing.T) {
+	events := []Event{
+		UserRegistered{UserID: 1, Username: "kirk"},
+		UsernameChanged{UserID: 1, OldUsername: "kirk", NewUsername: "captain"},
+		EmailChanged{UserID: 1, ChangeID: 2, Reverted: true},
+		AccountDeleted{UserID: 1},
+		AccountRestored{UserID: 1},
+		AccountPurged{UserID: 1, Mode: "anonymize"},
+	}
+	require.Len(t, decoders, len(events), "every event type is decodable")
+
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		require.NoError(t, err)
+		decoded, err := Decode(e.EventName(), data)
+		require.NoError(t, err)
+		assert.Equal(t, e, decoded)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	_, err := Decode("user.unknown", []byte(`{}`))
+	assert.ErrorContains(t, err, "unknown event")
+
+	_, err = Decode(NameAccountDeleted, []byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+// Package event defines the domain events that use cases announce through
+// gateway.EventPublisher.
+//
+// An event is a fact in the past tense: the change it describes has already
+// been made. Subscribers react to it (send mail, update read models, ...)
+// without the use case knowing about them.
+package event
+
+// Event is a domain event.
+type Event interface {
+	// EventName identifies the kind of event, e.g. "user.registered".
+	// Subscribers are registered by name, so names never change once used.
+	EventName() string
+}
+
+// Event names
+const (
+	NameUserRegistered  = "user.registered"
+	NameUsernameChanged = "user.username_changed"
+	NameEmailChanged    = "user.email_changed"
+	NameAccountDeleted  = "user.account_deleted"
+	NameAccountRestored = "user.account_restored"
+	NameAccountPurged   = "user.account_purged"
+)
+
+// UserRegistered is raised when a new account has been created.
+//
+// Events are stored in the outbox as they are, so they carry IDs rather than
+// email addresses; subscribers load the addresses they need.
+type UserRegistered struct {
+	UserID   int64  `json:"user_id,string"`
+	Username string `json:"username"`
+}
+
+func (UserRegistered) EventName() string { return NameUserRegistered }
+
+// UsernameChanged is raised when a user has taken a new username.
+type UsernameChanged struct {
+	UserID      int64  `json:"user_id,string"`
+	OldUsername string `json:"old_username"`
+	NewUsername string `json:"new_username"`
+}
+
+func (UsernameChanged) EventName() string { return NameUsernameChanged }
+
+// EmailChanged is raised when a user's email address has changed, either by
+// confirming a change or by reverting one from the previous address. Both
+// addresses are recorded in the email change ChangeID.
+type EmailChanged struct {
+	UserID   int64 `json:"user_id,string"`
+	ChangeID int64 `json:"change_id,string"`
+	Reverted bool  `json:"reverted"`
+}
+
+func (EmailChanged) EventName() string { return NameEmailChanged }
+
+// AccountDeleted is raised when a user has deleted their account. It can
+// still be restored until it is purged.
+type AccountDeleted struct {
+	UserID int64 `json:"user_id,string"`
+}
+
+func (AccountDeleted) EventName() string { return NameAccountDeleted }
+
+// AccountRestored is raised when a deleted account has been restored by
+// logging in during the grace period.
+type AccountRestored struct {
+	UserID int64 `json:"user_id,string"`
+}
+
+func (AccountRestored) EventName() string { return NameAccountRestored }
+
+// AccountPurged is raised when a deleted account's personal data has been
+// removed for good, either by deleting the row or by anonymizing it.
+type AccountPurged struct {
+	UserID int64  `json:"user_id,string"`
+	Mode   string `json:"mode"` // delete | anonymize
+}
+
+func (AccountPurged) EventName() string { return NameAccountPurged }
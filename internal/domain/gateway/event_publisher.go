@@ -0,0 +1,19 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+)
+
+// EventPublisher announces domain events to their subscribers.
+// Implementations live in the infrastructure layer.
+//
+// Inside TxManager.WithTx, events are held back until the transaction
+// commits and discarded if it rolls back (see repository.AfterCommit), so
+// subscribers never hear about changes that did not happen.
+type EventPublisher interface {
+	// Publish delivers events in order. A failing subscriber does not fail
+	// the publisher: the change the event describes has already been made.
+	Publish(ctx context.Context, events ...event.Event) error
+}
@@ -0,0 +1,14 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+// Notifier delivers messages to users through an out-of-band channel
+// (email, push, ...). Implementations live in the infrastructure layer.
+type Notifier interface {
+	// Notify sends n to its recipient
+	Notify(ctx context.Context, n *entity.Notification) error
+}
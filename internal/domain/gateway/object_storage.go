@@ -0,0 +1,24 @@
+package gateway
+
+import (
+	"context"
+	"io"
+)
+
+// ObjectStorage defines the interface for storing and serving binary objects (avatars, attachments, ...).
+// Keys are slash-separated relative paths such as "avatars/42/abc/256.png"; backends decide how they are laid out.
+type ObjectStorage interface {
+	// Put stores size bytes read from r under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the object stored under key; the caller must close the returned reader
+	// It returns errors.ErrObjectNotFound if the object does not exist
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key
+	// Deleting an object that does not exist is not an error
+	Delete(ctx context.Context, key string) error
+
+	// URL returns the public URL at which the object under key can be fetched
+	URL(key string) string
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *entity.DataExport) error
+	FindByID(ctx context.Context, id int64) (*entity.DataExport, error)
+	// Update writes an existing export if it still has export.Version, and bumps the version.
+	// It returns ErrConcurrentModification if the export was claimed again in the meantime
+	Update(ctx context.Context, export *entity.DataExport) error
+	Delete(ctx context.Context, id int64) error
+
+	// FindActiveByUserID retrieves the user's export that is still pending or processing
+	FindActiveByUserID(ctx context.Context, userID int64) (*entity.DataExport, error)
+	// ListClaimable returns up to limit pending exports, plus processing exports whose
+	// worker stopped reporting before staleBefore, oldest first
+	ListClaimable(ctx context.Context, staleBefore time.Time, limit int) ([]*entity.DataExport, error)
+	// Claim atomically moves a claimable export whose version is still export.Version
+	// to processing and bumps its version. On success export is updated.
+	// It returns ErrNoRowsAffected if the export was claimed by another worker first
+	Claim(ctx context.Context, export *entity.DataExport, staleBefore time.Time) error
+	// ListByUserID returns every export of a user, whatever its status
+	ListByUserID(ctx context.Context, userID int64) ([]*entity.DataExport, error)
+	// DeleteByUserID permanently removes every export of a user; their archives must be deleted separately
+	DeleteByUserID(ctx context.Context, userID int64) error
+	// ListExpired returns up to limit finished exports whose ExpiresAt is before the given time
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*entity.DataExport, error)
+}
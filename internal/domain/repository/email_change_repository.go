@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+type EmailChangeRepository interface {
+	Create(ctx context.Context, change *entity.EmailChange) error
+	FindByID(ctx context.Context, id int64) (*entity.EmailChange, error)
+	Update(ctx context.Context, change *entity.EmailChange) error
+
+	// ListByUserID returns the email changes of a user, most recent first
+	ListByUserID(ctx context.Context, userID int64) ([]*entity.EmailChange, error)
+	// CancelPendingByUserID marks every pending email change of a user as cancelled
+	CancelPendingByUserID(ctx context.Context, userID int64) error
+	// DeleteByUserID removes the email changes of a user
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
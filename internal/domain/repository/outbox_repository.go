@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+type OutboxRepository interface {
+	// Create stores a message. Called with a transaction context, the message
+	// is committed or rolled back together with the change it describes
+	Create(ctx context.Context, msg *entity.OutboxMessage) error
+	// Update writes the delivery state (status, attempts, next attempt, error) of a message
+	Update(ctx context.Context, msg *entity.OutboxMessage) error
+
+	// ListDue returns up to limit pending messages whose next attempt is due at now, oldest first
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxMessage, error)
+	// Claim atomically records a delivery attempt of a pending message whose attempt
+	// count is still msg.Attempts, and defers its next attempt to retryAt in case the
+	// delivery never completes. On success msg is updated.
+	// It returns ErrNoRowsAffected if another relay claimed the message first
+	Claim(ctx context.Context, msg *entity.OutboxMessage, retryAt time.Time) error
+	// Backlog returns the number of pending messages and the age of the oldest
+	Backlog(ctx context.Context) (*entity.OutboxBacklog, error)
+	// DeletePublishedBefore removes up to limit messages published before the given time
+	// and returns how many were removed
+	DeletePublishedBefore(ctx context.Context, before time.Time, limit int) (int, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+// SortOrder is the direction in which list queries are ordered.
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// ListOptions controls ordering and pagination for List queries.
+//
+// Rows are always ordered by primary key. Because IDs are Snowflake IDs,
+// this is equivalent to ordering by creation time and gives every row a
+// stable, unique position that keyset pagination can rely on.
+//
+// Two pagination modes are supported:
+//
+//   - Offset: set Offset to skip a number of rows (page-number navigation).
+//   - Keyset: set AfterID to the last ID of the previous page; only rows
+//     strictly after it in the sort direction are returned. When AfterID
+//     is non-zero, Offset is ignored.
+type ListOptions struct {
+	Order   SortOrder
+	Limit   int
+	Offset  int
+	AfterID int64
+}
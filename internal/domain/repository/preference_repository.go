@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type PreferenceRepository interface {
+	// ListByUserID returns the preferences a user has set, as raw JSON values keyed by preference key
+	ListByUserID(ctx context.Context, userID int64) (map[string]json.RawMessage, error)
+	// Set stores the given values, replacing any previous value of the same key
+	Set(ctx context.Context, userID int64, values map[string]json.RawMessage) error
+	// Unset removes the given keys, so that their defaults apply again
+	Unset(ctx context.Context, userID int64, keys []string) error
+	// DeleteByUserID removes every preference of a user
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import "context"
+
+type primaryKey struct{}
+
+// WithPrimary returns a copy of ctx whose reads go to the primary database
+// even when read replicas are configured. Use it where a read must observe a
+// write that was just made (read-your-writes) or feeds a later write, since
+// replicas may lag behind. Reads inside TxManager.WithTx always use the
+// primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary reports whether ctx was marked by WithPrimary.
+func UsesPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
@@ -1,7 +1,13 @@
 // Package repository defines the persistence interfaces for domain entities.
 package repository
 
-import "context"
+import (
+	"context"
+	"sync"
+	"time"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+)
 
 // TxManager abstracts database transaction management.
 //
@@ -11,7 +17,16 @@ import "context"
 //   - If fn returns a non-nil error or panics, the transaction is rolled back.
 //   - If fn returns nil, the transaction is committed.
 //
-// Nested calls to WithTx should be supported via savepoints (GORM default).
+// Nested calls to WithTx should be supported via savepoints (GORM default);
+// they join the outer transaction, so their options are ignored.
+// Implementations must also run the functions registered with AfterCommit
+// once the transaction commits (see WithCommitHooks).
+//
+// A transaction that fails because of a concurrent one (serialization
+// failure, deadlock) returns ErrTxConflict wrapping the driver's error; test
+// for it with IsTxConflict. With WithRetry the whole transaction, fn
+// included, is then run again, so fn must not have side effects outside the
+// database other than through AfterCommit.
 //
 // Usage in a use case:
 //
@@ -20,7 +35,149 @@ import "context"
 //	        return err // triggers rollback
 //	    }
 //	    return repo.Update(txCtx, other) // same transaction
-//	})
+//	}, repository.WithIsolation(repository.Serializable), repository.WithRetry(3, 50*time.Millisecond))
 type TxManager interface {
-	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error
+}
+
+// IsolationLevel is the isolation level of a transaction.
+type IsolationLevel int
+
+const (
+	IsolationDefault IsolationLevel = iota // 数据库的默认隔离级别
+	ReadCommitted
+	RepeatableRead
+	Serializable
+)
+
+// TxOptions configures a transaction. Use the TxOption functions to set it.
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+	// Retries is how many times a transaction failing with ErrTxConflict is
+	// run again. The first retry waits Backoff, every further one twice as long.
+	Retries int
+	Backoff time.Duration
+}
+
+// TxOption configures a transaction started by TxManager.WithTx.
+type TxOption func(*TxOptions)
+
+// WithIsolation runs the transaction at the given isolation level.
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(o *TxOptions) { o.Isolation = level }
+}
+
+// ReadOnly starts a read-only transaction, e.g. for a consistent snapshot
+// across several queries. Writes in it fail.
+func ReadOnly() TxOption {
+	return func(o *TxOptions) { o.ReadOnly = true }
+}
+
+// WithRetry runs the transaction up to n more times if it fails with
+// ErrTxConflict, waiting backoff before the first retry and doubling the wait
+// before each further one.
+func WithRetry(n int, backoff time.Duration) TxOption {
+	return func(o *TxOptions) {
+		o.Retries = n
+		o.Backoff = backoff
+	}
+}
+
+// ApplyTxOptions is for TxManager implementations. It returns the options
+// set by opts.
+func ApplyTxOptions(opts ...TxOption) TxOptions {
+	var o TxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// RunWithRetry is for TxManager implementations. It calls attempt, which
+// runs the whole transaction, again as long as it fails with ErrTxConflict
+// and o allows more retries, waiting between attempts. It gives up early if
+// ctx is cancelled, returning the last error.
+func (o TxOptions) RunWithRetry(ctx context.Context, attempt func() error) error {
+	backoff := o.Backoff
+	for retry := 0; ; retry++ {
+		err := attempt()
+		if err == nil || retry >= o.Retries || !IsTxConflict(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// IsTxConflict reports whether err is ErrTxConflict or a copy of it made by
+// Wrap, which errors.Is does not match.
+func IsTxConflict(err error) bool {
+	return domainerrors.HasCode(err, domainerrors.ErrTxConflict)
+}
+
+type commitHooksKey struct{}
+
+// commitHooks collects the AfterCommit functions of one transaction.
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func(ctx context.Context)
+}
+
+func (h *commitHooks) add(fn func(ctx context.Context)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fns = append(h.fns, fn)
+}
+
+func (h *commitHooks) take() []func(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fns := h.fns
+	h.fns = nil
+	return fns
+}
+
+// AfterCommit arranges for fn to run once the transaction of ctx has
+// committed, or runs it right away if ctx is not inside TxManager.WithTx.
+// If the transaction rolls back, fn never runs.
+//
+// fn receives the context WithTx was called with, not the transaction's,
+// so that whatever it does runs outside the finished transaction.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(commitHooksKey{}).(*commitHooks); ok {
+		hooks.add(fn)
+		return
+	}
+	fn(ctx)
+}
+
+// WithCommitHooks is for TxManager implementations. It returns the context
+// to pass to the transaction callback, in which AfterCommit defers its
+// functions, and a function to call once the transaction has ended. With
+// committed set the deferred functions run, or are handed to the enclosing
+// transaction if WithTx calls are nested; otherwise they are dropped.
+func WithCommitHooks(ctx context.Context) (context.Context, func(committed bool)) {
+	hooks := &commitHooks{}
+	parent, nested := ctx.Value(commitHooksKey{}).(*commitHooks)
+	end := func(committed bool) {
+		if !committed {
+			return
+		}
+		for _, fn := range hooks.take() {
+			if nested {
+				parent.add(fn)
+			} else {
+				fn(ctx)
+			}
+		}
+	}
+	return context.WithValue(ctx, commitHooksKey{}, hooks), end
 }
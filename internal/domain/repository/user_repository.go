@@ -2,15 +2,58 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
 )
 
+// UserFilter narrows the set of users returned by List and Count.
+// Zero-valued fields are ignored.
+type UserFilter struct {
+	UsernamePrefix string
+	CreatedAfter   *time.Time // inclusive
+	CreatedBefore  *time.Time // exclusive
+}
+
+// UserField names a user attribute that can be written by UpdateFields.
+// It acts as a field mask: only the listed attributes are persisted, every
+// other column keeps its stored value.
+type UserField string
+
+const (
+	UserFieldUsername    UserField = "username"
+	UserFieldEmail       UserField = "email"
+	UserFieldAvatarURL   UserField = "avatar_url"
+	UserFieldAvatarKey   UserField = "avatar_key"
+	UserFieldDisplayName UserField = "display_name"
+	UserFieldBio         UserField = "bio"
+	UserFieldLocale      UserField = "locale"
+	UserFieldTimezone    UserField = "timezone"
+	UserFieldWebsite     UserField = "website"
+)
+
 type UserRepository interface {
+	// Create, Update and UpdateFields return ErrUsernameExists or ErrEmailExists
+	// when another row holds the username or email, even one written concurrently
 	Create(ctx context.Context, user *entity.User) error
 	FindByID(ctx context.Context, id int64) (*entity.User, error)
 	FindByUsername(ctx context.Context, username string) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	List(ctx context.Context, filter UserFilter, opts ListOptions) ([]*entity.User, error)
+	Count(ctx context.Context, filter UserFilter) (int64, error)
 	Update(ctx context.Context, user *entity.User) error
+	UpdateFields(ctx context.Context, user *entity.User, fields ...UserField) error
 	SoftDelete(ctx context.Context, id int64) error
+
+	// FindDeletedByUsername finds a soft-deleted user that has not been purged yet
+	FindDeletedByUsername(ctx context.Context, username string) (*entity.User, error)
+	// Restore clears the deletion mark of a soft-deleted, not yet purged user
+	Restore(ctx context.Context, id int64) error
+	// ListDeletedBefore returns up to limit users soft-deleted before the given time that have not been purged
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entity.User, error)
+	// HardDelete permanently removes a user row
+	HardDelete(ctx context.Context, id int64) error
+	// Anonymize replaces a deleted user's personal data with placeholders, releasing
+	// the username and email while keeping the row for referential integrity
+	Anonymize(ctx context.Context, id int64) error
 }
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+type UsernameHistoryRepository interface {
+	Create(ctx context.Context, change *entity.UsernameChange) error
+	// ListByUserID returns the renames of a user, most recent first
+	ListByUserID(ctx context.Context, userID int64) ([]*entity.UsernameChange, error)
+	// IsReserved reports whether username was given up by a user other than exceptUserID
+	// at or after since, i.e. it is still inside its reservation window
+	IsReserved(ctx context.Context, username string, since time.Time, exceptUserID int64) (bool, error)
+	// DeleteByUserID removes the rename history of a user
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+// DataExportUseCase defines the personal-data export (GDPR "right of access") operations
+type DataExportUseCase interface {
+	// RequestExport queues an export of everything held about the user
+	// If an export is already queued or being built, that export is returned instead
+	RequestExport(ctx context.Context, userID int64) (*entity.DataExport, error)
+
+	// ProcessPendingExports builds the archives of queued exports and notifies their owners
+	// It returns the number of exports that became ready
+	ProcessPendingExports(ctx context.Context) (int, error)
+
+	// OpenExport resolves a signed download token to a ready export and opens its archive
+	// The caller must close the returned reader
+	OpenExport(ctx context.Context, token string) (*entity.DataExport, io.ReadCloser, error)
+
+	// PurgeExpiredExports deletes exports, and their archives, whose download window has passed
+	// It returns the number of exports removed
+	PurgeExpiredExports(ctx context.Context) (int, error)
+}
+
+// DataExporter contributes one category of personal data to an export.
+// Every subsystem that stores data about users registers an exporter, so the
+// export stays complete as the application grows.
+type DataExporter interface {
+	// Category names the archive directory the exporter writes into, e.g. "profile"
+	Category() string
+
+	// Export writes everything the subsystem holds about user into archive
+	Export(ctx context.Context, user *entity.User, archive ExportArchive) error
+}
+
+// ExportArchive receives the files of one export category.
+// Names are slash-separated and relative to the category directory.
+type ExportArchive interface {
+	// WriteJSON stores v as an indented JSON document
+	WriteJSON(name string, v any) error
+
+	// WriteFile copies the contents of r into the archive
+	WriteFile(name string, r io.Reader) error
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+// EmailChangeUseCase defines the re-verified email change flow
+type EmailChangeUseCase interface {
+	// RequestEmailChange starts moving the user's account to a new address after re-checking the password
+	// A confirmation link is sent to the new address and a revert link to the current one;
+	// the account email itself is left untouched until the change is confirmed
+	RequestEmailChange(ctx context.Context, userID int64, req *entity.ChangeEmailRequest) (*entity.EmailChange, error)
+
+	// ConfirmEmailChange applies the email change behind a confirmation token and returns the updated user
+	// The new address is checked for availability again at this point
+	ConfirmEmailChange(ctx context.Context, token string) (*entity.User, error)
+
+	// RevertEmailChange undoes the email change behind a revert token and returns the user
+	// A pending change is cancelled; a confirmed one restores the previous address
+	RevertEmailChange(ctx context.Context, token string) (*entity.User, error)
+}
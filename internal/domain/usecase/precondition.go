@@ -0,0 +1,19 @@
+package usecase
+
+import "context"
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion returns a copy of ctx that carries the version of the
+// target resource the caller last saw (HTTP If-Match). Use cases that modify
+// a versioned resource refuse to do so if its current version differs.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// ExpectedVersion returns the version attached by WithExpectedVersion.
+// The second result is false if the caller did not state a precondition.
+func ExpectedVersion(ctx context.Context) (int64, bool) {
+	version, ok := ctx.Value(expectedVersionKey{}).(int64)
+	return version, ok
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+// PreferenceUseCase defines the operations on per-user preferences
+type PreferenceUseCase interface {
+	// GetPreferences returns every registered preference of the user
+	// Keys the user has not set carry their default value
+	GetPreferences(ctx context.Context, userID int64) (entity.Preferences, error)
+
+	// UpdatePreferences applies a merge-patch to the user's preferences and returns the result
+	// Values are validated against the preference registry; null resets a key to its default
+	UpdatePreferences(ctx context.Context, userID int64, req entity.UpdatePreferencesRequest) (entity.Preferences, error)
+}
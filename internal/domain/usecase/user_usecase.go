@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"io"
 
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
 )
@@ -12,11 +13,27 @@ type UserUseCase interface {
 	// It takes a user ID and returns the corresponding User entity
 	GetUserByID(ctx context.Context, id int64) (*entity.User, error)
 
-	// UpdateUser updates the information of an existing user
-	// It takes a User entity with updated information and returns an error if the operation fails
-	UpdateUser(ctx context.Context, user *entity.User) error
+	// ListUsers returns a filtered, paginated list of users
+	// It supports both page-number and opaque cursor pagination
+	ListUsers(ctx context.Context, req *entity.ListUsersRequest) (*entity.ListUsersResponse, error)
 
-	// SoftDeleteUser marks a user as deleted in the system
-	// It takes a user ID and returns an error if the operation fails
-	SoftDeleteUser(ctx context.Context, id int64) error
+	// UpdateProfile applies a merge-patch to the profile of the user with the given ID
+	// Only fields present in the request are written; it returns the updated user
+	UpdateProfile(ctx context.Context, id int64, req *entity.UpdateProfileRequest) (*entity.User, error)
+
+	// ChangeUsername renames the user with the given ID and records the old name in the history
+	// Renames are rate-limited by a cooldown, and released names stay reserved for their previous owner
+	ChangeUsername(ctx context.Context, id int64, req *entity.ChangeUsernameRequest) (*entity.User, error)
+
+	// UploadAvatar stores the image read from file as the avatar of the user with the given ID
+	// The image is validated, resized to the standard thumbnail sizes and replaces any previous upload
+	UploadAvatar(ctx context.Context, id int64, file io.Reader) (*entity.AvatarResponse, error)
+
+	// DeleteAccount soft-deletes the user's own account after re-checking the password
+	// The account can be restored by logging in until the grace period ends
+	DeleteAccount(ctx context.Context, id int64, req *entity.DeleteAccountRequest) error
+
+	// PurgeDeletedAccounts permanently removes or anonymizes accounts whose grace period has expired
+	// It returns the number of accounts purged
+	PurgeDeletedAccounts(ctx context.Context) (int, error)
 }
@@ -0,0 +1,151 @@
+// Package eventbus implements gateway.EventPublisher with an in-process bus.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+// Handler reacts to a published event. Its error is logged; it never affects
+// the use case that published the event.
+type Handler func(ctx context.Context, e event.Event) error
+
+type subscriber struct {
+	handler Handler
+	async   bool
+}
+
+// Bus delivers events to the handlers subscribed to their name.
+//
+// Events published inside TxManager.WithTx are held back until the
+// transaction commits and dropped if it rolls back, so subscribers never see
+// a change that did not happen. Delivery is at most once: events still in
+// flight when the process exits are lost.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+	wg          sync.WaitGroup
+}
+
+// NewBus creates a Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[string][]subscriber)}
+}
+
+// Subscribe registers h for events named name. h runs synchronously after
+// the commit, in the goroutine of the publishing request, so it should be
+// quick.
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.subscribe(name, subscriber{handler: h})
+}
+
+// SubscribeAsync registers h for events named name. h runs in its own
+// goroutine, with a context that is not cancelled when the request ends.
+func (b *Bus) SubscribeAsync(name string, h Handler) {
+	b.subscribe(name, subscriber{handler: h, async: true})
+}
+
+func (b *Bus) subscribe(name string, s subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[name] = append(b.subscribers[name], s)
+}
+
+// On subscribes a handler for events of type E:
+//
+//	eventbus.On(bus, func(ctx context.Context, e event.UserRegistered) error {
+//	    return mailer.Welcome(ctx, e.Email)
+//	})
+func On[E event.Event](b *Bus, h func(ctx context.Context, e E) error) {
+	b.Subscribe(eventName[E](), typed(h))
+}
+
+// OnAsync is like On, but the handler runs as with SubscribeAsync.
+func OnAsync[E event.Event](b *Bus, h func(ctx context.Context, e E) error) {
+	b.SubscribeAsync(eventName[E](), typed(h))
+}
+
+func eventName[E event.Event]() string {
+	var zero E
+	return zero.EventName()
+}
+
+func typed[E event.Event](h func(ctx context.Context, e E) error) Handler {
+	return func(ctx context.Context, e event.Event) error {
+		typed, ok := e.(E)
+		if !ok {
+			return nil
+		}
+		return h(ctx, typed)
+	}
+}
+
+// Publish implements gateway.EventPublisher. It hands events to the
+// subscribers once the transaction of ctx commits, or right away outside a
+// transaction. It never fails.
+func (b *Bus) Publish(ctx context.Context, events ...event.Event) error {
+	repository.AfterCommit(ctx, func(ctx context.Context) {
+		for _, e := range events {
+			_ = b.dispatch(ctx, e) // 错误已记录；需要重试的事件应经由 outbox 投递
+		}
+	})
+	return nil
+}
+
+// Deliver hands e to its subscribers right away, ignoring any transaction of
+// ctx, and returns the errors of the synchronous handlers. It is meant for
+// relays that redeliver on failure, such as the outbox: handlers should then
+// tolerate seeing an event more than once (see event.IdempotencyKey).
+// Asynchronous handlers are started but not waited for.
+func (b *Bus) Deliver(ctx context.Context, e event.Event) error {
+	return b.dispatch(ctx, e)
+}
+
+// Wait blocks until the asynchronous handlers have finished. Call it during
+// shutdown, after the last event has been published.
+func (b *Bus) Wait() {
+	b.wg.Wait()
+}
+
+// dispatch runs the subscribers of e and returns the joined errors of the
+// synchronous ones.
+func (b *Bus) dispatch(ctx context.Context, e event.Event) error {
+	b.mu.RLock()
+	subscribers := b.subscribers[e.EventName()]
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscribers {
+		if s.async {
+			asyncCtx := context.WithoutCancel(ctx)
+			b.wg.Go(func() { _ = deliver(asyncCtx, s.handler, e) })
+			continue
+		}
+		errs = append(errs, deliver(ctx, s.handler, e))
+	}
+	return errors.Join(errs...)
+}
+
+// deliver runs h, logging and returning its error or panic.
+func deliver(ctx context.Context, h Handler, e event.Event) (err error) {
+	log := logger.FromContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+		if err != nil {
+			log.Errorf("event %s: %v", e.EventName(), err)
+		}
+	}()
+
+	if err := h(ctx, e); err != nil {
+		return fmt.Errorf("handler failed: %w", err)
+	}
+	return nil
+}
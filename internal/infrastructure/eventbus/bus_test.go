@@ -0,0 +1,110 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+)
+
+func TestBus_DeliversToSubscribersByName(t *testing.T) {
+	bus := NewBus()
+	var registered, deleted []event.Event
+	bus.Subscribe(event.NameUserRegistered, func(_ context.Context, e event.Event) error {
+		registered = append(registered, e)
+		return nil
+	})
+	bus.Subscribe(event.NameAccountDeleted, func(_ context.Context, e event.Event) error {
+		deleted = append(deleted, e)
+		return nil
+	})
+
+	require.NoError(t, bus.Publish(context.Background(), event.UserRegistered{UserID: 1}))
+
+	assert.Equal(t, []event.Event{event.UserRegistered{UserID: 1}}, registered)
+	assert.Empty(t, deleted)
+}
+
+func TestBus_TypedAndAsyncHandlers(t *testing.T) {
+	bus := NewBus()
+	var typed, async atomic.Int64
+	On(bus, func(_ context.Context, e event.AccountPurged) error {
+		typed.Store(e.UserID)
+		return nil
+	})
+	OnAsync(bus, func(_ context.Context, e event.AccountPurged) error {
+		async.Store(e.UserID)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, bus.Publish(ctx, event.AccountPurged{UserID: 7}))
+	cancel()
+	bus.Wait()
+
+	assert.Equal(t, int64(7), typed.Load())
+	assert.Equal(t, int64(7), async.Load(), "async handlers outlive the request context")
+}
+
+func TestBus_DefersUntilCommit(t *testing.T) {
+	bus := NewBus()
+	var delivered int
+	bus.Subscribe(event.NameAccountDeleted, func(context.Context, event.Event) error {
+		delivered++
+		return nil
+	})
+
+	// 回滚的事务不投递事件
+	txCtx, end := repository.WithCommitHooks(context.Background())
+	require.NoError(t, bus.Publish(txCtx, event.AccountDeleted{UserID: 1}))
+	end(false)
+	assert.Zero(t, delivered)
+
+	txCtx, end = repository.WithCommitHooks(context.Background())
+	require.NoError(t, bus.Publish(txCtx, event.AccountDeleted{UserID: 1}))
+	assert.Zero(t, delivered, "held back until commit")
+	end(true)
+	assert.Equal(t, 1, delivered)
+}
+
+func TestBus_HandlerFailuresAreIsolated(t *testing.T) {
+	bus := NewBus()
+	var delivered bool
+	bus.Subscribe(event.NameAccountRestored, func(context.Context, event.Event) error {
+		panic("boom")
+	})
+	bus.Subscribe(event.NameAccountRestored, func(context.Context, event.Event) error {
+		return errors.New("failed")
+	})
+	bus.Subscribe(event.NameAccountRestored, func(context.Context, event.Event) error {
+		delivered = true
+		return nil
+	})
+
+	assert.NoError(t, bus.Publish(context.Background(), event.AccountRestored{UserID: 1}))
+	assert.True(t, delivered)
+}
+
+func TestBus_DeliverReturnsHandlerErrors(t *testing.T) {
+	bus := NewBus()
+	failure := errors.New("failed")
+	bus.Subscribe(event.NameAccountDeleted, func(context.Context, event.Event) error { return failure })
+	bus.Subscribe(event.NameAccountDeleted, func(context.Context, event.Event) error { panic("boom") })
+	bus.SubscribeAsync(event.NameAccountDeleted, func(context.Context, event.Event) error { return failure })
+
+	// Deliver 不等待事务提交
+	txCtx, end := repository.WithCommitHooks(context.Background())
+	defer end(false)
+	err := bus.Deliver(txCtx, event.AccountDeleted{UserID: 1})
+	bus.Wait()
+
+	assert.ErrorIs(t, err, failure)
+	assert.ErrorContains(t, err, "panicked")
+	assert.NoError(t, bus.Deliver(context.Background(), event.AccountRestored{UserID: 1}))
+}
@@ -0,0 +1,29 @@
+// Package notification implements gateway.Notifier.
+package notification
+
+import (
+	"context"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier returns a Notifier that writes messages to the application log
+// instead of delivering them. It stands in for a real mail provider during
+// development; messages may contain sign-in or download links, so do not use
+// it where logs are shared.
+func NewLogNotifier() gateway.Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(ctx context.Context, n *entity.Notification) error {
+	logger.FromContext(ctx).Log(ctx, logger.InfoLevel, "notification: "+n.Subject, logger.Fields{
+		"user_id": n.UserID,
+		"to":      n.To,
+		"body":    n.Body,
+	})
+	return nil
+}
@@ -0,0 +1,26 @@
+package outbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// backlogMessages is the number of messages waiting to be delivered
+	backlogMessages = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "outbox_backlog_messages",
+		Help: "Number of outbox messages waiting to be delivered.",
+	})
+
+	// backlogAge is the age of the oldest message waiting to be delivered
+	backlogAge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "outbox_backlog_oldest_age_seconds",
+		Help: "Age in seconds of the oldest outbox message waiting to be delivered, 0 if there is none.",
+	})
+
+	// deliveries counts delivery attempts by result: published, retry or dead
+	deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbox_deliveries_total",
+		Help: "Outbox delivery attempts, partitioned by result (published, retry, dead).",
+	}, []string{"result"})
+)
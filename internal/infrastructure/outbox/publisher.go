@@ -0,0 +1,58 @@
+// Package outbox implements the transactional outbox: events are stored in
+// the database together with the change that raised them, and a relay
+// delivers them from there, retrying until it succeeds.
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+)
+
+type publisher struct {
+	repo repository.OutboxRepository
+}
+
+// NewPublisher returns an EventPublisher that stores events in the outbox.
+// Published inside TxManager.WithTx, the events are committed or rolled back
+// together with the transaction, so no event is lost once the change has
+// been made, even if the process dies right after the commit.
+func NewPublisher(repo repository.OutboxRepository) gateway.EventPublisher {
+	return &publisher{repo: repo}
+}
+
+func (p *publisher) Publish(ctx context.Context, events ...event.Event) error {
+	now := time.Now().UTC()
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encode event %s: %w", e.EventName(), err)
+		}
+		msg := &entity.OutboxMessage{
+			EventName:      e.EventName(),
+			Payload:        payload,
+			IdempotencyKey: newIdempotencyKey(),
+			Status:         entity.OutboxPending,
+			NextAttemptAt:  now,
+		}
+		if err := p.repo.Create(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newIdempotencyKey returns a random 128-bit key in hex.
+func newIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // crypto/rand.Read 不会返回错误
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,47 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+)
+
+func TestPublisher_StoresEvents(t *testing.T) {
+	repo := testmock.NewMockOutboxRepository(t)
+	var stored []*entity.OutboxMessage
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*entity.OutboxMessage")).
+		Run(func(args mock.Arguments) { stored = append(stored, args.Get(1).(*entity.OutboxMessage)) }).
+		Return(nil)
+
+	err := NewPublisher(repo).Publish(context.Background(),
+		event.AccountDeleted{UserID: 1}, event.AccountRestored{UserID: 1})
+
+	assert.NoError(t, err)
+	if assert.Len(t, stored, 2) {
+		msg := stored[0]
+		assert.Equal(t, event.NameAccountDeleted, msg.EventName)
+		assert.JSONEq(t, `{"user_id":"1"}`, string(msg.Payload))
+		assert.Equal(t, entity.OutboxPending, msg.Status)
+		assert.WithinDuration(t, time.Now(), msg.NextAttemptAt, time.Second)
+		assert.Len(t, msg.IdempotencyKey, 32)
+		assert.NotEqual(t, msg.IdempotencyKey, stored[1].IdempotencyKey)
+	}
+}
+
+func TestPublisher_CreateFails(t *testing.T) {
+	repo := testmock.NewMockOutboxRepository(t)
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db down")).Once()
+
+	err := NewPublisher(repo).Publish(context.Background(),
+		event.AccountDeleted{UserID: 1}, event.AccountRestored{UserID: 1})
+
+	assert.Error(t, err, "the caller's transaction must roll back")
+}
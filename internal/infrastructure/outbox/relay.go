@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+// Sink receives the events the relay delivers. A non-nil error makes the
+// relay try again later. eventbus.Bus is a Sink.
+type Sink interface {
+	Deliver(ctx context.Context, e event.Event) error
+}
+
+// RelayConfig configures a Relay.
+type RelayConfig struct {
+	BatchSize   int           // 每轮最多投递的消息数，默认 100
+	MaxAttempts int           // 投递失败达到该次数后标记为 dead，不再重试
+	RetryBase   time.Duration // 第一次失败后的重试间隔，此后每次失败翻倍
+	RetryMax    time.Duration // 重试间隔的上限
+	Retention   time.Duration // 已投递消息的保留时长
+}
+
+// Relay delivers the messages of the outbox to a Sink.
+//
+// Delivery is at least once: a message is redelivered if the relay dies
+// between delivering it and recording that, or if its delivery takes longer
+// than the retry interval. Every delivery of a message carries the same
+// idempotency key (see event.IdempotencyKey) so that consumers can drop
+// duplicates. Several relays may run at once, e.g. one per replica; each
+// message is claimed by one of them per attempt.
+type Relay struct {
+	repo   repository.OutboxRepository
+	sink   Sink
+	config RelayConfig
+}
+
+// NewRelay creates a Relay.
+func NewRelay(repo repository.OutboxRepository, sink Sink, config RelayConfig) *Relay {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	return &Relay{repo: repo, sink: sink, config: config}
+}
+
+// Run delivers the due messages, removes expired published ones and updates
+// the backlog metrics. It is meant to be run periodically as a job.
+func (r *Relay) Run(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	published, relayErr := r.RelayDue(ctx)
+	if published > 0 {
+		log.Infof("published %d outbox messages", published)
+	}
+	purged, purgeErr := r.repo.DeletePublishedBefore(ctx, time.Now().Add(-r.config.Retention), r.config.BatchSize)
+	if purged > 0 {
+		log.Infof("removed %d published outbox messages", purged)
+	}
+	return errors.Join(relayErr, purgeErr, r.reportBacklog(ctx))
+}
+
+// RelayDue delivers up to BatchSize due messages and returns how many were
+// published.
+func (r *Relay) RelayDue(ctx context.Context) (int, error) {
+	messages, err := r.repo.ListDue(ctx, time.Now(), r.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, msg := range messages {
+		if err := ctx.Err(); err != nil {
+			return published, err
+		}
+		// 领取时就把下次尝试推迟到重试时间：若本实例在投递中途退出，消息会在那时被重新投递
+		retryAt := time.Now().Add(r.backoff(msg.Attempts + 1))
+		if err := r.repo.Claim(ctx, msg, retryAt); err != nil {
+			if errors.Is(err, domainerrors.ErrNoRowsAffected) {
+				continue // 已被其他实例领取
+			}
+			return published, err
+		}
+
+		if err := r.settle(ctx, msg, r.deliver(ctx, msg)); err != nil {
+			return published, err
+		}
+		if msg.Status == entity.OutboxPublished {
+			published++
+		}
+	}
+	return published, nil
+}
+
+// deliver decodes msg and hands it to the sink.
+func (r *Relay) deliver(ctx context.Context, msg *entity.OutboxMessage) error {
+	e, err := event.Decode(msg.EventName, msg.Payload)
+	if err != nil {
+		return err
+	}
+	return r.sink.Deliver(event.WithIdempotencyKey(ctx, msg.IdempotencyKey), e)
+}
+
+// settle records the outcome of a delivery attempt.
+func (r *Relay) settle(ctx context.Context, msg *entity.OutboxMessage, deliveryErr error) error {
+	switch {
+	case deliveryErr == nil:
+		now := time.Now().UTC()
+		msg.Status = entity.OutboxPublished
+		msg.PublishedAt = &now
+		msg.LastError = nil
+		deliveries.WithLabelValues("published").Inc()
+	case msg.Attempts >= r.config.MaxAttempts:
+		text := deliveryErr.Error()
+		msg.Status = entity.OutboxDead
+		msg.LastError = &text
+		deliveries.WithLabelValues("dead").Inc()
+		logger.FromContext(ctx).Errorf("outbox message %d (%s) failed %d times, giving up: %v",
+			msg.ID, msg.EventName, msg.Attempts, deliveryErr)
+	default:
+		// 保持 pending，NextAttemptAt 已在领取时设为重试时间
+		text := deliveryErr.Error()
+		msg.LastError = &text
+		deliveries.WithLabelValues("retry").Inc()
+		logger.FromContext(ctx).Warnf("outbox message %d (%s) failed, retrying at %s: %v",
+			msg.ID, msg.EventName, msg.NextAttemptAt.Format(time.RFC3339), deliveryErr)
+	}
+	return r.repo.Update(ctx, msg)
+}
+
+// backoff returns the delay before the attempt after the given one:
+// RetryBase doubled for every earlier attempt, at most RetryMax.
+func (r *Relay) backoff(attempt int) time.Duration {
+	delay := r.config.RetryBase
+	for i := 1; i < attempt && delay < r.config.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.RetryMax)
+}
+
+// reportBacklog updates the backlog metrics.
+func (r *Relay) reportBacklog(ctx context.Context) error {
+	backlog, err := r.repo.Backlog(ctx)
+	if err != nil {
+		return err
+	}
+	backlogMessages.Set(float64(backlog.Pending))
+	age := 0.0
+	if backlog.OldestPending != nil {
+		age = time.Since(*backlog.OldestPending).Seconds()
+	}
+	backlogAge.Set(age)
+	return nil
+}
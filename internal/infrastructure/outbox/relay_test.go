@@ -0,0 +1,157 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+)
+
+// sinkFunc adapts a function to Sink.
+type sinkFunc func(ctx context.Context, e event.Event) error
+
+func (f sinkFunc) Deliver(ctx context.Context, e event.Event) error { return f(ctx, e) }
+
+var testRelayConfig = RelayConfig{
+	MaxAttempts: 3,
+	RetryBase:   time.Second,
+	RetryMax:    10 * time.Second,
+	Retention:   time.Hour,
+}
+
+func pendingMessage(id int64, attempts int) *entity.OutboxMessage {
+	return &entity.OutboxMessage{
+		ID:             id,
+		EventName:      event.NameAccountDeleted,
+		Payload:        []byte(`{"user_id":"42"}`),
+		IdempotencyKey: "key-1",
+		Status:         entity.OutboxPending,
+		Attempts:       attempts,
+	}
+}
+
+// expectClaim makes Claim behave like the repository: it records the attempt.
+func expectClaim(repo *testmock.MockOutboxRepository, msg *entity.OutboxMessage) *mock.Call {
+	return repo.On("Claim", mock.Anything, msg, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			msg.Attempts++
+			msg.NextAttemptAt = args.Get(2).(time.Time)
+		}).Return(nil)
+}
+
+func TestRelay_PublishesDueMessages(t *testing.T) {
+	repo := testmock.NewMockOutboxRepository(t)
+	msg := pendingMessage(1, 0)
+	repo.On("ListDue", mock.Anything, mock.Anything, 100).Return([]*entity.OutboxMessage{msg}, nil)
+	expectClaim(repo, msg)
+	repo.On("Update", mock.Anything, msg).Return(nil)
+
+	var delivered event.Event
+	var key string
+	relay := NewRelay(repo, sinkFunc(func(ctx context.Context, e event.Event) error {
+		delivered = e
+		key, _ = event.IdempotencyKey(ctx)
+		return nil
+	}), testRelayConfig)
+
+	published, err := relay.RelayDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, event.AccountDeleted{UserID: 42}, delivered)
+	assert.Equal(t, "key-1", key)
+	assert.Equal(t, entity.OutboxPublished, msg.Status)
+	assert.NotNil(t, msg.PublishedAt)
+}
+
+func TestRelay_FailedDeliveryIsRetriedWithBackoff(t *testing.T) {
+	repo := testmock.NewMockOutboxRepository(t)
+	msg := pendingMessage(1, 1)
+	repo.On("ListDue", mock.Anything, mock.Anything, 100).Return([]*entity.OutboxMessage{msg}, nil)
+	expectClaim(repo, msg)
+	repo.On("Update", mock.Anything, msg).Return(nil)
+	relay := NewRelay(repo, sinkFunc(func(context.Context, event.Event) error {
+		return errors.New("mailer down")
+	}), testRelayConfig)
+
+	published, err := relay.RelayDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, published)
+	assert.Equal(t, entity.OutboxPending, msg.Status)
+	assert.Equal(t, 2, msg.Attempts)
+	require.NotNil(t, msg.LastError)
+	assert.Equal(t, "mailer down", *msg.LastError)
+	// 第二次失败后等待 2 × RetryBase
+	assert.WithinDuration(t, time.Now().Add(2*time.Second), msg.NextAttemptAt, 500*time.Millisecond)
+}
+
+func TestRelay_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo := testmock.NewMockOutboxRepository(t)
+	msg := pendingMessage(1, 2)
+	msg.EventName = "user.unknown" // 无法解码的消息同样按失败处理
+	repo.On("ListDue", mock.Anything, mock.Anything, 100).Return([]*entity.OutboxMessage{msg}, nil)
+	expectClaim(repo, msg)
+	repo.On("Update", mock.Anything, msg).Return(nil)
+	relay := NewRelay(repo, sinkFunc(func(context.Context, event.Event) error {
+		t.Fatal("undecodable messages are not delivered")
+		return nil
+	}), testRelayConfig)
+
+	_, err := relay.RelayDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.OutboxDead, msg.Status)
+	assert.Equal(t, 3, msg.Attempts)
+}
+
+func TestRelay_SkipsMessagesClaimedElsewhere(t *testing.T) {
+	repo := testmock.NewMockOutboxRepository(t)
+	taken, free := pendingMessage(1, 0), pendingMessage(2, 0)
+	repo.On("ListDue", mock.Anything, mock.Anything, 100).Return([]*entity.OutboxMessage{taken, free}, nil)
+	repo.On("Claim", mock.Anything, taken, mock.Anything).Return(domainerrors.ErrNoRowsAffected)
+	expectClaim(repo, free)
+	repo.On("Update", mock.Anything, free).Return(nil)
+	var deliveries int
+	relay := NewRelay(repo, sinkFunc(func(context.Context, event.Event) error {
+		deliveries++
+		return nil
+	}), testRelayConfig)
+
+	published, err := relay.RelayDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 1, deliveries)
+}
+
+func TestRelay_Run(t *testing.T) {
+	repo := testmock.NewMockOutboxRepository(t)
+	repo.On("ListDue", mock.Anything, mock.Anything, 100).Return(nil, nil)
+	repo.On("DeletePublishedBefore", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= time.Hour && time.Since(before) < time.Hour+time.Minute
+	}), 100).Return(3, nil)
+	repo.On("Backlog", mock.Anything).Return(nil, errors.New("db down"))
+	relay := NewRelay(repo, sinkFunc(func(context.Context, event.Event) error { return nil }), testRelayConfig)
+
+	assert.Error(t, relay.Run(context.Background()))
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(nil, nil, testRelayConfig)
+
+	assert.Equal(t, time.Second, relay.backoff(1))
+	assert.Equal(t, 2*time.Second, relay.backoff(2))
+	assert.Equal(t, 8*time.Second, relay.backoff(4))
+	assert.Equal(t, 10*time.Second, relay.backoff(5), "capped at RetryMax")
+	assert.Equal(t, 10*time.Second, relay.backoff(100))
+}
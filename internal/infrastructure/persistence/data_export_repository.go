@@ -0,0 +1,181 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+type dataExportRepository struct {
+	db database.Database
+}
+
+// NewDataExportRepository creates a new instance of DataExportRepository
+func NewDataExportRepository(db database.Database) repository.DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+// Create inserts a new export into the database
+func (r *dataExportRepository) Create(ctx context.Context, export *entity.DataExport) error {
+	dto := model.DataExportDTO{}
+	dto.ConvertFromEntity(export)
+
+	if err := dbFromContext(ctx, r.db).Create(&dto).Error; err != nil {
+		return err
+	}
+
+	*export = *dto.ConvertToEntity()
+	return nil
+}
+
+// FindByID retrieves an export by its ID
+func (r *dataExportRepository) FindByID(ctx context.Context, id int64) (*entity.DataExport, error) {
+	var dto model.DataExportDTO
+	err := readDBFromContext(ctx, r.db).First(&dto, id).Error
+	return r.handleQueryResult(&dto, err)
+}
+
+// Update writes every mutable column of an existing export, provided the row
+// still has export.Version; a worker whose stale claim was taken over
+// therefore cannot overwrite the result of the new one
+func (r *dataExportRepository) Update(ctx context.Context, export *entity.DataExport) error {
+	var dto model.DataExportDTO
+	dto.ConvertFromEntity(export)
+	err := updateVersioned(dbFromContext(ctx, r.db), &dto, &dto.BaseModel,
+		"status", "object_key", "size", "expires_at", "completed_at")
+	if err != nil {
+		return err
+	}
+
+	export.UpdatedAt = dto.UpdatedAt
+	export.Version = dto.Version
+	return nil
+}
+
+// Delete permanently removes an export record; its archive must be deleted separately
+func (r *dataExportRepository) Delete(ctx context.Context, id int64) error {
+	result := dbFromContext(ctx, r.db).Unscoped().Delete(&model.DataExportDTO{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrNoRowsAffected
+	}
+	return nil
+}
+
+// FindActiveByUserID retrieves the user's pending or processing export
+func (r *dataExportRepository) FindActiveByUserID(ctx context.Context, userID int64) (*entity.DataExport, error) {
+	var dto model.DataExportDTO
+	err := readDBFromContext(ctx, r.db).
+		Where("user_id = ? AND status IN ?", userID, []string{
+			string(entity.DataExportPending), string(entity.DataExportProcessing),
+		}).
+		Order("id DESC").First(&dto).Error
+	return r.handleQueryResult(&dto, err)
+}
+
+// claimable scopes a query to exports a worker may pick up: pending ones, and
+// processing ones whose worker has not touched them since staleBefore.
+func claimable(staleBefore time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? OR (status = ? AND updated_at < ?)",
+			entity.DataExportPending, entity.DataExportProcessing, staleBefore.UTC())
+	}
+}
+
+// ListClaimable retrieves exports waiting to be built, oldest ID first
+func (r *dataExportRepository) ListClaimable(ctx context.Context, staleBefore time.Time, limit int) ([]*entity.DataExport, error) {
+	var dtos []model.DataExportDTO
+	err := readDBFromContext(ctx, r.db).Scopes(claimable(staleBefore)).
+		Order("id ASC").Limit(limit).
+		Find(&dtos).Error
+	if err != nil {
+		return nil, err
+	}
+	return convertDataExports(dtos), nil
+}
+
+// Claim marks an export as processing unless another worker already holds it.
+// The conditional UPDATE makes the claim atomic across replicas; bumping the
+// version fences off the writes of the worker whose stale claim is taken over.
+func (r *dataExportRepository) Claim(ctx context.Context, export *entity.DataExport, staleBefore time.Time) error {
+	now := time.Now().UTC()
+	result := dbFromContext(ctx, r.db).Model(&model.DataExportDTO{}).
+		Scopes(claimable(staleBefore)).Where("id = ? AND version = ?", export.ID, export.Version).
+		Updates(map[string]any{
+			"status":     entity.DataExportProcessing,
+			"updated_at": now,
+			"version":    export.Version + 1,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrNoRowsAffected
+	}
+
+	export.Status = entity.DataExportProcessing
+	export.UpdatedAt = &now
+	export.Version++
+	return nil
+}
+
+// ListByUserID retrieves every export of a user, oldest ID first
+func (r *dataExportRepository) ListByUserID(ctx context.Context, userID int64) ([]*entity.DataExport, error) {
+	var dtos []model.DataExportDTO
+	err := readDBFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&dtos).Error
+	if err != nil {
+		return nil, err
+	}
+	return convertDataExports(dtos), nil
+}
+
+// DeleteByUserID permanently removes every export record of a user
+func (r *dataExportRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	return dbFromContext(ctx, r.db).Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&model.DataExportDTO{}).Error
+}
+
+// ListExpired retrieves finished exports that expired before the given time, oldest ID first
+func (r *dataExportRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*entity.DataExport, error) {
+	var dtos []model.DataExportDTO
+	err := readDBFromContext(ctx, r.db).
+		Where("expires_at < ?", before.UTC()).
+		Order("id ASC").Limit(limit).
+		Find(&dtos).Error
+	if err != nil {
+		return nil, err
+	}
+	return convertDataExports(dtos), nil
+}
+
+func convertDataExports(dtos []model.DataExportDTO) []*entity.DataExport {
+	exports := make([]*entity.DataExport, 0, len(dtos))
+	for i := range dtos {
+		exports = append(exports, dtos[i].ConvertToEntity())
+	}
+	return exports
+}
+
+func (r *dataExportRepository) handleQueryResult(dto *model.DataExportDTO, err error) (*entity.DataExport, error) {
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return dto.ConvertToEntity(), nil
+}
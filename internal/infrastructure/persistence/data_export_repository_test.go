@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+)
+
+// A worker whose claim went stale is taken over by another; from then on
+// only the new claim may write the export.
+func TestDataExportRepository_StaleClaimTakeover(t *testing.T) {
+	repo := NewDataExportRepository(newTestDB(t))
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, &entity.DataExport{ID: 7, UserID: 42, Status: entity.DataExportPending}))
+
+	// 两个实例读到同一个待处理导出，只有一个能领取
+	listed, err := repo.ListClaimable(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	first := listed[0]
+	second := *first
+	require.NoError(t, repo.Claim(ctx, first, time.Now().Add(-time.Hour)))
+	assert.Equal(t, entity.DataExportProcessing, first.Status)
+	assert.ErrorIs(t, repo.Claim(ctx, &second, time.Now().Add(-time.Hour)), domainerrors.ErrNoRowsAffected)
+
+	// 第一个实例迟迟没有完成，领取过期后被第二个实例接手
+	staleBefore := time.Now().Add(time.Minute)
+	listed, err = repo.ListClaimable(ctx, staleBefore, 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	takeover := listed[0]
+	require.NoError(t, repo.Claim(ctx, takeover, staleBefore))
+
+	// 第一个实例随后完成，不能覆盖接手实例的结果
+	firstKey := "exports/42/first.zip"
+	first.Status = entity.DataExportReady
+	first.ObjectKey = &firstKey
+	assert.ErrorIs(t, repo.Update(ctx, first), domainerrors.ErrConcurrentModification)
+
+	secondKey := "exports/42/second.zip"
+	takeover.Status = entity.DataExportReady
+	takeover.ObjectKey = &secondKey
+	takeover.Size = 3
+	require.NoError(t, repo.Update(ctx, takeover))
+
+	stored, err := repo.FindByID(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DataExportReady, stored.Status)
+	require.NotNil(t, stored.ObjectKey)
+	assert.Equal(t, secondKey, *stored.ObjectKey)
+	assert.Equal(t, int64(3), stored.Size)
+	assert.Equal(t, takeover.Version, stored.Version)
+
+	// 已完成的导出不能再被领取
+	assert.ErrorIs(t, repo.Claim(ctx, stored, staleBefore), domainerrors.ErrNoRowsAffected)
+}
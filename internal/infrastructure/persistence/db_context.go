@@ -5,6 +5,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 	"github.com/kirklin/boot-backend-go-clean/pkg/database"
 )
 
@@ -26,3 +27,19 @@ func dbFromContext(ctx context.Context, fallback database.Database) *gorm.DB {
 	}
 	return fallback.DB().WithContext(ctx)
 }
+
+// readDBFromContext is dbFromContext for queries that only read. Outside a
+// transaction they go to a read replica if the database has any, unless ctx
+// was marked with repository.WithPrimary.
+//
+// Repository methods whose results may be slightly stale (Find*, List*,
+// Count) use this; everything that writes must use dbFromContext.
+func readDBFromContext(ctx context.Context, fallback database.Database) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	if replicas, ok := fallback.(database.ReadReplicas); ok && !repository.UsesPrimary(ctx) {
+		return replicas.Reader().WithContext(ctx)
+	}
+	return fallback.DB().WithContext(ctx)
+}
@@ -0,0 +1,78 @@
+package persistence
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres SQLSTATE codes and MySQL error numbers the repositories act on.
+const (
+	pgUniqueViolation      = "23505"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+
+	mysqlDuplicateEntry  = 1062
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+)
+
+// isTxConflict reports whether err means the transaction lost against a
+// concurrent one and may succeed if run again.
+func isTxConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock || mysqlErr.Number == mysqlLockWaitTimeout
+	}
+	return false
+}
+
+// uniqueViolation reports whether err is a unique constraint violation and
+// returns the name of the violated constraint, or "" if it is unknown.
+//
+// SQLite only reports the columns ("users.email"); for a single column the
+// name is derived from the convention the migrations follow,
+// uni_<table>_<column>.
+func uniqueViolation(err error) (constraint string, ok bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if pgErr.Code != pgUniqueViolation {
+			return "", false
+		}
+		return pgErr.ConstraintName, true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if mysqlErr.Number != mysqlDuplicateEntry {
+			return "", false
+		}
+		// Duplicate entry 'kirk' for key 'users.uni_users_username'（MySQL 8 之前没有表名前缀）
+		_, key, found := strings.Cut(mysqlErr.Message, " for key '")
+		if !found {
+			return "", true
+		}
+		key = strings.TrimSuffix(key, "'")
+		if i := strings.LastIndexByte(key, '.'); i >= 0 {
+			key = key[i+1:]
+		}
+		return key, true
+	}
+	if err == nil {
+		return "", false
+	}
+	_, columns, found := strings.Cut(err.Error(), "UNIQUE constraint failed: ")
+	if !found {
+		return "", false
+	}
+	table, column, single := strings.Cut(columns, ".")
+	if !single || strings.Contains(column, ",") {
+		return "", true
+	}
+	return "uni_" + table + "_" + column, true
+}
@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTxConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"pg serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"pg deadlock", fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40P01"}), true},
+		{"pg unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"mysql deadlock", &mysql.MySQLError{Number: 1213}, true},
+		{"mysql lock wait timeout", &mysql.MySQLError{Number: 1205}, true},
+		{"mysql duplicate entry", &mysql.MySQLError{Number: 1062}, false},
+		{"other", errors.New("failed"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isTxConflict(tt.err))
+		})
+	}
+}
+
+func TestUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantConstraint string
+		wantOK         bool
+	}{
+		{"pg", &pgconn.PgError{Code: "23505", ConstraintName: "uni_users_email"}, "uni_users_email", true},
+		{"pg other code", &pgconn.PgError{Code: "40001", ConstraintName: "uni_users_email"}, "", false},
+		{"mysql 8", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'kirk' for key 'users.uni_users_username'"}, "uni_users_username", true},
+		{"mysql 5.7", fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'kirk' for key 'uni_users_username'"}), "uni_users_username", true},
+		{"mysql other number", &mysql.MySQLError{Number: 1213}, "", false},
+		{"sqlite", errors.New("UNIQUE constraint failed: users.email"), "uni_users_email", true},
+		{"sqlite composite", errors.New("UNIQUE constraint failed: user_preferences.user_id, user_preferences.key"), "", true},
+		{"other", errors.New("failed"), "", false},
+		{"nil", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constraint, ok := uniqueViolation(tt.err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantConstraint, constraint)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/fieldcrypt"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/snowflake"
+)
+
+type emailChangeRepository struct {
+	db      database.Database
+	keyring *fieldcrypt.Keyring
+}
+
+// NewEmailChangeRepository creates a new instance of EmailChangeRepository.
+// The old and new emails are encrypted with keyring. Changes are only looked
+// up by ID and user, so the emails need no blind index.
+func NewEmailChangeRepository(db database.Database, keyring *fieldcrypt.Keyring) repository.EmailChangeRepository {
+	return &emailChangeRepository{db: db, keyring: keyring}
+}
+
+// emailChangeLocation returns the location column of email change id is
+// encrypted for.
+func emailChangeLocation(id int64, column string) fieldcrypt.Location {
+	return fieldcrypt.Location{Table: "email_changes", Column: column, RowID: id}
+}
+
+// seal encrypts the emails of dto.
+func (r *emailChangeRepository) seal(dto *model.EmailChangeDTO) error {
+	// 密文绑定行 ID，需要在加密前分配 ID（否则由 BeforeCreate 分配）
+	if dto.ID == 0 {
+		dto.ID = snowflake.NextID()
+	}
+	oldEmail, err := r.keyring.Encrypt(dto.OldEmail, emailChangeLocation(dto.ID, "old_email"))
+	if err != nil {
+		return err
+	}
+	newEmail, err := r.keyring.Encrypt(dto.NewEmail, emailChangeLocation(dto.ID, "new_email"))
+	if err != nil {
+		return err
+	}
+	dto.OldEmail, dto.NewEmail = oldEmail, newEmail
+	return nil
+}
+
+// open decrypts the emails of dto. Rows written before encryption was
+// introduced still hold plaintext, which is returned as is.
+func (r *emailChangeRepository) open(dto *model.EmailChangeDTO) error {
+	oldEmail, err := r.keyring.Decrypt(dto.OldEmail, emailChangeLocation(dto.ID, "old_email"))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt old email of email change %d: %w", dto.ID, err)
+	}
+	newEmail, err := r.keyring.Decrypt(dto.NewEmail, emailChangeLocation(dto.ID, "new_email"))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt new email of email change %d: %w", dto.ID, err)
+	}
+	dto.OldEmail, dto.NewEmail = oldEmail, newEmail
+	return nil
+}
+
+// Create inserts a new email change into the database
+func (r *emailChangeRepository) Create(ctx context.Context, change *entity.EmailChange) error {
+	dto := model.EmailChangeDTO{}
+	dto.ConvertFromEntity(change)
+	if err := r.seal(&dto); err != nil {
+		return err
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&dto).Error; err != nil {
+		return err
+	}
+
+	if err := r.open(&dto); err != nil {
+		return err
+	}
+	*change = *dto.ConvertToEntity()
+	return nil
+}
+
+// FindByID retrieves an email change by its ID
+func (r *emailChangeRepository) FindByID(ctx context.Context, id int64) (*entity.EmailChange, error) {
+	var dto model.EmailChangeDTO
+	err := readDBFromContext(ctx, r.db).First(&dto, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrEmailChangeNotFound
+		}
+		return nil, err
+	}
+	if err := r.open(&dto); err != nil {
+		return nil, err
+	}
+	return dto.ConvertToEntity(), nil
+}
+
+// Update writes the state of an existing email change, provided the row still
+// has change.Version; a link used twice concurrently therefore only applies once
+func (r *emailChangeRepository) Update(ctx context.Context, change *entity.EmailChange) error {
+	var dto model.EmailChangeDTO
+	dto.ConvertFromEntity(change)
+	err := updateVersioned(dbFromContext(ctx, r.db), &dto, &dto.BaseModel,
+		"status", "expires_at", "confirmed_at")
+	if err != nil {
+		return err
+	}
+
+	change.UpdatedAt = dto.UpdatedAt
+	change.Version = dto.Version
+	return nil
+}
+
+// ListByUserID retrieves the email changes of a user, most recent first
+func (r *emailChangeRepository) ListByUserID(ctx context.Context, userID int64) ([]*entity.EmailChange, error) {
+	var dtos []model.EmailChangeDTO
+	err := readDBFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").Order("id DESC").
+		Find(&dtos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*entity.EmailChange, 0, len(dtos))
+	for i := range dtos {
+		if err := r.open(&dtos[i]); err != nil {
+			return nil, err
+		}
+		changes = append(changes, dtos[i].ConvertToEntity())
+	}
+	return changes, nil
+}
+
+// CancelPendingByUserID cancels the pending email changes of a user, so that
+// only the links of the most recent request can still be confirmed
+func (r *emailChangeRepository) CancelPendingByUserID(ctx context.Context, userID int64) error {
+	return dbFromContext(ctx, r.db).Model(&model.EmailChangeDTO{}).
+		Where("user_id = ? AND status = ?", userID, entity.EmailChangePending).
+		Updates(map[string]any{"status": entity.EmailChangeCancelled, "version": bumpVersion}).Error
+}
+
+// DeleteByUserID permanently removes the email changes of a user
+func (r *emailChangeRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	return dbFromContext(ctx, r.db).Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&model.EmailChangeDTO{}).Error
+}
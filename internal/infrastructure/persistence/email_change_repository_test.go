@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/fieldcrypt"
+)
+
+func TestEmailChangeRepository_EncryptsEmails(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewEmailChangeRepository(db, newTestKeyring(t))
+	ctx := context.Background()
+	change := &entity.EmailChange{
+		ID: 10, UserID: 1, OldEmail: "kirk@example.com", NewEmail: "james@example.com",
+		Status: entity.EmailChangePending, ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, repo.Create(ctx, change))
+	assert.Equal(t, "kirk@example.com", change.OldEmail, "the entity keeps the plaintext")
+
+	var stored model.EmailChangeDTO
+	require.NoError(t, db.DB().First(&stored, 10).Error)
+	for _, value := range []string{stored.OldEmail, stored.NewEmail} {
+		assert.True(t, strings.HasPrefix(value, "enc:new:"), "ciphertext at rest")
+		assert.NotContains(t, value, "example.com")
+	}
+
+	found, err := repo.FindByID(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "kirk@example.com", found.OldEmail)
+	assert.Equal(t, "james@example.com", found.NewEmail)
+
+	// 密文绑定了列：交换新旧邮箱后无法解密
+	require.NoError(t, db.DB().Model(&model.EmailChangeDTO{}).Where("id = ?", 10).
+		UpdateColumns(map[string]any{"old_email": stored.NewEmail, "new_email": stored.OldEmail}).Error)
+	_, err = repo.FindByID(ctx, 10)
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+}
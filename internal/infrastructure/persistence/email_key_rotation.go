@@ -0,0 +1,131 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/fieldcrypt"
+)
+
+// EmailKeyRotation re-encrypts the emails stored in users and email_changes
+// that are not encrypted with the active key of the keyring: legacy
+// plaintext rows and rows sealed with a retired key. It also fills in missing
+// blind indexes of users. Once it reports no more rows, retired keys can be
+// removed from the configuration.
+type EmailKeyRotation struct {
+	db        database.Database
+	keyring   *fieldcrypt.Keyring
+	batchSize int
+}
+
+// NewEmailKeyRotation creates an EmailKeyRotation processing up to
+// batchSize rows of each table per run (100 if batchSize <= 0).
+func NewEmailKeyRotation(db database.Database, keyring *fieldcrypt.Keyring, batchSize int) *EmailKeyRotation {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &EmailKeyRotation{db: db, keyring: keyring, batchSize: batchSize}
+}
+
+// Run re-encrypts one batch of rows. It is meant to be run periodically as
+// a job, and is safe to run on several replicas at once.
+func (j *EmailKeyRotation) Run(ctx context.Context) error {
+	rotated, err := j.RotateBatch(ctx)
+	if rotated > 0 {
+		logger.FromContext(ctx).Infof("re-encrypted the emails of %d rows", rotated)
+	}
+	return err
+}
+
+// RotateBatch re-encrypts up to batchSize users and as many email changes,
+// and returns how many rows were updated.
+func (j *EmailKeyRotation) RotateBatch(ctx context.Context) (int, error) {
+	users, err := j.rotateUsers(ctx)
+	if err != nil {
+		return users, err
+	}
+	changes, err := j.rotateEmailChanges(ctx)
+	return users + changes, err
+}
+
+// rotateUsers re-encrypts the emails of up to batchSize users.
+func (j *EmailKeyRotation) rotateUsers(ctx context.Context) (int, error) {
+	var dtos []model.UserDTO
+	// 包含已注销的账号：它们的邮箱同样需要加密
+	err := dbFromContext(ctx, j.db).Unscoped().
+		Select("id", "email").
+		Where("email NOT LIKE ? ESCAPE ? OR email_index IS NULL", likePrefix(j.keyring.EncryptedPrefix()), likeEscape).
+		Order("id").Limit(j.batchSize).
+		Find(&dtos).Error
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for _, dto := range dtos {
+		if err := ctx.Err(); err != nil {
+			return rotated, err
+		}
+		email, err := j.keyring.Decrypt(dto.Email, userEmailLocation(dto.ID))
+		if err != nil {
+			return rotated, fmt.Errorf("failed to decrypt email of user %d: %w", dto.ID, err)
+		}
+		sealed, err := j.keyring.Encrypt(email, userEmailLocation(dto.ID))
+		if err != nil {
+			return rotated, err
+		}
+		// 只在邮箱未被并发修改时写入；不改变版本号，也不刷新 updated_at
+		result := dbFromContext(ctx, j.db).Unscoped().Model(&model.UserDTO{}).
+			Where("id = ? AND email = ?", dto.ID, dto.Email).
+			UpdateColumns(map[string]any{"email": sealed, "email_index": emailIndex(j.keyring, email)})
+		if result.Error != nil {
+			return rotated, result.Error
+		}
+		rotated += int(result.RowsAffected)
+	}
+	return rotated, nil
+}
+
+// rotateEmailChanges re-encrypts the emails of up to batchSize email changes.
+func (j *EmailKeyRotation) rotateEmailChanges(ctx context.Context) (int, error) {
+	var dtos []model.EmailChangeDTO
+	pattern := likePrefix(j.keyring.EncryptedPrefix())
+	err := dbFromContext(ctx, j.db).Unscoped().
+		Select("id", "old_email", "new_email").
+		Where("old_email NOT LIKE ? ESCAPE ? OR new_email NOT LIKE ? ESCAPE ?", pattern, likeEscape, pattern, likeEscape).
+		Order("id").Limit(j.batchSize).
+		Find(&dtos).Error
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for _, dto := range dtos {
+		if err := ctx.Err(); err != nil {
+			return rotated, err
+		}
+		columns := map[string]any{}
+		for column, value := range map[string]string{"old_email": dto.OldEmail, "new_email": dto.NewEmail} {
+			loc := emailChangeLocation(dto.ID, column)
+			email, err := j.keyring.Decrypt(value, loc)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to decrypt %s of email change %d: %w", column, dto.ID, err)
+			}
+			if columns[column], err = j.keyring.Encrypt(email, loc); err != nil {
+				return rotated, err
+			}
+		}
+		// 与用户邮箱相同，只在未被并发修改时写入，不改变版本号
+		result := dbFromContext(ctx, j.db).Unscoped().Model(&model.EmailChangeDTO{}).
+			Where("id = ? AND old_email = ? AND new_email = ?", dto.ID, dto.OldEmail, dto.NewEmail).
+			UpdateColumns(columns)
+		if result.Error != nil {
+			return rotated, result.Error
+		}
+		rotated += int(result.RowsAffected)
+	}
+	return rotated, nil
+}
@@ -0,0 +1,78 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/fieldcrypt"
+)
+
+func TestEmailKeyRotation_RotateBatch(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	keyring := newTestKeyring(t)
+
+	// 用旧密钥写入一行，再插入一行加密之前的明文
+	oldKeyring, err := fieldcrypt.NewKeyring(map[string][]byte{"old": bytes.Repeat([]byte{1}, 32)}, "old", []byte("index-secret"))
+	require.NoError(t, err)
+	createUser(t, NewUserRepository(db, oldKeyring), 1, "kirk")
+	require.NoError(t, db.DB().Exec(
+		"INSERT INTO users (id, created_at, version, username, email, password) VALUES (2, ?, 1, 'spock', 'spock@example.com', 'hashed')",
+		time.Now()).Error)
+	repo := NewUserRepository(db, keyring)
+	createUser(t, repo, 3, "mccoy")
+	// 邮箱修改记录：一条旧密钥、一条明文、一条已用当前密钥加密
+	oldChanges := NewEmailChangeRepository(db, oldKeyring)
+	require.NoError(t, oldChanges.Create(ctx, &entity.EmailChange{ID: 10, UserID: 1, OldEmail: "kirk@example.com", NewEmail: "james@example.com", Status: entity.EmailChangeConfirmed, ExpiresAt: time.Now()}))
+	require.NoError(t, db.DB().Exec(
+		"INSERT INTO email_changes (id, created_at, version, user_id, old_email, new_email, status, expires_at) VALUES (11, ?, 1, 2, 'spock@example.com', 'vulcan@example.com', 'pending', ?)",
+		time.Now(), time.Now()).Error)
+	changes := NewEmailChangeRepository(db, keyring)
+	require.NoError(t, changes.Create(ctx, &entity.EmailChange{ID: 12, UserID: 3, OldEmail: "mccoy@example.com", NewEmail: "bones@example.com", Status: entity.EmailChangePending, ExpiresAt: time.Now()}))
+
+	rotation := NewEmailKeyRotation(db, keyring, 1)
+	total := 0
+	for {
+		rotated, err := rotation.RotateBatch(ctx)
+		require.NoError(t, err)
+		if rotated == 0 {
+			break
+		}
+		total += rotated
+	}
+	assert.Equal(t, 4, total, "rows already on the active key are skipped")
+
+	var stored []model.UserDTO
+	require.NoError(t, db.DB().Order("id").Find(&stored).Error)
+	for _, dto := range stored {
+		assert.True(t, strings.HasPrefix(dto.Email, "enc:new:"), dto.Username)
+		assert.NotNil(t, dto.EmailIndex, dto.Username)
+		assert.Equal(t, int64(1), dto.Version, "rotation does not bump the version")
+	}
+	for id, email := range map[int64]string{1: "kirk@example.com", 2: "spock@example.com"} {
+		user, err := repo.FindByEmail(ctx, email)
+		require.NoError(t, err)
+		assert.Equal(t, id, user.ID)
+		assert.Equal(t, email, user.Email)
+	}
+
+	var storedChanges []model.EmailChangeDTO
+	require.NoError(t, db.DB().Order("id").Find(&storedChanges).Error)
+	for _, dto := range storedChanges {
+		assert.True(t, strings.HasPrefix(dto.OldEmail, "enc:new:"), dto.ID)
+		assert.True(t, strings.HasPrefix(dto.NewEmail, "enc:new:"), dto.ID)
+	}
+	list, err := changes.ListByUserID(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "spock@example.com", list[0].OldEmail)
+	assert.Equal(t, "vulcan@example.com", list[0].NewEmail)
+}
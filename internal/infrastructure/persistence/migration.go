@@ -1,19 +1,47 @@
 package persistence
 
 import (
-	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"fmt"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/migrations"
 	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/migrate"
 )
 
-// AutoMigrate runs GORM AutoMigrate for all registered domain models.
-// Add new model structs here as they are created.
+// NewMigrator returns a migrator that applies the embedded SQL migrations
+// (see package migrations) matching the dialect of db.
+//
+// Schema changes are made only through new migrations, never by editing the
+// DTOs alone: every DTO change needs a migration for each dialect.
 //
-// NOTE: AutoMigrate is convenient for development but should NOT be used
-// in production. Use a proper migration tool (e.g. golang-migrate) instead.
-func AutoMigrate(db database.Database) error {
-	return db.DB().AutoMigrate(
-		&model.UserDTO{},
-		// Add new models here:
-		// &model.PostDTO{},
-	)
+// Migrations that must wait for a background backfill are held back until
+// it has finished; Up then reports migrate.ErrBlocked.
+func NewMigrator(db database.Database) (*migrate.Migrator, error) {
+	dialect := db.DB().Dialector.Name()
+	fsys, err := migrations.FS(dialect)
+	if err != nil {
+		return nil, err
+	}
+	list, err := migrate.Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB().DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+	store, err := migrate.NewSQLStore(sqlDB, dialect)
+	if err != nil {
+		return nil, err
+	}
+	migrator := migrate.New(store, list)
+	// email 列的唯一约束要等 EmailKeyRotation 为所有用户补齐 email_index 之后才能删除
+	migrator.Hold(dropUsersEmailUniqueVersion,
+		"SELECT COUNT(*) FROM users WHERE email_index IS NULL",
+		"waiting for the email key rotation job to index every user's email")
+	return migrator, nil
 }
+
+// dropUsersEmailUniqueVersion is the migration dropping uni_users_email.
+const dropUsersEmailUniqueVersion = 10
@@ -0,0 +1,51 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/migrate"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+)
+
+// The plaintext unique constraint on users.email is dropped only once every
+// user has a blind index, so duplicates cannot slip in during the backfill.
+func TestNewMigrator_HoldsEmailUniqueDropUntilIndexed(t *testing.T) {
+	db := sqlite.NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	t.Cleanup(func() { _ = db.Close() })
+	ctx := context.Background()
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, dropUsersEmailUniqueVersion-1)
+	require.NoError(t, err)
+
+	// 加密之前写入的明文行
+	insertLegacy := "INSERT INTO users (id, created_at, version, username, email, password) VALUES (?, ?, 1, ?, 'spock@example.com', 'hashed')"
+	require.NoError(t, db.DB().Exec(insertLegacy, 2, time.Now(), "spock").Error)
+
+	applied, err := migrator.Up(ctx, 0)
+	assert.ErrorIs(t, err, migrate.ErrBlocked)
+	assert.Empty(t, applied)
+	assert.ErrorIs(t, migrator.Check(ctx), migrate.ErrBlocked)
+	assert.Error(t, db.DB().Exec(insertLegacy, 3, time.Now(), "spock2").Error, "the plaintext constraint is still in place")
+
+	rotated, err := NewEmailKeyRotation(db, newTestKeyring(t), 0).RotateBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotated)
+
+	applied, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, int64(dropUsersEmailUniqueVersion), applied[0].Version)
+	assert.NoError(t, migrator.Check(ctx))
+
+	var emailIndex *string
+	require.NoError(t, db.DB().Raw("SELECT email_index FROM users WHERE id = 2").Scan(&emailIndex).Error)
+	assert.NotNil(t, emailIndex, "the blind index survives the table rebuild")
+}
@@ -0,0 +1,28 @@
+// Package migrations embeds the versioned SQL migrations of the application
+// schema, one directory per database dialect.
+//
+// Every schema change is a new pair of files in each dialect directory, e.g.
+// 000006_add_users_phone.up.sql and 000006_add_users_phone.down.sql
+// (scripts/generate_migration.go creates them). Never edit a migration that has
+// been released: its checksum is recorded when applied, and the migrator
+// refuses to run against a database whose history no longer matches.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed postgres/*.sql mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// FS returns the migrations of dialect ("postgres", "mysql" or "sqlite").
+func FS(dialect string) (fs.FS, error) {
+	switch dialect {
+	case "postgres", "mysql", "sqlite":
+		return fs.Sub(files, dialect)
+	default:
+		return nil, fmt.Errorf("no migrations for database dialect %q", dialect)
+	}
+}
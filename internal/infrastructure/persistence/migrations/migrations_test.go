@@ -0,0 +1,119 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/migrate"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+)
+
+// baselineUser is the users model of the releases that created the schema
+// with AutoMigrate, before versioned migrations were introduced.
+type baselineUser struct {
+	ID        int64          `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	UpdatedAt *time.Time     `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt gorm.DeletedAt `gorm:"type:TIMESTAMP with time zone;index"`
+	Username  string         `gorm:"unique;not null"`
+	Email     string         `gorm:"unique;not null"`
+	Password  string         `gorm:"not null"`
+	AvatarURL *string
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+func newSQLiteMigrator(t *testing.T) (database.Database, *migrate.Migrator) {
+	t.Helper()
+	db := sqlite.NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	t.Cleanup(func() { _ = db.Close() })
+	sqlDB, err := db.DB().DB()
+	require.NoError(t, err)
+	store, err := migrate.NewSQLStore(sqlDB, "sqlite")
+	require.NoError(t, err)
+	return db, migrate.New(store, load(t, "sqlite"))
+}
+
+func load(t *testing.T, dialect string) []migrate.Migration {
+	t.Helper()
+	fsys, err := FS(dialect)
+	require.NoError(t, err)
+	migrations, err := migrate.Load(fsys)
+	require.NoError(t, err)
+	return migrations
+}
+
+// Every schema change must ship for all dialects under the same version.
+func TestMigrations_DialectsInSync(t *testing.T) {
+	postgres := load(t, "postgres")
+	require.NotEmpty(t, postgres)
+
+	for _, dialect := range []string{"mysql", "sqlite"} {
+		t.Run(dialect, func(t *testing.T) {
+			migrations := load(t, dialect)
+
+			require.Len(t, migrations, len(postgres))
+			for i := range postgres {
+				assert.Equal(t, postgres[i].Version, migrations[i].Version)
+				assert.Equal(t, postgres[i].Name, migrations[i].Name)
+			}
+		})
+	}
+}
+
+func TestFS_UnknownDialect(t *testing.T) {
+	_, err := FS("sqlserver")
+
+	assert.Error(t, err)
+}
+
+// The SQLite migrations run against a real in-memory database, which also
+// exercises migrate.SQLStore end to end.
+func TestMigrations_ApplyOnSQLite(t *testing.T) {
+	db, migrator := newSQLiteMigrator(t)
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	assert.NotEmpty(t, applied)
+	require.NoError(t, migrator.Check(ctx))
+	assert.True(t, db.DB().Migrator().HasTable("users"))
+
+	reverted, err := migrator.Down(ctx, len(applied))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(applied))
+	assert.False(t, db.DB().Migrator().HasTable("users"))
+	assert.ErrorIs(t, migrator.Check(ctx), migrate.ErrPending)
+}
+
+// A database created by AutoMigrate before migrations existed is adopted:
+// the initial migration keeps its users table and rows, and the later ones
+// add the columns it lacks.
+func TestMigrations_UpgradeAutoMigrateSchema(t *testing.T) {
+	db, migrator := newSQLiteMigrator(t)
+	ctx := context.Background()
+	require.NoError(t, db.DB().AutoMigrate(&baselineUser{}))
+	require.NoError(t, db.DB().Create(&baselineUser{ID: 1, CreatedAt: time.Now(), Username: "kirk", Email: "kirk@example.com", Password: "hash"}).Error)
+
+	_, err := migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+
+	for _, column := range []string{"version", "avatar_key", "display_name", "bio", "locale", "timezone", "website", "purged_at", "email_index"} {
+		assert.True(t, db.DB().Migrator().HasColumn("users", column), column)
+	}
+	var row struct {
+		Username string
+		Version  int64
+	}
+	require.NoError(t, db.DB().Table("users").Select("username", "version").Where("id = ?", 1).Take(&row).Error)
+	assert.Equal(t, "kirk", row.Username, "existing rows are kept")
+	assert.Equal(t, int64(1), row.Version)
+}
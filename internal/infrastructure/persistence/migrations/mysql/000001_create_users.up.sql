@@ -0,0 +1,16 @@
+-- users 表的初始结构，与引入迁移之前 AutoMigrate 创建的表一致。
+-- 已由 AutoMigrate 建表的数据库执行时跳过建表，之后的迁移照常补齐新列。
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGINT NOT NULL,
+    created_at DATETIME(3) NOT NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    username   VARCHAR(191) NOT NULL,
+    email      VARCHAR(191) NOT NULL,
+    password   TEXT NOT NULL,
+    avatar_url TEXT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_users_username UNIQUE (username),
+    CONSTRAINT uni_users_email UNIQUE (email),
+    INDEX idx_users_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+ALTER TABLE users
+    DROP INDEX idx_users_purged_at,
+    DROP COLUMN purged_at,
+    DROP COLUMN website,
+    DROP COLUMN timezone,
+    DROP COLUMN locale,
+    DROP COLUMN bio,
+    DROP COLUMN display_name,
+    DROP COLUMN avatar_key,
+    DROP COLUMN version;
@@ -0,0 +1,11 @@
+-- 资料、头像、乐观锁版本与清理时间等列，在已有的 users 表上补齐（包括 AutoMigrate 创建的表）。
+ALTER TABLE users
+    ADD COLUMN version      BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN avatar_key   TEXT NULL,
+    ADD COLUMN display_name VARCHAR(64) NULL,
+    ADD COLUMN bio          VARCHAR(500) NULL,
+    ADD COLUMN locale       VARCHAR(35) NULL,
+    ADD COLUMN timezone     VARCHAR(64) NULL,
+    ADD COLUMN website      TEXT NULL,
+    ADD COLUMN purged_at    DATETIME(3) NULL,
+    ADD INDEX idx_users_purged_at (purged_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    id           BIGINT NOT NULL,
+    created_at   DATETIME(3) NOT NULL,
+    updated_at   DATETIME(3) NULL,
+    deleted_at   DATETIME(3) NULL,
+    version      BIGINT NOT NULL DEFAULT 1,
+    user_id      BIGINT NOT NULL,
+    status       VARCHAR(16) NOT NULL,
+    object_key   TEXT NULL,
+    size         BIGINT NULL,
+    expires_at   DATETIME(3) NULL,
+    completed_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_data_exports_deleted_at (deleted_at),
+    INDEX idx_data_exports_user_id (user_id),
+    INDEX idx_data_exports_status (status),
+    INDEX idx_data_exports_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
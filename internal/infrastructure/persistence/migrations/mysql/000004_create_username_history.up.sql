@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS username_history (
+    id           BIGINT NOT NULL,
+    created_at   DATETIME(3) NOT NULL,
+    updated_at   DATETIME(3) NULL,
+    deleted_at   DATETIME(3) NULL,
+    version      BIGINT NOT NULL DEFAULT 1,
+    user_id      BIGINT NOT NULL,
+    old_username VARCHAR(255) NOT NULL,
+    new_username VARCHAR(255) NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_username_history_deleted_at (deleted_at),
+    INDEX idx_username_history_user_id (user_id),
+    INDEX idx_username_history_old_username (old_username)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS email_changes (
+    id           BIGINT NOT NULL,
+    created_at   DATETIME(3) NOT NULL,
+    updated_at   DATETIME(3) NULL,
+    deleted_at   DATETIME(3) NULL,
+    version      BIGINT NOT NULL DEFAULT 1,
+    user_id      BIGINT NOT NULL,
+    old_email    VARCHAR(255) NOT NULL,
+    new_email    VARCHAR(255) NOT NULL,
+    status       VARCHAR(16) NOT NULL,
+    expires_at   DATETIME(3) NOT NULL,
+    confirmed_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_email_changes_deleted_at (deleted_at),
+    INDEX idx_email_changes_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
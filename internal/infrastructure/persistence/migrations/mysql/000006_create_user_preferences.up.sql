@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS user_preferences (
+    id         BIGINT NOT NULL,
+    created_at DATETIME(3) NOT NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    version    BIGINT NOT NULL DEFAULT 1,
+    user_id    BIGINT NOT NULL,
+    `key`      VARCHAR(64) NOT NULL,
+    `value`    TEXT NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_user_preferences_deleted_at (deleted_at),
+    UNIQUE INDEX idx_user_preferences_user_key (user_id, `key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
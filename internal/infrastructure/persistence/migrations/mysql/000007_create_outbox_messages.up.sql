@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id              BIGINT NOT NULL,
+    created_at      DATETIME(3) NOT NULL,
+    updated_at      DATETIME(3) NULL,
+    deleted_at      DATETIME(3) NULL,
+    version         BIGINT NOT NULL DEFAULT 1,
+    event_name      VARCHAR(128) NOT NULL,
+    payload         MEDIUMTEXT NOT NULL,
+    idempotency_key VARCHAR(64) NOT NULL,
+    status          VARCHAR(16) NOT NULL,
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(3) NOT NULL,
+    last_error      TEXT NULL,
+    published_at    DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_outbox_messages_idempotency_key UNIQUE (idempotency_key),
+    INDEX idx_outbox_messages_deleted_at (deleted_at),
+    INDEX idx_outbox_messages_due (status, next_attempt_at),
+    INDEX idx_outbox_messages_published_at (published_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
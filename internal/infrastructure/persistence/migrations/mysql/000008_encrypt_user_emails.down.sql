@@ -0,0 +1,7 @@
+-- 不会解密邮箱：回滚前需确认 email 列中没有加密值，否则旧版本会读到密文（且密文超出 VARCHAR(191)）
+ALTER TABLE users
+    DROP INDEX uni_users_email_index,
+    DROP INDEX uni_users_email,
+    DROP COLUMN email_index,
+    MODIFY email VARCHAR(191) NOT NULL,
+    ADD CONSTRAINT uni_users_email UNIQUE (email);
@@ -0,0 +1,9 @@
+-- 邮箱改为加密存储（见 pkg/utils/fieldcrypt），按 email_index（HMAC 盲索引）精确查找与保证唯一。
+-- 已有的明文邮箱由密钥轮换任务在后台加密并补齐 email_index；补齐之前 email 列的唯一约束仍然保留，
+-- 由 000010 删除。密文比明文长，email 改为 TEXT，唯一索引只能建在前缀上（明文邮箱不超过 191 个字符）。
+ALTER TABLE users
+    DROP INDEX uni_users_email,
+    MODIFY email TEXT NOT NULL,
+    ADD UNIQUE INDEX uni_users_email (email(191)),
+    ADD COLUMN email_index VARCHAR(64) NULL,
+    ADD CONSTRAINT uni_users_email_index UNIQUE (email_index);
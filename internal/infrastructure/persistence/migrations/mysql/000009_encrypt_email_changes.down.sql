@@ -0,0 +1,4 @@
+-- 不会解密邮箱：回滚前需确认 old_email、new_email 中没有加密值，否则旧版本会读到密文（且密文超出 VARCHAR(255)）
+ALTER TABLE email_changes
+    MODIFY old_email VARCHAR(255) NOT NULL,
+    MODIFY new_email VARCHAR(255) NOT NULL;
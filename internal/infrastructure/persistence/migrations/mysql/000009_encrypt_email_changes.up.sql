@@ -0,0 +1,5 @@
+-- 邮箱修改记录中的新旧邮箱改为加密存储（见 pkg/utils/fieldcrypt），密文比明文长，改为 TEXT。
+-- 只按 user_id 查询，无需盲索引；已有的明文由密钥轮换任务在后台加密。
+ALTER TABLE email_changes
+    MODIFY old_email TEXT NOT NULL,
+    MODIFY new_email TEXT NOT NULL;
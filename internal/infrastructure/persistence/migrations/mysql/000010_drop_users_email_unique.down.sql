@@ -0,0 +1 @@
+ALTER TABLE users ADD UNIQUE INDEX uni_users_email (email(191));
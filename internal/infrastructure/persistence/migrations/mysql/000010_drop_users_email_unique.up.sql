@@ -0,0 +1,3 @@
+-- 所有用户都有 email_index 之后，唯一性由 uni_users_email_index 保证；密文每次加密都不同，email 列的唯一索引已无意义。
+-- 仍有 email_index 为空的用户时不会执行（见 persistence.NewMigrator），以免补齐期间可以用同一邮箱重复注册。
+ALTER TABLE users DROP INDEX uni_users_email;
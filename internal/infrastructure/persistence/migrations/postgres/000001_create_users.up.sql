@@ -0,0 +1,16 @@
+-- users 表的初始结构，与引入迁移之前 AutoMigrate 创建的表一致。
+-- 已由 AutoMigrate 建表的数据库执行时跳过建表，之后的迁移照常补齐新列。
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGINT PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    username   TEXT NOT NULL,
+    email      TEXT NOT NULL,
+    password   TEXT NOT NULL,
+    avatar_url TEXT,
+    CONSTRAINT uni_users_username UNIQUE (username),
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
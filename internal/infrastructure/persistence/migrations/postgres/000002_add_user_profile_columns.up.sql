@@ -0,0 +1,11 @@
+-- 资料、头像、乐观锁版本与清理时间等列，在已有的 users 表上补齐（包括 AutoMigrate 创建的表）。
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN avatar_key TEXT;
+ALTER TABLE users ADD COLUMN display_name VARCHAR(64);
+ALTER TABLE users ADD COLUMN bio VARCHAR(500);
+ALTER TABLE users ADD COLUMN locale VARCHAR(35);
+ALTER TABLE users ADD COLUMN timezone VARCHAR(64);
+ALTER TABLE users ADD COLUMN website TEXT;
+ALTER TABLE users ADD COLUMN purged_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_purged_at ON users (purged_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    id           BIGINT PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at   TIMESTAMP WITH TIME ZONE,
+    deleted_at   TIMESTAMP WITH TIME ZONE,
+    version      BIGINT NOT NULL DEFAULT 1,
+    user_id      BIGINT NOT NULL,
+    status       VARCHAR(16) NOT NULL,
+    object_key   TEXT,
+    size         BIGINT,
+    expires_at   TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_deleted_at ON data_exports (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports (status);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports (expires_at);
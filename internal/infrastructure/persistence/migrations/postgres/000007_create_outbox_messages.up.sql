@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id              BIGINT PRIMARY KEY,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at      TIMESTAMP WITH TIME ZONE,
+    deleted_at      TIMESTAMP WITH TIME ZONE,
+    version         BIGINT NOT NULL DEFAULT 1,
+    event_name      VARCHAR(128) NOT NULL,
+    payload         TEXT NOT NULL,
+    idempotency_key VARCHAR(64) NOT NULL,
+    status          VARCHAR(16) NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_error      TEXT,
+    published_at    TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT uni_outbox_messages_idempotency_key UNIQUE (idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_deleted_at ON outbox_messages (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_due ON outbox_messages (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_published_at ON outbox_messages (published_at);
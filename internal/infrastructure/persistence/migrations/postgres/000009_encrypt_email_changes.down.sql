@@ -0,0 +1,3 @@
+-- 不会解密邮箱：回滚前需确认 old_email、new_email 中没有加密值，否则旧版本会读到密文（且密文超出 VARCHAR(255)）
+ALTER TABLE email_changes ALTER COLUMN old_email TYPE VARCHAR(255);
+ALTER TABLE email_changes ALTER COLUMN new_email TYPE VARCHAR(255);
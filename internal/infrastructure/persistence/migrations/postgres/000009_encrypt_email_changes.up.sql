@@ -0,0 +1,4 @@
+-- 邮箱修改记录中的新旧邮箱改为加密存储（见 pkg/utils/fieldcrypt），密文比明文长，改为 TEXT。
+-- 只按 user_id 查询，无需盲索引；已有的明文由密钥轮换任务在后台加密。
+ALTER TABLE email_changes ALTER COLUMN old_email TYPE TEXT;
+ALTER TABLE email_changes ALTER COLUMN new_email TYPE TEXT;
@@ -0,0 +1 @@
+ALTER TABLE users ADD CONSTRAINT uni_users_email UNIQUE (email);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_users_purged_at;
+
+ALTER TABLE users DROP COLUMN purged_at;
+ALTER TABLE users DROP COLUMN website;
+ALTER TABLE users DROP COLUMN timezone;
+ALTER TABLE users DROP COLUMN locale;
+ALTER TABLE users DROP COLUMN bio;
+ALTER TABLE users DROP COLUMN display_name;
+ALTER TABLE users DROP COLUMN avatar_key;
+ALTER TABLE users DROP COLUMN version;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS username_history (
+    id           BIGINT PRIMARY KEY,
+    created_at   DATETIME NOT NULL,
+    updated_at   DATETIME,
+    deleted_at   DATETIME,
+    version      BIGINT NOT NULL DEFAULT 1,
+    user_id      BIGINT NOT NULL,
+    old_username VARCHAR(255) NOT NULL,
+    new_username VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_username_history_deleted_at ON username_history (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_username_history_user_id ON username_history (user_id);
+CREATE INDEX IF NOT EXISTS idx_username_history_old_username ON username_history (old_username);
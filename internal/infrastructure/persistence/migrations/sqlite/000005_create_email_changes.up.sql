@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS email_changes (
+    id           BIGINT PRIMARY KEY,
+    created_at   DATETIME NOT NULL,
+    updated_at   DATETIME,
+    deleted_at   DATETIME,
+    version      BIGINT NOT NULL DEFAULT 1,
+    user_id      BIGINT NOT NULL,
+    old_email    VARCHAR(255) NOT NULL,
+    new_email    VARCHAR(255) NOT NULL,
+    status       VARCHAR(16) NOT NULL,
+    expires_at   DATETIME NOT NULL,
+    confirmed_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_changes_deleted_at ON email_changes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_email_changes_user_id ON email_changes (user_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS user_preferences (
+    id         BIGINT PRIMARY KEY,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version    BIGINT NOT NULL DEFAULT 1,
+    user_id    BIGINT NOT NULL,
+    "key"      VARCHAR(64) NOT NULL,
+    "value"    TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_preferences_deleted_at ON user_preferences (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_preferences_user_key ON user_preferences (user_id, "key");
@@ -0,0 +1,4 @@
+-- 不会解密邮箱：回滚前需确认 email 列中没有加密值，否则旧版本会读到密文
+DROP INDEX IF EXISTS uni_users_email_index;
+
+ALTER TABLE users DROP COLUMN email_index;
@@ -0,0 +1,5 @@
+-- 邮箱改为加密存储（见 pkg/utils/fieldcrypt），按 email_index（HMAC 盲索引）精确查找与保证唯一。
+-- 已有的明文邮箱由密钥轮换任务在后台加密并补齐 email_index；补齐之前 email 列的唯一约束仍然保留，
+-- 由 000010 删除。
+ALTER TABLE users ADD COLUMN email_index VARCHAR(64);
+CREATE UNIQUE INDEX uni_users_email_index ON users (email_index);
@@ -0,0 +1 @@
+-- 不会解密邮箱：回滚前需确认 old_email、new_email 中没有加密值，否则旧版本会读到密文
@@ -0,0 +1,2 @@
+-- 邮箱修改记录中的新旧邮箱改为加密存储（见 pkg/utils/fieldcrypt）。
+-- SQLite 不限制 VARCHAR 的长度，无需修改表结构；已有的明文由密钥轮换任务在后台加密。
@@ -0,0 +1,35 @@
+-- SQLite 不能添加表约束，需要重建 users 表
+CREATE TABLE users_old (
+    id           BIGINT PRIMARY KEY,
+    created_at   DATETIME NOT NULL,
+    updated_at   DATETIME,
+    deleted_at   DATETIME,
+    version      BIGINT NOT NULL DEFAULT 1,
+    username     TEXT NOT NULL,
+    email        TEXT NOT NULL,
+    email_index  VARCHAR(64),
+    password     TEXT NOT NULL,
+    avatar_url   TEXT,
+    avatar_key   TEXT,
+    display_name VARCHAR(64),
+    bio          VARCHAR(500),
+    locale       VARCHAR(35),
+    timezone     VARCHAR(64),
+    website      TEXT,
+    purged_at    DATETIME,
+    CONSTRAINT uni_users_username UNIQUE (username),
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+INSERT INTO users_old (id, created_at, updated_at, deleted_at, version, username, email, email_index, password,
+                       avatar_url, avatar_key, display_name, bio, locale, timezone, website, purged_at)
+SELECT id, created_at, updated_at, deleted_at, version, username, email, email_index, password,
+       avatar_url, avatar_key, display_name, bio, locale, timezone, website, purged_at
+FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
+
+CREATE UNIQUE INDEX uni_users_email_index ON users (email_index);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_users_purged_at ON users (purged_at);
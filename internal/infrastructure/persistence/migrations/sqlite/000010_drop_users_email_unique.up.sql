@@ -0,0 +1,36 @@
+-- 所有用户都有 email_index 之后，唯一性由 uni_users_email_index 保证；密文每次加密都不同，email 列的唯一约束已无意义。
+-- 仍有 email_index 为空的用户时不会执行（见 persistence.NewMigrator），以免补齐期间可以用同一邮箱重复注册。
+-- SQLite 不能删除表约束，需要重建 users 表。
+CREATE TABLE users_new (
+    id           BIGINT PRIMARY KEY,
+    created_at   DATETIME NOT NULL,
+    updated_at   DATETIME,
+    deleted_at   DATETIME,
+    version      BIGINT NOT NULL DEFAULT 1,
+    username     TEXT NOT NULL,
+    email        TEXT NOT NULL,
+    email_index  VARCHAR(64),
+    password     TEXT NOT NULL,
+    avatar_url   TEXT,
+    avatar_key   TEXT,
+    display_name VARCHAR(64),
+    bio          VARCHAR(500),
+    locale       VARCHAR(35),
+    timezone     VARCHAR(64),
+    website      TEXT,
+    purged_at    DATETIME,
+    CONSTRAINT uni_users_username UNIQUE (username)
+);
+
+INSERT INTO users_new (id, created_at, updated_at, deleted_at, version, username, email, email_index, password,
+                       avatar_url, avatar_key, display_name, bio, locale, timezone, website, purged_at)
+SELECT id, created_at, updated_at, deleted_at, version, username, email, email_index, password,
+       avatar_url, avatar_key, display_name, bio, locale, timezone, website, purged_at
+FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE UNIQUE INDEX uni_users_email_index ON users (email_index);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_users_purged_at ON users (purged_at);
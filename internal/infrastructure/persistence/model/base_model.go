@@ -11,15 +11,23 @@ import (
 type BaseModel struct {
 	ID int64 `gorm:"primaryKey;autoIncrement:false" json:"id,string"`
 
-	CreatedAt time.Time      `gorm:"type:TIMESTAMP with time zone;not null" json:"created_at"`
-	UpdatedAt *time.Time     `gorm:"type:TIMESTAMP with time zone;null" json:"updated_at,omitempty"`
-	DeletedAt gorm.DeletedAt `gorm:"type:TIMESTAMP with time zone;index" json:"-"`
+	CreatedAt time.Time      `gorm:"not null" json:"created_at"`
+	UpdatedAt *time.Time     `gorm:"null" json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Version is the optimistic-locking counter of the row. Repositories that
+	// support conditional updates only write a row whose version still matches
+	// what the caller read, and advance it on every such write.
+	Version int64 `gorm:"not null;default:1" json:"version"`
 }
 
 func (m *BaseModel) BeforeCreate(tx *gorm.DB) (err error) {
 	if m.ID == 0 {
 		m.ID = snowflake.NextID()
 	}
+	if m.Version == 0 {
+		m.Version = 1
+	}
 	m.CreatedAt = time.Now().UTC()
 	return
 }
@@ -29,3 +37,9 @@ func (m *BaseModel) BeforeUpdate(tx *gorm.DB) (err error) {
 	m.UpdatedAt = &now
 	return
 }
+
+// Base returns m. Through embedding it gives generic code access to the
+// BaseModel of any model.
+func (m *BaseModel) Base() *BaseModel {
+	return m
+}
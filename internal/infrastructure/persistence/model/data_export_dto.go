@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+type DataExportDTO struct {
+	BaseModel
+	UserID      int64      `json:"user_id,string" gorm:"not null;index"`
+	Status      string     `json:"status" gorm:"size:16;not null;index"`
+	ObjectKey   *string    `json:"-"`
+	Size        int64      `json:"size"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName specifies the actual table name for DataExportDTO
+func (*DataExportDTO) TableName() string {
+	return "data_exports"
+}
+
+// ConvertToEntity 将 DataExportDTO 转换为领域实体 DataExport
+func (dto *DataExportDTO) ConvertToEntity() *entity.DataExport {
+	return &entity.DataExport{
+		ID:          dto.ID,
+		UserID:      dto.UserID,
+		Status:      entity.DataExportStatus(dto.Status),
+		ObjectKey:   dto.ObjectKey,
+		Size:        dto.Size,
+		ExpiresAt:   dto.ExpiresAt,
+		CompletedAt: dto.CompletedAt,
+		CreatedAt:   dto.CreatedAt,
+		UpdatedAt:   dto.UpdatedAt,
+		Version:     dto.Version,
+	}
+}
+
+// ConvertFromEntity 从领域实体 DataExport 转换为 DataExportDTO
+func (dto *DataExportDTO) ConvertFromEntity(e *entity.DataExport) {
+	dto.ID = e.ID
+	dto.UserID = e.UserID
+	dto.Status = string(e.Status)
+	dto.ObjectKey = e.ObjectKey
+	dto.Size = e.Size
+	dto.ExpiresAt = e.ExpiresAt
+	dto.CompletedAt = e.CompletedAt
+	dto.CreatedAt = e.CreatedAt
+	dto.UpdatedAt = e.UpdatedAt
+	dto.Version = e.Version
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+type EmailChangeDTO struct {
+	BaseModel
+	UserID      int64      `json:"user_id,string" gorm:"not null;index"`
+	OldEmail    string     `json:"old_email" gorm:"not null"` // 加密存储，见 persistence.NewEmailChangeRepository
+	NewEmail    string     `json:"new_email" gorm:"not null"` // 同上
+	Status      string     `json:"status" gorm:"size:16;not null"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+}
+
+// TableName specifies the actual table name for EmailChangeDTO
+func (*EmailChangeDTO) TableName() string {
+	return "email_changes"
+}
+
+// ConvertToEntity 将 EmailChangeDTO 转换为领域实体 EmailChange
+func (dto *EmailChangeDTO) ConvertToEntity() *entity.EmailChange {
+	return &entity.EmailChange{
+		ID:          dto.ID,
+		UserID:      dto.UserID,
+		OldEmail:    dto.OldEmail,
+		NewEmail:    dto.NewEmail,
+		Status:      entity.EmailChangeStatus(dto.Status),
+		ExpiresAt:   dto.ExpiresAt,
+		ConfirmedAt: dto.ConfirmedAt,
+		CreatedAt:   dto.CreatedAt,
+		UpdatedAt:   dto.UpdatedAt,
+		Version:     dto.Version,
+	}
+}
+
+// ConvertFromEntity 从领域实体 EmailChange 转换为 EmailChangeDTO
+func (dto *EmailChangeDTO) ConvertFromEntity(c *entity.EmailChange) {
+	dto.ID = c.ID
+	dto.UserID = c.UserID
+	dto.OldEmail = c.OldEmail
+	dto.NewEmail = c.NewEmail
+	dto.Status = string(c.Status)
+	dto.ExpiresAt = c.ExpiresAt
+	dto.ConfirmedAt = c.ConfirmedAt
+	dto.CreatedAt = c.CreatedAt
+	dto.UpdatedAt = c.UpdatedAt
+	dto.Version = c.Version
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+type OutboxMessageDTO struct {
+	BaseModel
+	EventName      string     `json:"event_name" gorm:"size:128;not null"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"`
+	IdempotencyKey string     `json:"idempotency_key" gorm:"size:64;not null;uniqueIndex"`
+	Status         string     `json:"status" gorm:"size:16;not null"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null"`
+	LastError      *string    `json:"last_error,omitempty"`
+	PublishedAt    *time.Time `json:"published_at,omitempty" gorm:"index"`
+}
+
+// TableName specifies the actual table name for OutboxMessageDTO
+func (*OutboxMessageDTO) TableName() string {
+	return "outbox_messages"
+}
+
+// ConvertToEntity 将 OutboxMessageDTO 转换为领域实体 OutboxMessage
+func (dto *OutboxMessageDTO) ConvertToEntity() *entity.OutboxMessage {
+	return &entity.OutboxMessage{
+		ID:             dto.ID,
+		EventName:      dto.EventName,
+		Payload:        []byte(dto.Payload),
+		IdempotencyKey: dto.IdempotencyKey,
+		Status:         entity.OutboxStatus(dto.Status),
+		Attempts:       dto.Attempts,
+		NextAttemptAt:  dto.NextAttemptAt,
+		LastError:      dto.LastError,
+		PublishedAt:    dto.PublishedAt,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+	}
+}
+
+// ConvertFromEntity 从领域实体 OutboxMessage 转换为 OutboxMessageDTO
+func (dto *OutboxMessageDTO) ConvertFromEntity(m *entity.OutboxMessage) {
+	dto.ID = m.ID
+	dto.EventName = m.EventName
+	dto.Payload = string(m.Payload)
+	dto.IdempotencyKey = m.IdempotencyKey
+	dto.Status = string(m.Status)
+	dto.Attempts = m.Attempts
+	dto.NextAttemptAt = m.NextAttemptAt
+	dto.LastError = m.LastError
+	dto.PublishedAt = m.PublishedAt
+	dto.CreatedAt = m.CreatedAt
+	dto.UpdatedAt = m.UpdatedAt
+}
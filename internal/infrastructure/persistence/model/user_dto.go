@@ -1,16 +1,31 @@
 package model
 
 import (
+	"time"
+
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
 	"github.com/kirklin/boot-backend-go-clean/pkg/utils/timeutil"
 )
 
 type UserDTO struct {
 	BaseModel
-	Username  string  `json:"username" gorm:"unique;not null"`
-	Email     string  `json:"email" gorm:"unique;not null"`
-	Password  string  `json:"-" gorm:"not null"` // 不在 JSON 中显示密码
-	AvatarURL *string `json:"avatar_url,omitempty"`
+	Username string `json:"username" gorm:"unique;not null"`
+	Email    string `json:"email" gorm:"not null"` // 加密存储，见 EmailIndex
+	// EmailIndex is the blind index of Email, used for exact-match lookups
+	// and the unique constraint. NULL until the rotation job has indexed a
+	// legacy plaintext row.
+	EmailIndex *string `json:"-" gorm:"size:64;unique"`
+	Password   string  `json:"-" gorm:"not null"` // 不在 JSON 中显示密码
+	AvatarURL  *string `json:"avatar_url,omitempty"`
+	AvatarKey  *string `json:"-"`
+	// Profile
+	DisplayName *string `json:"display_name,omitempty" gorm:"size:64"`
+	Bio         *string `json:"bio,omitempty" gorm:"size:500"`
+	Locale      *string `json:"locale,omitempty" gorm:"size:35"` // RFC 5646 建议的最小支持长度
+	Timezone    *string `json:"timezone,omitempty" gorm:"size:64"`
+	Website     *string `json:"website,omitempty"`
+	// PurgedAt is set once a soft-deleted account has been anonymized
+	PurgedAt *time.Time `json:"-" gorm:"index"`
 }
 
 // TableName specifies the actual table name for UserDTO
@@ -21,14 +36,21 @@ func (*UserDTO) TableName() string {
 // ConvertToEntity 将 UserDTO 转换为领域实体 User
 func (dto *UserDTO) ConvertToEntity() *entity.User {
 	return &entity.User{
-		ID:        dto.ID,
-		Username:  dto.Username,
-		Email:     dto.Email,
-		Password:  dto.Password,
-		AvatarURL: dto.AvatarURL,
-		CreatedAt: dto.CreatedAt,
-		UpdatedAt: dto.UpdatedAt,
-		DeletedAt: timeutil.ToTimePointer(dto.DeletedAt),
+		ID:          dto.ID,
+		Username:    dto.Username,
+		Email:       dto.Email,
+		Password:    dto.Password,
+		AvatarURL:   dto.AvatarURL,
+		AvatarKey:   dto.AvatarKey,
+		DisplayName: dto.DisplayName,
+		Bio:         dto.Bio,
+		Locale:      dto.Locale,
+		Timezone:    dto.Timezone,
+		Website:     dto.Website,
+		CreatedAt:   dto.CreatedAt,
+		UpdatedAt:   dto.UpdatedAt,
+		DeletedAt:   timeutil.ToTimePointer(dto.DeletedAt),
+		Version:     dto.Version,
 	}
 }
 
@@ -39,7 +61,14 @@ func (dto *UserDTO) ConvertFromEntity(u *entity.User) {
 	dto.Email = u.Email
 	dto.Password = u.Password
 	dto.AvatarURL = u.AvatarURL
+	dto.AvatarKey = u.AvatarKey
+	dto.DisplayName = u.DisplayName
+	dto.Bio = u.Bio
+	dto.Locale = u.Locale
+	dto.Timezone = u.Timezone
+	dto.Website = u.Website
 	dto.CreatedAt = u.CreatedAt
 	dto.UpdatedAt = u.UpdatedAt
 	dto.DeletedAt = timeutil.ToGormDeletedAt(u.DeletedAt)
+	dto.Version = u.Version
 }
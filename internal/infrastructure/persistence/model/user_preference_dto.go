@@ -0,0 +1,15 @@
+package model
+
+// UserPreferenceDTO stores one preference value of a user as JSON text.
+// Keys and value types are defined by the preference registry, not the table.
+type UserPreferenceDTO struct {
+	BaseModel
+	UserID int64  `json:"user_id,string" gorm:"not null;uniqueIndex:idx_user_preferences_user_key"`
+	Key    string `json:"key" gorm:"size:64;not null;uniqueIndex:idx_user_preferences_user_key"`
+	Value  string `json:"value" gorm:"type:text;not null"`
+}
+
+// TableName specifies the actual table name for UserPreferenceDTO
+func (*UserPreferenceDTO) TableName() string {
+	return "user_preferences"
+}
@@ -0,0 +1,37 @@
+package model
+
+import "github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+
+// UsernameHistoryDTO is one row of the username change history.
+// The change time is the row's CreatedAt.
+type UsernameHistoryDTO struct {
+	BaseModel
+	UserID      int64  `json:"user_id,string" gorm:"not null;index"`
+	OldUsername string `json:"old_username" gorm:"size:255;not null;index"`
+	NewUsername string `json:"new_username" gorm:"size:255;not null"`
+}
+
+// TableName specifies the actual table name for UsernameHistoryDTO
+func (*UsernameHistoryDTO) TableName() string {
+	return "username_history"
+}
+
+// ConvertToEntity 将 UsernameHistoryDTO 转换为领域实体 UsernameChange
+func (dto *UsernameHistoryDTO) ConvertToEntity() *entity.UsernameChange {
+	return &entity.UsernameChange{
+		ID:          dto.ID,
+		UserID:      dto.UserID,
+		OldUsername: dto.OldUsername,
+		NewUsername: dto.NewUsername,
+		ChangedAt:   dto.CreatedAt,
+	}
+}
+
+// ConvertFromEntity 从领域实体 UsernameChange 转换为 UsernameHistoryDTO
+func (dto *UsernameHistoryDTO) ConvertFromEntity(c *entity.UsernameChange) {
+	dto.ID = c.ID
+	dto.UserID = c.UserID
+	dto.OldUsername = c.OldUsername
+	dto.NewUsername = c.NewUsername
+	dto.CreatedAt = c.ChangedAt
+}
@@ -0,0 +1,114 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+type outboxRepository struct {
+	db   database.Database
+	base *Repository[entity.OutboxMessage, model.OutboxMessageDTO]
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository
+func NewOutboxRepository(db database.Database) repository.OutboxRepository {
+	return &outboxRepository{db: db, base: NewRepository(db, Mapping[entity.OutboxMessage, model.OutboxMessageDTO]{
+		ToEntity: (*model.OutboxMessageDTO).ConvertToEntity,
+		FromEntity: func(msg *entity.OutboxMessage) *model.OutboxMessageDTO {
+			dto := &model.OutboxMessageDTO{}
+			dto.ConvertFromEntity(msg)
+			return dto
+		},
+	})}
+}
+
+// Create inserts a new message, within the transaction of ctx if there is one
+func (r *outboxRepository) Create(ctx context.Context, msg *entity.OutboxMessage) error {
+	return r.base.Create(ctx, msg)
+}
+
+// Update writes the delivery state of a message. Messages are only written
+// by the relay holding the claim, so they are not version-checked.
+func (r *outboxRepository) Update(ctx context.Context, msg *entity.OutboxMessage) error {
+	return r.base.UpdateColumns(ctx, map[string]any{
+		"status":          msg.Status,
+		"attempts":        msg.Attempts,
+		"next_attempt_at": msg.NextAttemptAt.UTC(),
+		"last_error":      msg.LastError,
+		"published_at":    msg.PublishedAt,
+		"updated_at":      time.Now().UTC(),
+	}, where("id = ?", msg.ID))
+}
+
+// due scopes a query to pending messages whose next attempt is due at now.
+func due(now time.Time) Scope {
+	return where("status = ? AND next_attempt_at <= ?", entity.OutboxPending, now.UTC())
+}
+
+// ListDue retrieves messages waiting to be delivered, oldest ID first
+func (r *outboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxMessage, error) {
+	return r.base.List(ctx, repository.ListOptions{Limit: limit}, due(now))
+}
+
+// Claim records a delivery attempt unless another relay already did. The
+// conditional UPDATE on the attempt count makes the claim atomic across replicas.
+func (r *outboxRepository) Claim(ctx context.Context, msg *entity.OutboxMessage, retryAt time.Time) error {
+	now := time.Now().UTC()
+	err := r.base.UpdateColumns(ctx, map[string]any{
+		"attempts":        msg.Attempts + 1,
+		"next_attempt_at": retryAt.UTC(),
+		"updated_at":      now,
+	}, where("id = ? AND attempts = ?", msg.ID, msg.Attempts), due(now))
+	if err != nil {
+		return err
+	}
+	msg.Attempts++
+	msg.NextAttemptAt = retryAt.UTC()
+	msg.UpdatedAt = &now
+	return nil
+}
+
+// Backlog counts the pending messages and finds the oldest of them
+func (r *outboxRepository) Backlog(ctx context.Context) (*entity.OutboxBacklog, error) {
+	pending := where("status = ?", entity.OutboxPending)
+	count, err := r.base.Count(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	backlog := &entity.OutboxBacklog{Pending: count}
+	if count == 0 {
+		return backlog, nil
+	}
+
+	oldest, err := r.base.First(ctx, pending)
+	if errors.Is(err, domainerrors.ErrNotFound) {
+		return backlog, nil // 计数之后刚被投递
+	}
+	if err != nil {
+		return nil, err
+	}
+	backlog.OldestPending = &oldest.CreatedAt
+	return backlog, nil
+}
+
+// DeletePublishedBefore permanently removes delivered messages, oldest ID first
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	var ids []int64
+	err := dbFromContext(ctx, r.db).Model(&model.OutboxMessageDTO{}).
+		Where("status = ? AND published_at < ?", entity.OutboxPublished, before.UTC()).
+		Order("id ASC").Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	result := dbFromContext(ctx, r.db).Unscoped().Delete(&model.OutboxMessageDTO{}, ids)
+	return int(result.RowsAffected), result.Error
+}
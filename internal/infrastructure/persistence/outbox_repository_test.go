@@ -0,0 +1,87 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+)
+
+func createOutboxMessage(t *testing.T, repo repository.OutboxRepository, id int64, nextAttemptAt time.Time) *entity.OutboxMessage {
+	t.Helper()
+	msg := &entity.OutboxMessage{
+		ID:             id,
+		EventName:      "user.registered",
+		Payload:        []byte(`{"user_id":"1"}`),
+		IdempotencyKey: "key-" + strconv.FormatInt(id, 10),
+		Status:         entity.OutboxPending,
+		NextAttemptAt:  nextAttemptAt,
+	}
+	require.NoError(t, repo.Create(context.Background(), msg))
+	return msg
+}
+
+func TestOutboxRepository_ListDueAndClaim(t *testing.T) {
+	repo := NewOutboxRepository(newTestDB(t))
+	ctx := context.Background()
+	now := time.Now()
+	createOutboxMessage(t, repo, 1, now.Add(-time.Minute))
+	createOutboxMessage(t, repo, 2, now.Add(time.Hour)) // 尚未到重试时间
+
+	due, err := repo.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, int64(1), due[0].ID)
+	assert.Equal(t, `{"user_id":"1"}`, string(due[0].Payload))
+
+	stale := *due[0]
+	retryAt := now.Add(time.Minute)
+	require.NoError(t, repo.Claim(ctx, due[0], retryAt))
+	assert.Equal(t, 1, due[0].Attempts)
+
+	// 另一个实例持有的是领取前的副本
+	assert.ErrorIs(t, repo.Claim(ctx, &stale, retryAt), domainerrors.ErrNoRowsAffected)
+	due, err = repo.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due, "claimed messages wait for their retry time")
+}
+
+func TestOutboxRepository_BacklogAndCleanup(t *testing.T) {
+	repo := NewOutboxRepository(newTestDB(t))
+	ctx := context.Background()
+
+	backlog, err := repo.Backlog(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, backlog.Pending)
+	assert.Nil(t, backlog.OldestPending)
+
+	first := createOutboxMessage(t, repo, 1, time.Now())
+	createOutboxMessage(t, repo, 2, time.Now())
+	backlog, err = repo.Backlog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), backlog.Pending)
+	require.NotNil(t, backlog.OldestPending)
+	assert.WithinDuration(t, first.CreatedAt, *backlog.OldestPending, time.Second)
+
+	publishedAt := time.Now().Add(-2 * time.Hour).UTC()
+	first.Status = entity.OutboxPublished
+	first.PublishedAt = &publishedAt
+	require.NoError(t, repo.Update(ctx, first))
+	backlog, err = repo.Backlog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), backlog.Pending)
+
+	removed, err := repo.DeletePublishedBefore(ctx, time.Now().Add(-3*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Zero(t, removed, "published after the cutoff")
+	removed, err = repo.DeletePublishedBefore(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+}
@@ -0,0 +1,78 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+type preferenceRepository struct {
+	db database.Database
+}
+
+// NewPreferenceRepository creates a new instance of PreferenceRepository
+func NewPreferenceRepository(db database.Database) repository.PreferenceRepository {
+	return &preferenceRepository{db: db}
+}
+
+// ListByUserID retrieves the stored preference values of a user
+func (r *preferenceRepository) ListByUserID(ctx context.Context, userID int64) (map[string]json.RawMessage, error) {
+	var dtos []model.UserPreferenceDTO
+	if err := readDBFromContext(ctx, r.db).Where("user_id = ?", userID).Find(&dtos).Error; err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]json.RawMessage, len(dtos))
+	for _, dto := range dtos {
+		values[dto.Key] = json.RawMessage(dto.Value)
+	}
+	return values, nil
+}
+
+// Set upserts preference values; an existing (user_id, key) row has its value replaced
+func (r *preferenceRepository) Set(ctx context.Context, userID int64, values map[string]json.RawMessage) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	dtos := make([]model.UserPreferenceDTO, 0, len(values))
+	for key, value := range values {
+		dto := model.UserPreferenceDTO{UserID: userID, Key: key, Value: string(value)}
+		dto.UpdatedAt = &now
+		dtos = append(dtos, dto)
+	}
+	return dbFromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&dtos).Error
+}
+
+// Unset permanently removes the given preference keys of a user
+func (r *preferenceRepository) Unset(ctx context.Context, userID int64, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		values[i] = key
+	}
+	// key 是 MySQL 保留字，用 clause.Column 交给方言加引号
+	return dbFromContext(ctx, r.db).Unscoped().
+		Where("user_id = ?", userID).
+		Where(clause.IN{Column: clause.Column{Name: "key"}, Values: values}).
+		Delete(&model.UserPreferenceDTO{}).Error
+}
+
+// DeleteByUserID permanently removes every preference of a user
+func (r *preferenceRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	return dbFromContext(ctx, r.db).Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&model.UserPreferenceDTO{}).Error
+}
@@ -0,0 +1,53 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+)
+
+func TestPreferenceRepository_Unset(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewPreferenceRepository(db)
+	ctx := context.Background()
+	// 显式指定 ID 写入，无需 Snowflake 节点
+	for i, pref := range []struct {
+		userID     int64
+		key, value string
+	}{
+		{1, "theme", `"dark"`}, {1, "language", `"en"`}, {1, "digest", `true`}, {2, "theme", `"light"`},
+	} {
+		dto := model.UserPreferenceDTO{UserID: pref.userID, Key: pref.key, Value: pref.value}
+		dto.ID = int64(i + 1)
+		require.NoError(t, db.DB().Create(&dto).Error)
+	}
+
+	// 记录 DELETE 语句：key 是 MySQL 保留字，必须加引号
+	var deleteSQL string
+	require.NoError(t, db.DB().Callback().Delete().After("gorm:delete").Register("test:capture_sql", func(tx *gorm.DB) {
+		deleteSQL = tx.Statement.SQL.String()
+	}))
+	require.NoError(t, repo.Unset(ctx, 1, []string{"theme", "digest", "unknown"}))
+	assert.Contains(t, deleteSQL, "`key` IN")
+
+	values, err := repo.ListByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{"language": json.RawMessage(`"en"`)}, values)
+	values, err = repo.ListByUserID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{"theme": json.RawMessage(`"light"`)}, values, "other users are untouched")
+
+	var remaining int64
+	require.NoError(t, db.DB().Unscoped().Model(&model.UserPreferenceDTO{}).Count(&remaining).Error)
+	assert.Equal(t, int64(2), remaining, "rows are deleted, not soft-deleted")
+
+	deleteSQL = ""
+	require.NoError(t, repo.Unset(ctx, 1, nil))
+	assert.Empty(t, deleteSQL, "no keys, no query")
+}
@@ -0,0 +1,293 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+// Scope narrows a query, e.g. with a WHERE clause or Unscoped. Scopes are
+// applied in order after the model is set.
+type Scope = func(*gorm.DB) *gorm.DB
+
+// Mapping configures a Repository for one entity and its GORM model.
+type Mapping[E, M any] struct {
+	// ToEntity and FromEntity convert between the model and the entity.
+	// The conversion must be lossless: after a write the entity is rebuilt
+	// from the model to pick up the timestamps and version it was given.
+	ToEntity   func(*M) *E
+	FromEntity func(*E) *M
+	// Columns are the columns Update writes. The key, timestamps and version
+	// are managed by the Repository.
+	Columns []string
+	// NotFound is returned by the queries that find a single row when none
+	// matches. Defaults to ErrNotFound.
+	NotFound error
+	// NoRowsAffected is returned by writes whose target row does not exist.
+	// Defaults to ErrNoRowsAffected.
+	NoRowsAffected error
+	// Conflicts maps unique constraint names to the errors returned by
+	// writes violating them. Other violations return ErrConflict.
+	Conflicts map[string]error
+	// Seal and Open, if set, transform the model on its way into and out of
+	// the database, e.g. to encrypt columns. Seal runs before every write,
+	// Open after every read and before the entity is rebuilt after a write.
+	// UpdateColumns writes raw values and runs neither.
+	Seal func(*M) error
+	Open func(*M) error
+}
+
+// Repository implements the operations every GORM-backed aggregate shares:
+// DTO conversion, transaction and replica aware connections (dbFromContext,
+// readDBFromContext), not-found and unique violation translation and
+// optimistic locking.
+//
+// M is the model struct and must embed model.BaseModel. Entity repositories
+// hold a Repository and add their own queries on top of it, usually as scopes:
+//
+//	func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+//	    return r.base.First(ctx, where("email = ?", email))
+//	}
+type Repository[E, M any] struct {
+	db      database.Database
+	mapping Mapping[E, M]
+}
+
+// NewRepository creates a Repository. It panics if M does not embed
+// model.BaseModel or a conversion is missing, which are programming errors.
+func NewRepository[E, M any](db database.Database, mapping Mapping[E, M]) *Repository[E, M] {
+	if _, ok := any(new(M)).(baseModeler); !ok {
+		panic(fmt.Sprintf("persistence: %T does not embed model.BaseModel", new(M)))
+	}
+	if mapping.ToEntity == nil || mapping.FromEntity == nil {
+		panic(fmt.Sprintf("persistence: mapping of %T is missing a conversion", new(M)))
+	}
+	if mapping.NotFound == nil {
+		mapping.NotFound = domainerrors.ErrNotFound
+	}
+	if mapping.NoRowsAffected == nil {
+		mapping.NoRowsAffected = domainerrors.ErrNoRowsAffected
+	}
+	return &Repository[E, M]{db: db, mapping: mapping}
+}
+
+// baseModeler is implemented by every model embedding model.BaseModel.
+type baseModeler interface {
+	Base() *model.BaseModel
+}
+
+func base(dto any) *model.BaseModel {
+	return dto.(baseModeler).Base()
+}
+
+// where returns a Scope adding a WHERE clause.
+func where(query any, args ...any) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
+// unscoped is a Scope that includes soft-deleted rows.
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// Create inserts entity and updates it with the generated ID, timestamps
+// and version.
+func (r *Repository[E, M]) Create(ctx context.Context, entity *E) error {
+	dto, err := r.toModel(entity)
+	if err != nil {
+		return err
+	}
+	if err := dbFromContext(ctx, r.db).Create(dto).Error; err != nil {
+		return r.conflict(err)
+	}
+	return r.refresh(entity, dto)
+}
+
+// FindByID retrieves the row with the given ID.
+func (r *Repository[E, M]) FindByID(ctx context.Context, id int64, scopes ...Scope) (*E, error) {
+	return r.First(ctx, append(scopes, where("id = ?", id))...)
+}
+
+// First retrieves the first row, by ID, matching scopes.
+func (r *Repository[E, M]) First(ctx context.Context, scopes ...Scope) (*E, error) {
+	var dto M
+	err := r.query(readDBFromContext(ctx, r.db), scopes).First(&dto).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, r.mapping.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntity(&dto)
+}
+
+// List retrieves the rows matching scopes, ordered by ID and paginated per opts.
+func (r *Repository[E, M]) List(ctx context.Context, opts repository.ListOptions, scopes ...Scope) ([]*E, error) {
+	query := r.query(readDBFromContext(ctx, r.db), scopes)
+
+	if opts.Order == repository.SortDesc {
+		if opts.AfterID != 0 {
+			query = query.Where("id < ?", opts.AfterID)
+		}
+		query = query.Order("id DESC")
+	} else {
+		if opts.AfterID != 0 {
+			query = query.Where("id > ?", opts.AfterID)
+		}
+		query = query.Order("id ASC")
+	}
+	if opts.AfterID == 0 && opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	var dtos []M
+	if err := query.Find(&dtos).Error; err != nil {
+		return nil, err
+	}
+
+	entities := make([]*E, 0, len(dtos))
+	for i := range dtos {
+		entity, err := r.toEntity(&dtos[i])
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+// Count returns the number of rows matching scopes.
+func (r *Repository[E, M]) Count(ctx context.Context, scopes ...Scope) (int64, error) {
+	var total int64
+	err := r.query(readDBFromContext(ctx, r.db), scopes).Count(&total).Error
+	return total, err
+}
+
+// Exists reports whether any row matches scopes.
+func (r *Repository[E, M]) Exists(ctx context.Context, scopes ...Scope) (bool, error) {
+	var found []int64
+	err := r.query(readDBFromContext(ctx, r.db), scopes).Limit(1).Pluck("id", &found).Error
+	return len(found) > 0, err
+}
+
+// Update writes the given columns of an existing row, or Mapping.Columns if
+// none are given, provided the row still has entity's version. entity is
+// updated with the new version and UpdatedAt. A missing row is reported as
+// Mapping.NoRowsAffected, a newer version as ErrConcurrentModification.
+func (r *Repository[E, M]) Update(ctx context.Context, entity *E, columns ...string) error {
+	if len(columns) == 0 {
+		columns = r.mapping.Columns
+	}
+	dto, err := r.toModel(entity)
+	if err != nil {
+		return err
+	}
+	err = updateVersioned(dbFromContext(ctx, r.db), dto, base(dto), columns...)
+	if errors.Is(err, domainerrors.ErrNoRowsAffected) {
+		return r.mapping.NoRowsAffected
+	}
+	if err != nil {
+		return r.conflict(err)
+	}
+	return r.refresh(entity, dto)
+}
+
+// SoftDelete marks the row with the given ID as deleted. Like GORM's soft
+// delete, but it also advances the version.
+func (r *Repository[E, M]) SoftDelete(ctx context.Context, id int64) error {
+	return r.UpdateColumns(ctx, map[string]any{"deleted_at": time.Now().UTC()}, where("id = ?", id))
+}
+
+// UpdateColumns sets columns on the rows matching scopes, unconditionally,
+// and advances their version. It returns Mapping.NoRowsAffected if no row
+// matched.
+func (r *Repository[E, M]) UpdateColumns(ctx context.Context, columns map[string]any, scopes ...Scope) error {
+	values := make(map[string]any, len(columns)+1)
+	for column, value := range columns {
+		values[column] = value
+	}
+	values["version"] = bumpVersion
+
+	return r.affected(r.query(dbFromContext(ctx, r.db), scopes).Updates(values))
+}
+
+// HardDelete permanently removes the row with the given ID, even if it is
+// soft-deleted.
+func (r *Repository[E, M]) HardDelete(ctx context.Context, id int64) error {
+	return r.affected(dbFromContext(ctx, r.db).Unscoped().Delete(new(M), id))
+}
+
+// toModel converts entity to a sealed model ready to be written.
+func (r *Repository[E, M]) toModel(entity *E) (*M, error) {
+	dto := r.mapping.FromEntity(entity)
+	if r.mapping.Seal != nil {
+		if err := r.mapping.Seal(dto); err != nil {
+			return nil, err
+		}
+	}
+	return dto, nil
+}
+
+// toEntity opens a model read from the database and converts it.
+func (r *Repository[E, M]) toEntity(dto *M) (*E, error) {
+	if r.mapping.Open != nil {
+		if err := r.mapping.Open(dto); err != nil {
+			return nil, err
+		}
+	}
+	return r.mapping.ToEntity(dto), nil
+}
+
+// refresh updates entity from the model it was written as, to pick up the
+// values the database assigned.
+func (r *Repository[E, M]) refresh(entity *E, dto *M) error {
+	written, err := r.toEntity(dto)
+	if err != nil {
+		return err
+	}
+	*entity = *written
+	return nil
+}
+
+// query sets the model on db and applies scopes.
+func (r *Repository[E, M]) query(db *gorm.DB, scopes []Scope) *gorm.DB {
+	return db.Model(new(M)).Scopes(scopes...)
+}
+
+// affected translates a write that matched no row into Mapping.NoRowsAffected.
+func (r *Repository[E, M]) affected(result *gorm.DB) error {
+	if result.Error != nil {
+		return r.conflict(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return r.mapping.NoRowsAffected
+	}
+	return nil
+}
+
+// conflict translates a unique constraint violation into the error
+// Mapping.Conflicts configures for the constraint, or ErrConflict. Other
+// errors are returned unchanged.
+func (r *Repository[E, M]) conflict(err error) error {
+	constraint, ok := uniqueViolation(err)
+	if !ok {
+		return err
+	}
+	if mapped, ok := r.mapping.Conflicts[constraint]; ok {
+		return mapped
+	}
+	return domainerrors.ErrConflict.Wrap(err)
+}
@@ -0,0 +1,87 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+)
+
+func newTestUserBase(t *testing.T, mapping Mapping[entity.User, model.UserDTO]) *Repository[entity.User, model.UserDTO] {
+	t.Helper()
+	mapping.ToEntity = (*model.UserDTO).ConvertToEntity
+	mapping.FromEntity = func(user *entity.User) *model.UserDTO {
+		dto := &model.UserDTO{}
+		dto.ConvertFromEntity(user)
+		return dto
+	}
+	return NewRepository(newTestDB(t), mapping)
+}
+
+func TestRepository_DefaultErrors(t *testing.T) {
+	base := newTestUserBase(t, Mapping[entity.User, model.UserDTO]{})
+	ctx := context.Background()
+
+	_, err := base.FindByID(ctx, 1)
+	assert.ErrorIs(t, err, domainerrors.ErrNotFound)
+	assert.ErrorIs(t, base.SoftDelete(ctx, 1), domainerrors.ErrNoRowsAffected)
+
+	// 未配置的唯一约束冲突统一返回 ErrConflict
+	require.NoError(t, base.Create(ctx, &entity.User{ID: 1, Username: "kirk", Email: "kirk@example.com"}))
+	err = base.Create(ctx, &entity.User{ID: 2, Username: "kirk", Email: "james@example.com"})
+	var appErr *domainerrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, domainerrors.ErrConflict.Code, appErr.Code)
+}
+
+func TestRepository_ConfiguredErrors(t *testing.T) {
+	base := newTestUserBase(t, Mapping[entity.User, model.UserDTO]{
+		NotFound:       domainerrors.ErrUserNotFound,
+		NoRowsAffected: domainerrors.ErrUserNotFound,
+	})
+	ctx := context.Background()
+
+	_, err := base.FindByID(ctx, 1)
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+	assert.ErrorIs(t, base.HardDelete(ctx, 1), domainerrors.ErrUserNotFound)
+	missing := &entity.User{ID: 1, Version: 1}
+	assert.ErrorIs(t, base.Update(ctx, missing, "username"), domainerrors.ErrUserNotFound)
+}
+
+func TestRepository_Exists(t *testing.T) {
+	base := newTestUserBase(t, Mapping[entity.User, model.UserDTO]{})
+	ctx := context.Background()
+	require.NoError(t, base.Create(ctx, &entity.User{ID: 1, Username: "kirk", Email: "kirk@example.com"}))
+
+	exists, err := base.Exists(ctx, where("username = ?", "kirk"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, base.SoftDelete(ctx, 1))
+	exists, err = base.Exists(ctx, where("username = ?", "kirk"))
+	require.NoError(t, err)
+	assert.False(t, exists, "soft-deleted rows are excluded")
+
+	exists, err = base.Exists(ctx, unscoped, where("username = ?", "kirk"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestNewRepository_RequiresBaseModel(t *testing.T) {
+	type plain struct{ ID int64 }
+
+	assert.Panics(t, func() {
+		NewRepository(nil, Mapping[entity.User, plain]{
+			ToEntity:   func(*plain) *entity.User { return nil },
+			FromEntity: func(*entity.User) *plain { return nil },
+		})
+	})
+	assert.Panics(t, func() {
+		NewRepository(nil, Mapping[entity.User, model.UserDTO]{})
+	})
+}
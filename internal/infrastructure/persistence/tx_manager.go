@@ -2,9 +2,11 @@ package persistence
 
 import (
 	"context"
+	"database/sql"
 
 	"gorm.io/gorm"
 
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 	"github.com/kirklin/boot-backend-go-clean/pkg/database"
 )
@@ -13,9 +15,9 @@ import (
 // transaction support. It wraps the callback in a database transaction
 // and automatically commits or rolls back based on the returned error.
 //
-// Nested WithTx calls are safe — GORM uses SAVEPOINTs for nested
-// transactions, so inner rollbacks don't affect the outer transaction
-// unless the outer callback also returns an error.
+// Nested WithTx calls are safe — they run in a SAVEPOINT of the transaction
+// in ctx, so inner rollbacks don't affect the outer transaction unless the
+// outer callback also returns an error.
 type gormTxManager struct {
 	db database.Database
 }
@@ -35,10 +37,50 @@ func NewTxManager(db database.Database) repository.TxManager {
 //
 // The transaction is injected into ctx via a context key. Repository
 // methods that use dbFromContext() will automatically participate in
-// this transaction.
-func (m *gormTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
-	return m.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		txCtx := context.WithValue(ctx, txKey{}, tx)
-		return fn(txCtx)
+// this transaction. Functions registered with repository.AfterCommit
+// inside fn run after the commit, and not at all on rollback.
+//
+// Serialization failures and deadlocks are returned as ErrTxConflict and
+// retried as configured by repository.WithRetry.
+func (m *gormTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...repository.TxOption) error {
+	// 嵌套调用在外层事务中以 SAVEPOINT 执行；隔离级别与重试由外层决定
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return m.run(ctx, tx, fn, nil)
+	}
+
+	o := repository.ApplyTxOptions(opts...)
+	txOptions := &sql.TxOptions{Isolation: sqlIsolation(o.Isolation), ReadOnly: o.ReadOnly}
+	return o.RunWithRetry(ctx, func() error {
+		return m.run(ctx, m.db.DB(), fn, txOptions)
 	})
 }
+
+// run executes fn in a transaction of db, or a savepoint if db is a transaction.
+func (m *gormTxManager) run(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error, txOptions *sql.TxOptions) error {
+	hooksCtx, end := repository.WithCommitHooks(ctx)
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := context.WithValue(hooksCtx, txKey{}, tx)
+		return fn(txCtx)
+	}, txOptions)
+	// 事务提交后才执行 AfterCommit 注册的函数（如投递领域事件）；回滚时丢弃
+	end(err == nil)
+
+	if isTxConflict(err) && !repository.IsTxConflict(err) {
+		return domainerrors.ErrTxConflict.Wrap(err)
+	}
+	return err
+}
+
+// sqlIsolation maps an isolation level to database/sql's.
+func sqlIsolation(level repository.IsolationLevel) sql.IsolationLevel {
+	switch level {
+	case repository.ReadCommitted:
+		return sql.LevelReadCommitted
+	case repository.RepeatableRead:
+		return sql.LevelRepeatableRead
+	case repository.Serializable:
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
+}
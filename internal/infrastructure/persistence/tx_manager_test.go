@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+)
+
+func TestTxManager_CommitAndRollback(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db, newTestKeyring(t))
+	txManager := NewTxManager(db)
+	ctx := context.Background()
+	createUser(t, repo, 1, "kirk")
+
+	var committed bool
+	require.NoError(t, txManager.WithTx(ctx, func(txCtx context.Context) error {
+		repository.AfterCommit(txCtx, func(context.Context) { committed = true })
+		return repo.SoftDelete(txCtx, 1)
+	}))
+	assert.True(t, committed)
+	_, err := repo.FindByID(ctx, 1)
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+
+	createUser(t, repo, 2, "spock")
+	failure := errors.New("failed")
+	committed = false
+	err = txManager.WithTx(ctx, func(txCtx context.Context) error {
+		repository.AfterCommit(txCtx, func(context.Context) { committed = true })
+		require.NoError(t, repo.SoftDelete(txCtx, 2))
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.False(t, committed, "hooks are dropped on rollback")
+	_, err = repo.FindByID(ctx, 2)
+	assert.NoError(t, err, "rolled back")
+}
+
+func TestTxManager_NestedRollbackKeepsOuter(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db, newTestKeyring(t))
+	txManager := NewTxManager(db)
+	ctx := context.Background()
+	createUser(t, repo, 1, "kirk")
+	createUser(t, repo, 2, "spock")
+
+	failure := errors.New("failed")
+	require.NoError(t, txManager.WithTx(ctx, func(txCtx context.Context) error {
+		require.NoError(t, repo.SoftDelete(txCtx, 1))
+		// 内层回滚到 SAVEPOINT，外层事务继续
+		err := txManager.WithTx(txCtx, func(innerCtx context.Context) error {
+			require.NoError(t, repo.SoftDelete(innerCtx, 2))
+			return failure
+		}, repository.WithRetry(3, 0))
+		assert.ErrorIs(t, err, failure)
+		return nil
+	}))
+
+	_, err := repo.FindByID(ctx, 1)
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+	_, err = repo.FindByID(ctx, 2)
+	assert.NoError(t, err)
+}
+
+func TestTxManager_RetriesConflicts(t *testing.T) {
+	txManager := NewTxManager(newTestDB(t))
+	ctx := context.Background()
+
+	// 驱动报告的序列化失败与已翻译的 ErrTxConflict 都会重试
+	conflicts := []error{&pgconn.PgError{Code: "40001"}, domainerrors.ErrTxConflict}
+	attempts := 0
+	err := txManager.WithTx(ctx, func(context.Context) error {
+		attempts++
+		if attempts <= len(conflicts) {
+			return conflicts[attempts-1]
+		}
+		return nil
+	}, repository.WithIsolation(repository.Serializable), repository.WithRetry(2, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// 未配置重试时直接返回冲突
+	attempts = 0
+	err = txManager.WithTx(ctx, func(context.Context) error {
+		attempts++
+		return domainerrors.ErrTxConflict
+	})
+	assert.True(t, repository.IsTxConflict(err))
+	assert.Equal(t, 1, attempts)
+
+	// 驱动错误被翻译为 ErrTxConflict，并保留原始错误
+	err = txManager.WithTx(ctx, func(context.Context) error {
+		return &mysql.MySQLError{Number: 1213}
+	})
+	assert.True(t, repository.IsTxConflict(err))
+	var mysqlErr *mysql.MySQLError
+	assert.ErrorAs(t, err, &mysqlErr)
+}
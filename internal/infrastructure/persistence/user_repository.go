@@ -2,7 +2,9 @@ package persistence
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -11,85 +13,252 @@ import (
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
 	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/fieldcrypt"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/snowflake"
 )
 
 type userRepository struct {
-	db database.Database
+	base    *Repository[entity.User, model.UserDTO]
+	keyring *fieldcrypt.Keyring
 }
 
-// NewUserRepository creates a new instance of UserRepository
-func NewUserRepository(db database.Database) repository.UserRepository {
-	return &userRepository{db: db}
+// NewUserRepository creates a new instance of UserRepository. Emails are
+// encrypted with keyring and looked up by their blind index.
+func NewUserRepository(db database.Database, keyring *fieldcrypt.Keyring) repository.UserRepository {
+	r := &userRepository{keyring: keyring}
+	r.base = NewRepository(db, Mapping[entity.User, model.UserDTO]{
+		ToEntity: (*model.UserDTO).ConvertToEntity,
+		FromEntity: func(user *entity.User) *model.UserDTO {
+			dto := &model.UserDTO{}
+			dto.ConvertFromEntity(user)
+			return dto
+		},
+		Columns:  userMutableColumns,
+		NotFound: domainerrors.ErrUserNotFound,
+		// 并发注册或改名可能同时通过唯一性检查，由数据库约束兜底
+		Conflicts: map[string]error{
+			"uni_users_username":    domainerrors.ErrUsernameExists,
+			"uni_users_email_index": domainerrors.ErrEmailExists,
+		},
+		Seal: r.seal,
+		Open: r.open,
+	})
+	return r
 }
 
-// Create inserts a new user into the database
-func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	dto := model.UserDTO{}
-	dto.ConvertFromEntity(user)
+// emailIndex returns the blind index of email. Emails are compared
+// case-insensitively.
+func emailIndex(keyring *fieldcrypt.Keyring, email string) string {
+	return keyring.BlindIndex(strings.ToLower(email))
+}
 
-	err := dbFromContext(ctx, r.db).Create(&dto).Error
+// userEmailLocation returns the location the email of user id is encrypted
+// for.
+func userEmailLocation(id int64) fieldcrypt.Location {
+	return fieldcrypt.Location{Table: "users", Column: "email", RowID: id}
+}
+
+// seal encrypts the email of dto and sets its blind index.
+func (r *userRepository) seal(dto *model.UserDTO) error {
+	// 密文绑定行 ID，新用户需要在加密前分配 ID（否则由 BeforeCreate 分配）
+	if dto.ID == 0 {
+		dto.ID = snowflake.NextID()
+	}
+	index := emailIndex(r.keyring, dto.Email)
+	email, err := r.keyring.Encrypt(dto.Email, userEmailLocation(dto.ID))
 	if err != nil {
 		return err
 	}
+	dto.Email, dto.EmailIndex = email, &index
+	return nil
+}
 
-	// 在创建成功后，把 DTO 的 ID 同步回领域实体
-	*user = *dto.ConvertToEntity()
+// open decrypts the email of dto. Rows written before encryption was
+// introduced still hold plaintext, which is returned as is.
+func (r *userRepository) open(dto *model.UserDTO) error {
+	email, err := r.keyring.Decrypt(dto.Email, userEmailLocation(dto.ID))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt email of user %d: %w", dto.ID, err)
+	}
+	dto.Email = email
 	return nil
 }
 
+// Create inserts a new user into the database
+func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
+	return r.base.Create(ctx, user)
+}
+
 // FindByID retrieves a user by their ID
 func (r *userRepository) FindByID(ctx context.Context, id int64) (*entity.User, error) {
-	var dto model.UserDTO
-	err := dbFromContext(ctx, r.db).First(&dto, id).Error
-	return r.handleQueryResult(&dto, err)
+	return r.base.FindByID(ctx, id)
 }
 
 // FindByUsername retrieves a user by their username
 func (r *userRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
-	var dto model.UserDTO
-	err := dbFromContext(ctx, r.db).Where("username = ?", username).First(&dto).Error
-	return r.handleQueryResult(&dto, err)
+	return r.base.First(ctx, where("username = ?", username))
 }
 
-// FindByEmail retrieves a user by their email
+// FindByEmail retrieves a user by their email, looking it up by its blind
+// index. Users whose legacy plaintext email has not been indexed yet by
+// EmailKeyRotation are matched on the email column.
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
-	var dto model.UserDTO
-	err := dbFromContext(ctx, r.db).Where("email = ?", email).First(&dto).Error
-	return r.handleQueryResult(&dto, err)
+	return r.base.First(ctx, r.emailIs(email))
+}
+
+// emailIs is a Scope matching users with the given email, ignoring case.
+// Legacy plaintext rows not yet indexed by EmailKeyRotation are compared
+// case-insensitively too, like the blind index.
+func (r *userRepository) emailIs(email string) Scope {
+	return where("(email_index = ? OR (email_index IS NULL AND LOWER(email) = ?))", emailIndex(r.keyring, email), strings.ToLower(email))
+}
+
+// List retrieves users matching filter, ordered by ID and paginated per opts
+func (r *userRepository) List(ctx context.Context, filter repository.UserFilter, opts repository.ListOptions) ([]*entity.User, error) {
+	return r.base.List(ctx, opts, r.filter(filter))
+}
+
+// Count returns the number of users matching filter
+func (r *userRepository) Count(ctx context.Context, filter repository.UserFilter) (int64, error) {
+	return r.base.Count(ctx, r.filter(filter))
 }
 
-// Update updates an existing user in the database
+// userMutableColumns are the columns Update writes. The key, timestamps,
+// purge mark and version are managed by the repository itself.
+var userMutableColumns = []string{
+	"username", "email", "email_index", "password", "avatar_url", "avatar_key",
+	"display_name", "bio", "locale", "timezone", "website",
+}
+
+// Update writes every mutable column of an existing user, provided the row
+// still has user.Version. A missing row is reported as ErrNoRowsAffected
+// instead of being inserted (which GORM's Save would do), a newer version as
+// ErrConcurrentModification.
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	var dto model.UserDTO
-	dto.ConvertFromEntity(user)
-	result := dbFromContext(ctx, r.db).Save(&dto)
-	if result.Error != nil {
-		return result.Error
+	return r.base.Update(ctx, user)
+}
+
+// userFieldColumns whitelists the columns UpdateFields may write.
+var userFieldColumns = map[repository.UserField]string{
+	repository.UserFieldUsername:    "username",
+	repository.UserFieldEmail:       "email",
+	repository.UserFieldAvatarURL:   "avatar_url",
+	repository.UserFieldAvatarKey:   "avatar_key",
+	repository.UserFieldDisplayName: "display_name",
+	repository.UserFieldBio:         "bio",
+	repository.UserFieldLocale:      "locale",
+	repository.UserFieldTimezone:    "timezone",
+	repository.UserFieldWebsite:     "website",
+}
+
+// UpdateFields writes only the columns named by fields, taking their values
+// from user, provided the row still has user.Version. updated_at is refreshed
+// automatically.
+func (r *userRepository) UpdateFields(ctx context.Context, user *entity.User, fields ...repository.UserField) error {
+	if len(fields) == 0 {
+		return nil
 	}
-	if result.RowsAffected == 0 {
-		return domainerrors.ErrNoRowsAffected
+
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		column, ok := userFieldColumns[field]
+		if !ok {
+			return fmt.Errorf("user field %q is not updatable", field)
+		}
+		columns = append(columns, column)
+		// 邮箱与其盲索引总是一起写入
+		if column == "email" {
+			columns = append(columns, "email_index")
+		}
 	}
-	return nil
+	return r.base.Update(ctx, user, columns...)
 }
 
 // SoftDelete marks a user as deleted in the database
 func (r *userRepository) SoftDelete(ctx context.Context, id int64) error {
-	result := dbFromContext(ctx, r.db).Delete(&model.UserDTO{}, id)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return domainerrors.ErrNoRowsAffected
+	return r.base.SoftDelete(ctx, id)
+}
+
+// pendingDeletion scopes an unscoped query to soft-deleted users that are still restorable.
+func pendingDeletion(db *gorm.DB) *gorm.DB {
+	return db.Where("deleted_at IS NOT NULL AND purged_at IS NULL")
+}
+
+// FindDeletedByUsername retrieves a soft-deleted, not yet purged user by username
+func (r *userRepository) FindDeletedByUsername(ctx context.Context, username string) (*entity.User, error) {
+	return r.base.First(ctx, unscoped, pendingDeletion, where("username = ?", username))
+}
+
+// Restore clears the soft-delete mark of a user awaiting purge
+func (r *userRepository) Restore(ctx context.Context, id int64) error {
+	return r.base.UpdateColumns(ctx, map[string]any{"deleted_at": nil}, unscoped, pendingDeletion, where("id = ?", id))
+}
+
+// ListDeletedBefore retrieves users soft-deleted before the given time and not yet purged, oldest ID first
+func (r *userRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entity.User, error) {
+	return r.base.List(ctx, repository.ListOptions{Limit: limit}, unscoped, pendingDeletion, where("deleted_at < ?", before))
+}
+
+// HardDelete permanently removes a user from the database
+func (r *userRepository) HardDelete(ctx context.Context, id int64) error {
+	return r.base.HardDelete(ctx, id)
+}
+
+// Anonymize overwrites the personal data of a deleted user and marks it as purged.
+// The placeholder username and email are derived from the ID, so they stay unique
+// and the original identifiers become available again.
+//
+// 新增个人信息字段时需要同步在这里清空
+func (r *userRepository) Anonymize(ctx context.Context, id int64) error {
+	// UpdateColumns 不经过 Seal，占位邮箱需要在这里加密并计算盲索引
+	placeholder := &model.UserDTO{BaseModel: model.BaseModel{ID: id}, Email: fmt.Sprintf("deleted_%d@deleted.invalid", id)}
+	if err := r.seal(placeholder); err != nil {
+		return err
 	}
-	return nil
+	return r.base.UpdateColumns(ctx, map[string]any{
+		"username":     fmt.Sprintf("deleted_%d", id),
+		"email":        placeholder.Email,
+		"email_index":  placeholder.EmailIndex,
+		"password":     "",
+		"avatar_url":   nil,
+		"avatar_key":   nil,
+		"display_name": nil,
+		"bio":          nil,
+		"locale":       nil,
+		"timezone":     nil,
+		"website":      nil,
+		"purged_at":    time.Now(),
+	}, unscoped, where("id = ? AND deleted_at IS NOT NULL", id))
 }
 
-func (r *userRepository) handleQueryResult(dto *model.UserDTO, err error) (*entity.User, error) {
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, domainerrors.ErrUserNotFound
+// filter returns a Scope adding the WHERE clauses described by filter.
+func (r *userRepository) filter(filter repository.UserFilter) Scope {
+	return func(query *gorm.DB) *gorm.DB {
+		if filter.UsernamePrefix != "" {
+			query = query.Where("username LIKE ? ESCAPE ?", likePrefix(filter.UsernamePrefix), likeEscape)
 		}
-		return nil, err
+		if filter.CreatedAfter != nil {
+			query = query.Where("created_at >= ?", filter.CreatedAfter.UTC())
+		}
+		if filter.CreatedBefore != nil {
+			query = query.Where("created_at < ?", filter.CreatedBefore.UTC())
+		}
+		return query
 	}
-	return dto.ConvertToEntity(), nil
+}
+
+// likeEscape is the escape character of the patterns built by likePrefix.
+// Queries must name it with ESCAPE, since SQLite has no default escape
+// character. It is bound as a parameter rather than written as the literal
+// '\', which MySQL would read as the start of an escape sequence.
+const likeEscape = `\`
+
+// likeEscaper escapes LIKE wildcards with likeEscape so user input is
+// matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePrefix builds a LIKE pattern matching values that start with prefix;
+// use it with ESCAPE likeEscape.
+func likePrefix(prefix string) string {
+	return likeEscaper.Replace(prefix) + "%"
 }
@@ -0,0 +1,264 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/fieldcrypt"
+)
+
+// ─── Helper ───────────────────────────────────────────────────────────────────
+
+// newTestDB returns an in-memory SQLite database with the schema migrated.
+func newTestDB(t *testing.T) database.Database {
+	t.Helper()
+	db := sqlite.NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background(), 0)
+	require.NoError(t, err)
+	return db
+}
+
+// newTestKeyring returns a keyring with two keys, "old" and the active "new".
+func newTestKeyring(t *testing.T) *fieldcrypt.Keyring {
+	t.Helper()
+	keyring, err := fieldcrypt.NewKeyring(map[string][]byte{
+		"old": bytes.Repeat([]byte{1}, 32),
+		"new": bytes.Repeat([]byte{2}, 32),
+	}, "new", []byte("index-secret"))
+	require.NoError(t, err)
+	return keyring
+}
+
+// createUser inserts a user with an explicit ID, so no Snowflake node is needed.
+func createUser(t *testing.T, repo repository.UserRepository, id int64, username string) *entity.User {
+	t.Helper()
+	user := &entity.User{ID: id, Username: username, Email: username + "@example.com", Password: "hashed"}
+	require.NoError(t, repo.Create(context.Background(), user))
+	return user
+}
+
+// ─── Tests ────────────────────────────────────────────────────────────────────
+
+func TestUserRepository_CreateAndFind(t *testing.T) {
+	repo := NewUserRepository(newTestDB(t), newTestKeyring(t))
+	ctx := context.Background()
+	user := createUser(t, repo, 1, "kirk")
+
+	assert.Equal(t, int64(1), user.Version)
+	assert.False(t, user.CreatedAt.IsZero())
+
+	byID, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "kirk", byID.Username)
+
+	byUsername, err := repo.FindByUsername(ctx, "kirk")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), byUsername.ID)
+
+	byEmail, err := repo.FindByEmail(ctx, "kirk@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), byEmail.ID)
+
+	_, err = repo.FindByUsername(ctx, "nobody")
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+}
+
+func TestUserRepository_EncryptsEmail(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db, newTestKeyring(t))
+	ctx := context.Background()
+	createUser(t, repo, 1, "kirk")
+
+	var stored model.UserDTO
+	require.NoError(t, db.DB().First(&stored, 1).Error)
+	assert.True(t, strings.HasPrefix(stored.Email, "enc:new:"), "ciphertext at rest")
+	assert.NotContains(t, stored.Email, "kirk")
+	require.NotNil(t, stored.EmailIndex)
+
+	byEmail, err := repo.FindByEmail(ctx, "KIRK@example.com")
+	require.NoError(t, err, "blind index lookups ignore case")
+	assert.Equal(t, "kirk@example.com", byEmail.Email)
+
+	_, err = repo.FindByEmail(ctx, "kirk@")
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound, "an exact match, not a prefix")
+
+	// 改邮箱时盲索引随之更新
+	byEmail.Email = "james@example.com"
+	require.NoError(t, repo.UpdateFields(ctx, byEmail, repository.UserFieldEmail))
+	_, err = repo.FindByEmail(ctx, "kirk@example.com")
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+	_, err = repo.FindByEmail(ctx, "james@example.com")
+	assert.NoError(t, err)
+
+	// 加密之前写入的明文行仍可读取与查找
+	require.NoError(t, db.DB().Exec(
+		"INSERT INTO users (id, created_at, version, username, email, password) VALUES (2, ?, 1, 'spock', 'spock@example.com', 'hashed')",
+		time.Now()).Error)
+	legacy, err := repo.FindByEmail(ctx, "spock@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), legacy.ID)
+	// 未建索引的明文行同样忽略大小写，否则可以用不同大小写重复注册
+	legacy, err = repo.FindByEmail(ctx, "Spock@Example.COM")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), legacy.ID)
+
+	// 密文绑定了行 ID：复制到其他用户的行后无法解密
+	require.NoError(t, db.DB().First(&stored, 1).Error)
+	require.NoError(t, db.DB().Model(&model.UserDTO{}).Where("id = ?", 2).Update("email", stored.Email).Error)
+	_, err = repo.FindByID(ctx, 2)
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+}
+
+func TestUserRepository_UniqueViolations(t *testing.T) {
+	repo := NewUserRepository(newTestDB(t), newTestKeyring(t))
+	ctx := context.Background()
+	createUser(t, repo, 1, "kirk")
+	spock := createUser(t, repo, 2, "spock")
+
+	// 模拟并发注册：唯一性检查已通过，插入时才由约束发现冲突
+	err := repo.Create(ctx, &entity.User{ID: 3, Username: "kirk", Email: "james@example.com", Password: "hashed"})
+	assert.ErrorIs(t, err, domainerrors.ErrUsernameExists)
+	err = repo.Create(ctx, &entity.User{ID: 3, Username: "james", Email: "kirk@example.com", Password: "hashed"})
+	assert.ErrorIs(t, err, domainerrors.ErrEmailExists)
+
+	spock.Username = "kirk"
+	assert.ErrorIs(t, repo.UpdateFields(ctx, spock, repository.UserFieldUsername), domainerrors.ErrUsernameExists)
+	spock.Username, spock.Email = "spock", "kirk@example.com"
+	assert.ErrorIs(t, repo.Update(ctx, spock), domainerrors.ErrEmailExists)
+}
+
+func TestUserRepository_UpdateChecksVersion(t *testing.T) {
+	repo := NewUserRepository(newTestDB(t), newTestKeyring(t))
+	ctx := context.Background()
+	user := createUser(t, repo, 1, "kirk")
+	stale := *user
+
+	user.Username = "kirk2"
+	require.NoError(t, repo.Update(ctx, user))
+	assert.Equal(t, int64(2), user.Version)
+	assert.NotNil(t, user.UpdatedAt)
+
+	stale.Username = "kirk3"
+	assert.ErrorIs(t, repo.Update(ctx, &stale), domainerrors.ErrConcurrentModification)
+
+	missing := &entity.User{ID: 99, Username: "ghost", Email: "ghost@example.com", Version: 1}
+	assert.ErrorIs(t, repo.Update(ctx, missing), domainerrors.ErrNoRowsAffected)
+}
+
+func TestUserRepository_UpdateFieldsWritesOnlyMask(t *testing.T) {
+	repo := NewUserRepository(newTestDB(t), newTestKeyring(t))
+	ctx := context.Background()
+	user := createUser(t, repo, 1, "kirk")
+
+	bio := "hello"
+	user.Bio = &bio
+	user.Username = "not-written"
+	require.NoError(t, repo.UpdateFields(ctx, user, repository.UserFieldBio))
+
+	stored, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "kirk", stored.Username)
+	require.NotNil(t, stored.Bio)
+	assert.Equal(t, "hello", *stored.Bio)
+	assert.Equal(t, int64(2), stored.Version)
+
+	assert.Error(t, repo.UpdateFields(ctx, user, repository.UserField("password")))
+}
+
+func TestUserRepository_ListAndCount(t *testing.T) {
+	repo := NewUserRepository(newTestDB(t), newTestKeyring(t))
+	ctx := context.Background()
+	for i, name := range []string{"alice", "albert", "bob"} {
+		createUser(t, repo, int64(i+1), name)
+	}
+	filter := repository.UserFilter{UsernamePrefix: "al"}
+
+	users, err := repo.List(ctx, filter, repository.ListOptions{Order: repository.SortDesc})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "albert", users[0].Username)
+	assert.Equal(t, "alice", users[1].Username)
+
+	after, err := repo.List(ctx, repository.UserFilter{}, repository.ListOptions{AfterID: 1, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	assert.Equal(t, int64(2), after[0].ID)
+
+	total, err := repo.Count(ctx, filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+// Wildcards in the prefix are matched literally; SQLite only honours the
+// escapes because the query names the escape character.
+func TestUserRepository_ListEscapesPrefix(t *testing.T) {
+	repo := NewUserRepository(newTestDB(t), newTestKeyring(t))
+	ctx := context.Background()
+	for i, name := range []string{"kirk_lin", "kirkxlin", "kirk%lin", "kirk_li"} {
+		createUser(t, repo, int64(i+1), name)
+	}
+
+	users, err := repo.List(ctx, repository.UserFilter{UsernamePrefix: "kirk_lin"}, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "kirk_lin", users[0].Username)
+
+	users, err = repo.List(ctx, repository.UserFilter{UsernamePrefix: "kirk%"}, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "kirk%lin", users[0].Username)
+}
+
+func TestUserRepository_DeletionLifecycle(t *testing.T) {
+	repo := NewUserRepository(newTestDB(t), newTestKeyring(t))
+	ctx := context.Background()
+	createUser(t, repo, 1, "kirk")
+
+	require.NoError(t, repo.SoftDelete(ctx, 1))
+	_, err := repo.FindByID(ctx, 1)
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+
+	deleted, err := repo.FindDeletedByUsername(ctx, "kirk")
+	require.NoError(t, err)
+	assert.NotNil(t, deleted.DeletedAt)
+	assert.Equal(t, int64(2), deleted.Version)
+
+	due, err := repo.ListDeletedBefore(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	require.NoError(t, repo.Restore(ctx, 1))
+	restored, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.ErrorIs(t, repo.Restore(ctx, 1), domainerrors.ErrNoRowsAffected)
+
+	// 匿名化只作用于已注销的账号
+	assert.ErrorIs(t, repo.Anonymize(ctx, 1), domainerrors.ErrNoRowsAffected)
+	require.NoError(t, repo.SoftDelete(ctx, 1))
+	require.NoError(t, repo.Anonymize(ctx, 1))
+	_, err = repo.FindDeletedByUsername(ctx, "deleted_1")
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound, "purged users are no longer restorable")
+	_, err = repo.FindByEmail(ctx, "kirk@example.com")
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound, "the original email is released")
+
+	require.NoError(t, repo.HardDelete(ctx, 1))
+	assert.ErrorIs(t, repo.HardDelete(ctx, 1), domainerrors.ErrNoRowsAffected)
+}
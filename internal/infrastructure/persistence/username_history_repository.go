@@ -0,0 +1,67 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+type usernameHistoryRepository struct {
+	db database.Database
+}
+
+// NewUsernameHistoryRepository creates a new instance of UsernameHistoryRepository
+func NewUsernameHistoryRepository(db database.Database) repository.UsernameHistoryRepository {
+	return &usernameHistoryRepository{db: db}
+}
+
+// Create appends a rename to the history
+func (r *usernameHistoryRepository) Create(ctx context.Context, change *entity.UsernameChange) error {
+	dto := model.UsernameHistoryDTO{}
+	dto.ConvertFromEntity(change)
+
+	if err := dbFromContext(ctx, r.db).Create(&dto).Error; err != nil {
+		return err
+	}
+
+	*change = *dto.ConvertToEntity()
+	return nil
+}
+
+// ListByUserID retrieves the renames of a user, most recent first
+func (r *usernameHistoryRepository) ListByUserID(ctx context.Context, userID int64) ([]*entity.UsernameChange, error) {
+	var dtos []model.UsernameHistoryDTO
+	err := readDBFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").Order("id DESC").
+		Find(&dtos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*entity.UsernameChange, 0, len(dtos))
+	for i := range dtos {
+		changes = append(changes, dtos[i].ConvertToEntity())
+	}
+	return changes, nil
+}
+
+// IsReserved reports whether another user released username at or after since
+func (r *usernameHistoryRepository) IsReserved(ctx context.Context, username string, since time.Time, exceptUserID int64) (bool, error) {
+	var count int64
+	err := readDBFromContext(ctx, r.db).Model(&model.UsernameHistoryDTO{}).
+		Where("old_username = ? AND created_at >= ? AND user_id <> ?", username, since.UTC(), exceptUserID).
+		Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteByUserID permanently removes the rename history of a user
+func (r *usernameHistoryRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	return dbFromContext(ctx, r.db).Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&model.UsernameHistoryDTO{}).Error
+}
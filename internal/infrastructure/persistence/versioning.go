@@ -0,0 +1,44 @@
+package persistence
+
+import (
+	"gorm.io/gorm"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/infrastructure/persistence/model"
+)
+
+// bumpVersion advances the optimistic-locking version in map-based updates
+// that are not conditional on it, so that readers holding the old version
+// notice the change on their next conditional write.
+var bumpVersion = gorm.Expr("version + 1")
+
+// updateVersioned writes the given columns of dto, whose embedded BaseModel is
+// base, only if the stored row still has the version the caller read. On
+// success base.Version holds the new version.
+//
+// When no row matches it tells the two causes apart: ErrNoRowsAffected if the
+// row is gone, ErrConcurrentModification if someone else changed it first.
+func updateVersioned(db *gorm.DB, dto any, base *model.BaseModel, columns ...string) error {
+	expected := base.Version
+	base.Version = expected + 1
+
+	result := db.Model(dto).Where("version = ?", expected).
+		Select(append(columns, "version")).
+		Updates(dto)
+	if result.Error == nil && result.RowsAffected > 0 {
+		return nil
+	}
+	base.Version = expected
+	if result.Error != nil {
+		return result.Error
+	}
+
+	var count int64
+	if err := db.Model(dto).Where("id = ?", base.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domainerrors.ErrNoRowsAffected
+	}
+	return domainerrors.ErrConcurrentModification
+}
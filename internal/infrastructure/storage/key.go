@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// cleanKey normalises an object key and rejects keys that would escape the
+// storage root (absolute paths, ".." segments, empty keys).
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("storage: invalid object key %q", key)
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("storage: invalid object key %q", key)
+	}
+	return cleaned, nil
+}
+
+// joinURL joins a public base URL and an object key with exactly one slash.
+func joinURL(baseURL, key string) string {
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(key, "/")
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
+)
+
+// localStorage 本地文件系统存储实现，适用于开发环境和单机部署
+type localStorage struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalStorage creates an ObjectStorage that keeps objects under root.
+// baseURL is the public prefix the files are served from (e.g. "http://localhost:8888/uploads").
+func NewLocalStorage(root, baseURL string) (gateway.ObjectStorage, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("storage: resolve root %q: %w", root, err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: create root %q: %w", abs, err)
+	}
+	return &localStorage{root: abs, baseURL: baseURL}, nil
+}
+
+func (s *localStorage) path(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put writes to a temporary file first and renames it into place,
+// so readers never observe a partially written object.
+func (s *localStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && n != size {
+		return fmt.Errorf("storage: short write for %q: wrote %d of %d bytes", key, n, size)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *localStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domainerrors.ErrObjectNotFound.Wrap(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *localStorage) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStorage) URL(key string) string {
+	return joinURL(s.baseURL, key)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+)
+
+func TestLocalStorage_PutGetDelete(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root, "http://localhost:8888/uploads/")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "avatars/1/abc/64.png", strings.NewReader("png-bytes"), 9, "image/png"))
+	assert.FileExists(t, filepath.Join(root, "avatars", "1", "abc", "64.png"))
+
+	rc, err := s.Get(ctx, "avatars/1/abc/64.png")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "png-bytes", string(data))
+
+	assert.Equal(t, "http://localhost:8888/uploads/avatars/1/abc/64.png", s.URL("avatars/1/abc/64.png"))
+
+	require.NoError(t, s.Delete(ctx, "avatars/1/abc/64.png"))
+	_, err = s.Get(ctx, "avatars/1/abc/64.png")
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "OBJECT_NOT_FOUND", appErr.Code)
+
+	// Deleting a missing object is not an error
+	assert.NoError(t, s.Delete(ctx, "avatars/1/abc/64.png"))
+}
+
+func TestLocalStorage_PutReplacesExisting(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "/uploads")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "a.txt", strings.NewReader("old"), 3, "text/plain"))
+	require.NoError(t, s.Put(ctx, "a.txt", strings.NewReader("new!"), 4, "text/plain"))
+
+	rc, err := s.Get(ctx, "a.txt")
+	require.NoError(t, err)
+	defer rc.Close()
+	data, _ := io.ReadAll(rc)
+	assert.Equal(t, "new!", string(data))
+}
+
+func TestLocalStorage_ShortWriteLeavesNoObject(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root, "/uploads")
+	require.NoError(t, err)
+
+	err = s.Put(context.Background(), "a.txt", strings.NewReader("abc"), 10, "text/plain")
+
+	assert.Error(t, err)
+	entries, _ := os.ReadDir(root)
+	assert.Empty(t, entries, "neither the object nor the temp file should remain")
+}
+
+func TestLocalStorage_RejectsEscapingKeys(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(filepath.Join(root, "store"), "/uploads")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/etc/passwd", "../outside.txt", "a/../../outside.txt", `..\outside.txt`} {
+		t.Run(key, func(t *testing.T) {
+			assert.Error(t, s.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"))
+		})
+	}
+	assert.NoFileExists(t, filepath.Join(root, "outside.txt"))
+}
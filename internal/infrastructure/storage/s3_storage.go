@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
+)
+
+// S3Config holds the settings for an S3-compatible object store (AWS S3, MinIO, R2, ...).
+type S3Config struct {
+	Endpoint  string // host[:port], without scheme
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string // 为空时由 SDK 自动探测
+	UseSSL    bool
+
+	// PublicBaseURL is the prefix objects are publicly served from (e.g. a CDN).
+	// When empty, path-style URLs on Endpoint are used.
+	PublicBaseURL string
+
+	// Transport overrides the HTTP transport (mainly for tests).
+	Transport http.RoundTripper
+}
+
+// s3Storage S3 兼容对象存储实现
+type s3Storage struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+// NewS3Storage creates an ObjectStorage backed by an S3-compatible service.
+func NewS3Storage(cfg S3Config) (gateway.ObjectStorage, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		Transport:    cfg.Transport,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: create s3 client: %w", err)
+	}
+
+	baseURL := cfg.PublicBaseURL
+	if baseURL == "" {
+		scheme := "http"
+		if cfg.UseSSL {
+			scheme = "https"
+		}
+		baseURL = fmt.Sprintf("%s://%s/%s", scheme, cfg.Endpoint, cfg.Bucket)
+	}
+	return &s3Storage{client: client, bucket: cfg.Bucket, baseURL: baseURL}, nil
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, cleaned, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (s *s3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, cleaned, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s.translateError(err)
+	}
+	// GetObject is lazy; Stat forces the request so a missing key surfaces here
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		return nil, s.translateError(err)
+	}
+	return obj, nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	err = s.client.RemoveObject(ctx, s.bucket, cleaned, minio.RemoveObjectOptions{})
+	if err != nil && minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil
+	}
+	return err
+}
+
+func (s *s3Storage) URL(key string) string {
+	return joinURL(s.baseURL, key)
+}
+
+// translateError maps S3 "not found" responses to the domain error.
+func (s *s3Storage) translateError(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return domainerrors.ErrObjectNotFound.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+)
+
+// fakeS3 is a minimal in-process S3 server covering the path-style object
+// operations used by s3Storage: PUT, GET, HEAD and DELETE of single objects.
+// Request signatures are not verified.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: make(map[string]fakeObject)}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Path-style addressing: /<bucket>/<key>
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+		return
+	}
+	id := bucket + "/" + key
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readS3Body(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[id] = fakeObject{data: data, contentType: r.Header.Get("Content-Type")}
+		w.Header().Set("ETag", etag(data))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		obj, ok := f.objects[id]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message><Key>%s</Key><BucketName>%s</BucketName></Error>`, key, bucket)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("ETag", etag(obj.data))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(obj.data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) object(id string) (fakeObject, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[id]
+	return obj, ok
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// readS3Body returns the object payload, decoding aws-chunked framing when the
+// client uses streaming signatures or trailing checksums.
+func readS3Body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") &&
+		!strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") {
+		return io.ReadAll(r.Body)
+	}
+	var out []byte
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return out, nil // trailers, if any, are ignored
+		}
+		chunk := make([]byte, size+2) // payload + CRLF
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			return nil, err
+		}
+		out = append(out, chunk[:size]...)
+	}
+}
+
+func newTestS3Storage(t *testing.T, publicBaseURL string) (*fakeS3, *s3Storage) {
+	t.Helper()
+	fake := newFakeS3()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	s, err := NewS3Storage(S3Config{
+		Endpoint:      u.Host,
+		AccessKey:     "test-access",
+		SecretKey:     "test-secret",
+		Bucket:        "avatars-bucket",
+		Region:        "us-east-1",
+		PublicBaseURL: publicBaseURL,
+	})
+	require.NoError(t, err)
+	return fake, s.(*s3Storage)
+}
+
+func TestS3Storage_PutGetDelete(t *testing.T) {
+	fake, s := newTestS3Storage(t, "")
+	ctx := context.Background()
+	payload := strings.Repeat("avatar", 100)
+
+	require.NoError(t, s.Put(ctx, "avatars/1/abc/64.png", strings.NewReader(payload), int64(len(payload)), "image/png"))
+	obj, ok := fake.object("avatars-bucket/avatars/1/abc/64.png")
+	require.True(t, ok)
+	assert.Equal(t, payload, string(obj.data))
+	assert.Equal(t, "image/png", obj.contentType)
+
+	rc, err := s.Get(ctx, "avatars/1/abc/64.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(data))
+
+	require.NoError(t, s.Delete(ctx, "avatars/1/abc/64.png"))
+	_, ok = fake.object("avatars-bucket/avatars/1/abc/64.png")
+	assert.False(t, ok)
+}
+
+func TestS3Storage_GetMissingObject(t *testing.T) {
+	_, s := newTestS3Storage(t, "")
+
+	_, err := s.Get(context.Background(), "avatars/1/missing.png")
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "OBJECT_NOT_FOUND", appErr.Code)
+}
+
+func TestS3Storage_DeleteMissingObject(t *testing.T) {
+	_, s := newTestS3Storage(t, "")
+
+	assert.NoError(t, s.Delete(context.Background(), "avatars/1/missing.png"))
+}
+
+func TestS3Storage_URL(t *testing.T) {
+	_, s := newTestS3Storage(t, "")
+	assert.Regexp(t, `^http://127\.0\.0\.1:\d+/avatars-bucket/avatars/1/a\.png$`, s.URL("avatars/1/a.png"))
+
+	_, s = newTestS3Storage(t, "https://cdn.example.com/")
+	assert.Equal(t, "https://cdn.example.com/avatars/1/a.png", s.URL("avatars/1/a.png"))
+}
+
+func TestS3Storage_RejectsInvalidKeys(t *testing.T) {
+	fake, s := newTestS3Storage(t, "")
+
+	err := s.Put(context.Background(), "../escape", strings.NewReader("x"), 1, "text/plain")
+
+	assert.Error(t, err)
+	assert.Empty(t, fake.objects)
+}
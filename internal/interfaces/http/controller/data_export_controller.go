@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity/response"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+type DataExportController struct {
+	dataExportUseCase usecase.DataExportUseCase
+}
+
+func NewDataExportController(dataExportUseCase usecase.DataExportUseCase) *DataExportController {
+	return &DataExportController{
+		dataExportUseCase: dataExportUseCase,
+	}
+}
+
+// RequestExport queues an export of the current user's data.
+// The archive is built in the background; the download link is sent to the user.
+func (c *DataExportController) RequestExport(ctx *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, response.NewErrorResponse("Unauthorized", nil))
+		return
+	}
+
+	export, err := c.dataExportUseCase.RequestExport(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to request data export", err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, response.NewSuccessResponse("Data export requested; a download link will be sent when it is ready", export))
+}
+
+// DownloadExport streams the archive behind a signed download link.
+// The link itself is the credential, so this endpoint does not require a login.
+func (c *DataExportController) DownloadExport(ctx *gin.Context) {
+	export, archive, err := c.dataExportUseCase.OpenExport(ctx.Request.Context(), ctx.Param("token"))
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to download data export", err))
+		return
+	}
+	defer archive.Close()
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "application/zip")
+	header.Set("Content-Length", strconv.FormatInt(export.Size, 10))
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="data-export-%d.zip"`, export.ID))
+	// 归档包含个人数据，禁止任何中间层缓存
+	header.Set("Cache-Control", "no-store")
+	ctx.Status(http.StatusOK)
+	if _, err := io.Copy(ctx.Writer, archive); err != nil {
+		logger.FromContext(ctx.Request.Context()).Warnf("data export %d download interrupted: %v", export.ID, err)
+	}
+}
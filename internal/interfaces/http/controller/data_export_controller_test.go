@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+)
+
+func setupDataExportRouter(ctrl *DataExportController) *gin.Engine {
+	r := gin.New()
+	r.POST("/users/me/export", func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, int64(42))
+		c.Next()
+	}, ctrl.RequestExport)
+	r.GET("/exports/:token", ctrl.DownloadExport)
+	return r
+}
+
+func TestDataExportController_RequestExport_Accepted(t *testing.T) {
+	mockUC := new(testmock.MockDataExportUseCase)
+	router := setupDataExportRouter(NewDataExportController(mockUC))
+
+	mockUC.On("RequestExport", mock.Anything, int64(42)).Return(
+		&entity.DataExport{ID: 7, UserID: 42, Status: entity.DataExportPending}, nil,
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/export", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+}
+
+func TestDataExportController_DownloadExport_StreamsArchive(t *testing.T) {
+	mockUC := new(testmock.MockDataExportUseCase)
+	router := setupDataExportRouter(NewDataExportController(mockUC))
+
+	mockUC.On("OpenExport", mock.Anything, "signed-token").Return(
+		&entity.DataExport{ID: 7, Size: 8}, io.NopCloser(strings.NewReader("zip-data")), nil,
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/exports/signed-token", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "zip-data", w.Body.String())
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Equal(t, "8", w.Header().Get("Content-Length"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
+
+func TestDataExportController_DownloadExport_InvalidLink(t *testing.T) {
+	mockUC := new(testmock.MockDataExportUseCase)
+	router := setupDataExportRouter(NewDataExportController(mockUC))
+
+	mockUC.On("OpenExport", mock.Anything, "expired").Return(nil, nil, domainerrors.ErrDownloadLinkInvalid)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/exports/expired", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), "DOWNLOAD_LINK_INVALID")
+}
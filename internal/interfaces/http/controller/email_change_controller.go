@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity/response"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+)
+
+type EmailChangeController struct {
+	emailChangeUseCase usecase.EmailChangeUseCase
+}
+
+func NewEmailChangeController(emailChangeUseCase usecase.EmailChangeUseCase) *EmailChangeController {
+	return &EmailChangeController{
+		emailChangeUseCase: emailChangeUseCase,
+	}
+}
+
+// RequestEmailChange starts moving the current user's account to a new email address.
+// The address only changes once the link sent to it is confirmed.
+func (c *EmailChangeController) RequestEmailChange(ctx *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, response.NewErrorResponse("Unauthorized", nil))
+		return
+	}
+
+	var req entity.ChangeEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid input", err))
+		return
+	}
+
+	change, err := c.emailChangeUseCase.RequestEmailChange(ctx.Request.Context(), userID, &req)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to request email change", err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, response.NewSuccessResponse("A confirmation link has been sent to the new email address", change))
+}
+
+// ConfirmEmailChange applies the email change behind a confirmation token.
+func (c *EmailChangeController) ConfirmEmailChange(ctx *gin.Context) {
+	var req entity.EmailChangeTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid input", err))
+		return
+	}
+
+	user, err := c.emailChangeUseCase.ConfirmEmailChange(ctx.Request.Context(), req.Token)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to confirm email change", err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse("Email changed successfully", user))
+}
+
+// RevertEmailChange cancels or undoes the email change behind a revert token.
+func (c *EmailChangeController) RevertEmailChange(ctx *gin.Context) {
+	var req entity.EmailChangeTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid input", err))
+		return
+	}
+
+	user, err := c.emailChangeUseCase.RevertEmailChange(ctx.Request.Context(), req.Token)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to revert email change", err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse("Email change reverted", user))
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+)
+
+func setupEmailChangeRouter(ctrl *EmailChangeController) *gin.Engine {
+	r := gin.New()
+	r.POST("/users/me/email", func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, int64(42))
+		c.Next()
+	}, ctrl.RequestEmailChange)
+	r.POST("/email-changes/confirm", ctrl.ConfirmEmailChange)
+	r.POST("/email-changes/revert", ctrl.RevertEmailChange)
+	return r
+}
+
+func TestEmailChangeController_RequestEmailChange_Accepted(t *testing.T) {
+	mockUC := new(testmock.MockEmailChangeUseCase)
+	router := setupEmailChangeRouter(NewEmailChangeController(mockUC))
+
+	mockUC.On("RequestEmailChange", mock.Anything, int64(42),
+		&entity.ChangeEmailRequest{Email: "captain@example.com", Password: "securepass"}).
+		Return(&entity.EmailChange{ID: 9, UserID: 42, NewEmail: "captain@example.com", Status: entity.EmailChangePending}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/email",
+		strings.NewReader(`{"email":"captain@example.com","password":"securepass"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+}
+
+func TestEmailChangeController_ConfirmEmailChange_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{"invalid link", domainerrors.ErrEmailChangeLinkInvalid, http.StatusGone, "EMAIL_CHANGE_LINK_INVALID"},
+		{"address taken", domainerrors.ErrEmailExists, http.StatusConflict, "EMAIL_ALREADY_EXISTS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(testmock.MockEmailChangeUseCase)
+			router := setupEmailChangeRouter(NewEmailChangeController(mockUC))
+			mockUC.On("ConfirmEmailChange", mock.Anything, "signed-token").Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/email-changes/confirm", strings.NewReader(`{"token":"signed-token"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestEmailChangeController_RevertEmailChange_MissingToken(t *testing.T) {
+	mockUC := new(testmock.MockEmailChangeUseCase)
+	router := setupEmailChangeRouter(NewEmailChangeController(mockUC))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/email-changes/revert", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "RevertEmailChange")
+}
@@ -16,8 +16,9 @@ import (
 
 // InfraController handles infrastructure endpoints: welcome page, health probes.
 type InfraController struct {
-	db     database.Database
-	config *configs.AppConfig
+	db      database.Database
+	config  *configs.AppConfig
+	started func() bool
 
 	// Caching to prevent DB DoS via healthcheck endpoint
 	mu               sync.RWMutex
@@ -27,11 +28,14 @@ type InfraController struct {
 	cacheTTL         time.Duration
 }
 
-// NewInfraController creates a new InfraController.
-func NewInfraController(db database.Database, config *configs.AppConfig) *InfraController {
+// NewInfraController creates a new InfraController. started reports whether
+// the application has connected the database; until then Ready reports
+// "starting".
+func NewInfraController(db database.Database, config *configs.AppConfig, started func() bool) *InfraController {
 	return &InfraController{
 		db:       db,
 		config:   config,
+		started:  started,
 		cacheTTL: 5 * time.Second, // Max 1 DB ping every 5 seconds
 	}
 }
@@ -65,8 +69,18 @@ func (h *InfraController) Live(c *gin.Context) {
 // removes the pod from the Service load balancer without restarting it.
 //
 // This endpoint checks:
+//   - Startup: "starting" while the database is being connected at startup
 //   - Database connectivity (SQL ping with 2s timeout)
+//   - Read replicas in rotation, if configured (reported, never failing)
 func (h *InfraController) Ready(c *gin.Context) {
+	// 启动期间数据库尚未连接，不能 ping，也不缓存结果
+	if !h.started() {
+		c.JSON(http.StatusServiceUnavailable, response.NewSuccessResponse("starting", gin.H{
+			"database": gin.H{"status": "starting"},
+		}))
+		return
+	}
+
 	h.mu.RLock()
 	// If cache is still valid, return immediately (O(1) time, no DB call)
 	if time.Since(h.lastCheckTime) < h.cacheTTL {
@@ -120,6 +134,12 @@ func (h *InfraController) Ready(c *gin.Context) {
 		checks["database"] = gin.H{"status": "up"}
 	}
 
+	// Replicas are informational: without them reads fall back to the primary
+	if replicated, ok := h.db.(*database.ReplicatedDB); ok {
+		healthy, total := replicated.HealthyReplicas()
+		checks["replicas"] = gin.H{"healthy": healthy, "total": total}
+	}
+
 	// Update cache
 	h.cachedAllHealthy = allHealthy
 	h.cachedChecks = checks
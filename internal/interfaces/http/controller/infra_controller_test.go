@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/configs"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+)
+
+func TestInfraController_Ready(t *testing.T) {
+	// 启动期间数据库对象尚未连接，Ready 不能触碰它
+	db := sqlite.NewSQLiteDB()
+	started := false
+	ctrl := NewInfraController(db, &configs.AppConfig{Environment: "test"}, func() bool { return started })
+	r := gin.New()
+	r.GET("/health/ready", ctrl.Ready)
+	ready := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := ready()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"starting"`)
+	assert.Contains(t, w.Body.String(), `"status":"starting"`)
+
+	require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	t.Cleanup(func() { _ = db.Close() })
+	started = true
+	w = ready()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"database":{"status":"up"}`)
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity/response"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+)
+
+type PreferenceController struct {
+	preferenceUseCase usecase.PreferenceUseCase
+}
+
+func NewPreferenceController(preferenceUseCase usecase.PreferenceUseCase) *PreferenceController {
+	return &PreferenceController{
+		preferenceUseCase: preferenceUseCase,
+	}
+}
+
+// GetPreferences returns the current user's preferences, defaults included.
+func (c *PreferenceController) GetPreferences(ctx *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, response.NewErrorResponse("Unauthorized", nil))
+		return
+	}
+
+	prefs, err := c.preferenceUseCase.GetPreferences(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to get preferences", err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse("Preferences retrieved successfully", prefs))
+}
+
+// UpdatePreferences merge-patches the current user's preferences.
+// Keys sent as null are reset to their defaults.
+func (c *PreferenceController) UpdatePreferences(ctx *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, response.NewErrorResponse("Unauthorized", nil))
+		return
+	}
+
+	var req entity.UpdatePreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid input", err))
+		return
+	}
+
+	prefs, err := c.preferenceUseCase.UpdatePreferences(ctx.Request.Context(), userID, req)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to update preferences", err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse("Preferences updated successfully", prefs))
+}
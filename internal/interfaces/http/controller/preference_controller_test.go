@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+)
+
+func setupPreferenceRouter(ctrl *PreferenceController) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, int64(42))
+		c.Next()
+	})
+	r.GET("/users/me/preferences", ctrl.GetPreferences)
+	r.PATCH("/users/me/preferences", ctrl.UpdatePreferences)
+	return r
+}
+
+func TestPreferenceController_GetPreferences_Success(t *testing.T) {
+	mockUC := new(testmock.MockPreferenceUseCase)
+	router := setupPreferenceRouter(NewPreferenceController(mockUC))
+
+	mockUC.On("GetPreferences", mock.Anything, int64(42)).
+		Return(entity.Preferences{"theme": "system", "notifications.email": true}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users/me/preferences", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"theme":"system"`)
+	assert.Contains(t, w.Body.String(), `"notifications.email":true`)
+}
+
+func TestPreferenceController_UpdatePreferences_ValidationFailed(t *testing.T) {
+	mockUC := new(testmock.MockPreferenceUseCase)
+	router := setupPreferenceRouter(NewPreferenceController(mockUC))
+
+	mockUC.On("UpdatePreferences", mock.Anything, int64(42), mock.Anything).
+		Return(nil, domainerrors.ErrValidationFailed.WithMessage(`unknown preference "font"`))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/users/me/preferences", strings.NewReader(`{"font":"mono"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "VALIDATION_FAILED")
+}
+
+func TestPreferenceController_UpdatePreferences_RejectsNonObject(t *testing.T) {
+	mockUC := new(testmock.MockPreferenceUseCase)
+	router := setupPreferenceRouter(NewPreferenceController(mockUC))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/users/me/preferences", strings.NewReader(`["theme"]`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "UpdatePreferences")
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity/response"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
 	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
 )
@@ -35,9 +37,69 @@ func (c *UserController) GetUser(ctx *gin.Context) {
 		return
 	}
 
+	// 只有本人能看到完整资料（邮箱、语言、时区），其他人只返回公开字段
+	if currentUserID, ok := middleware.GetUserIDFromContext(ctx); !ok || currentUserID != user.ID {
+		ctx.JSON(http.StatusOK, response.NewSuccessResponse("User retrieved successfully", user.Public()))
+		return
+	}
+	// ETag 只随本人可见的完整资料返回，本人据此在修改时携带 If-Match
+	middleware.SetETag(ctx, user.ID, user.Version)
 	ctx.JSON(http.StatusOK, response.NewSuccessResponse("User retrieved successfully", user))
 }
 
+// GetPublicProfile returns the public projection of a user. It needs no
+// authentication, so the response is the same for every caller and may be
+// stored by shared caches.
+func (c *UserController) GetPublicProfile(ctx *gin.Context) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid user ID", err))
+		return
+	}
+
+	user, err := c.userUseCase.GetUserByID(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to get user", err))
+		return
+	}
+
+	if user.UpdatedAt != nil {
+		middleware.SetLastModified(ctx, *user.UpdatedAt)
+	}
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse("User retrieved successfully", user.Public()))
+}
+
+// ListUsers lists users, filtered by the query parameters:
+//
+//   - username: prefix of the username; wildcards such as % and _ are literal
+//   - created_after, created_before: RFC 3339 times bounding the registration
+//
+// Results are paginated by page/page_size or by the page_token of the
+// previous page. There is deliberately no email filter: any logged-in user
+// may list users, and must not learn which account owns an address.
+func (c *UserController) ListUsers(ctx *gin.Context) {
+	var req entity.ListUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid query parameters", err))
+		return
+	}
+
+	result, err := c.userUseCase.ListUsers(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to list users", err))
+		return
+	}
+
+	// 列表面向所有登录用户，一律返回公开字段
+	users := make([]*entity.PublicUser, 0, len(result.Users))
+	for i := range result.Users {
+		users = append(users, result.Users[i].Public())
+	}
+	ctx.JSON(http.StatusOK, response.NewCursorPageResponse(
+		"Users retrieved successfully", users, result.Page, result.PageSize, result.Total, result.NextPageToken,
+	))
+}
+
 func (c *UserController) GetCurrentUser(ctx *gin.Context) {
 	userID, exists := middleware.GetUserIDFromContext(ctx)
 	if !exists {
@@ -51,35 +113,106 @@ func (c *UserController) GetCurrentUser(ctx *gin.Context) {
 		return
 	}
 
+	middleware.SetETag(ctx, user.ID, user.Version)
 	ctx.JSON(http.StatusOK, response.NewSuccessResponse("User retrieved successfully", user))
 }
 
-func (c *UserController) UpdateUser(ctx *gin.Context) {
-	var user entity.User
-	if err := ctx.ShouldBindJSON(&user); err != nil {
+func (c *UserController) UpdateProfile(ctx *gin.Context) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid user ID", err))
+		return
+	}
+
+	var req entity.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid input", err))
 		return
 	}
 
-	if err := c.userUseCase.UpdateUser(ctx.Request.Context(), &user); err != nil {
+	user, err := c.userUseCase.UpdateProfile(ctx.Request.Context(), userID, &req)
+	if err != nil {
 		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to update user", err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessResponse[any]("User updated successfully", nil))
+	middleware.SetETag(ctx, user.ID, user.Version)
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse("User updated successfully", user))
 }
 
-func (c *UserController) DeleteUser(ctx *gin.Context) {
+func (c *UserController) ChangeUsername(ctx *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, response.NewErrorResponse("Unauthorized", nil))
+		return
+	}
+
+	var req entity.ChangeUsernameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid input", err))
+		return
+	}
+
+	user, err := c.userUseCase.ChangeUsername(ctx.Request.Context(), userID, &req)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to change username", err))
+		return
+	}
+
+	middleware.SetETag(ctx, user.ID, user.Version)
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse("Username changed successfully", user))
+}
+
+func (c *UserController) DeleteCurrentUser(ctx *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, response.NewErrorResponse("Unauthorized", nil))
+		return
+	}
+
+	var req entity.DeleteAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid input", err))
+		return
+	}
+
+	if err := c.userUseCase.DeleteAccount(ctx.Request.Context(), userID, &req); err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to delete account", err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse[any]("Account scheduled for deletion", nil))
+}
+
+func (c *UserController) UploadAvatar(ctx *gin.Context) {
 	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid user ID", err))
 		return
 	}
 
-	if err := c.userUseCase.SoftDeleteUser(ctx.Request.Context(), userID); err != nil {
-		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to delete user", err))
+	fileHeader, err := ctx.FormFile("avatar")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, response.NewErrorResponse("Avatar is too large", domainerrors.ErrPayloadTooLarge))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Missing avatar file", err))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.NewErrorResponse("Invalid avatar file", err))
+		return
+	}
+	defer file.Close()
+
+	result, err := c.userUseCase.UploadAvatar(ctx.Request.Context(), userID, file)
+	if err != nil {
+		ctx.JSON(response.HTTPCodeFromError(err, http.StatusInternalServerError), response.NewErrorResponse("Failed to upload avatar", err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessResponse[any]("User deleted successfully", nil))
+	ctx.JSON(http.StatusOK, response.NewSuccessResponse("Avatar uploaded successfully", result))
 }
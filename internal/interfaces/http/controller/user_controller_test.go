@@ -3,9 +3,12 @@ package controller
 import (
 	"bytes"
 	"encoding/json"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -19,9 +22,11 @@ import (
 
 func setupUserRouter(ctrl *UserController) *gin.Engine {
 	r := gin.New()
+	r.GET("/users", ctrl.ListUsers)
 	r.GET("/users/:id", ctrl.GetUser)
-	r.PUT("/users/:id", ctrl.UpdateUser)
-	r.DELETE("/users/:id", ctrl.DeleteUser)
+	r.GET("/profiles/:id", ctrl.GetPublicProfile)
+	r.PATCH("/users/:id", ctrl.UpdateProfile)
+	r.POST("/users/:id/avatar", ctrl.UploadAvatar)
 	return r
 }
 
@@ -59,6 +64,27 @@ func TestUserController_GetUser_NotFound(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "USER_NOT_FOUND")
 }
 
+func TestUserController_GetPublicProfile_Success(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupUserRouter(ctrl)
+
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockUC.On("GetUserByID", mock.Anything, int64(1)).Return(
+		&entity.User{ID: 1, Username: "kirk", Email: "kirk@example.com", UpdatedAt: &updatedAt}, nil,
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/profiles/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "kirk")
+	// 公开资料可被共享缓存存储，绝不能包含私有字段
+	assert.NotContains(t, w.Body.String(), "kirk@example.com")
+	assert.Equal(t, "Sun, 01 Mar 2026 12:00:00 GMT", w.Header().Get("Last-Modified"))
+}
+
 func TestUserController_GetUser_InvalidID(t *testing.T) {
 	mockUC := new(testmock.MockUserUseCase)
 	ctrl := NewUserController(mockUC)
@@ -71,99 +97,239 @@ func TestUserController_GetUser_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestUserController_DeleteUser_Success(t *testing.T) {
+// ─── UpdateProfile ────────────────────────────────────────────────────────────
+
+func TestUserController_UpdateProfile_Success(t *testing.T) {
 	mockUC := new(testmock.MockUserUseCase)
 	ctrl := NewUserController(mockUC)
 	router := setupUserRouter(ctrl)
 
-	mockUC.On("SoftDeleteUser", mock.Anything, int64(1)).Return(nil)
+	avatar := "https://cdn.example.com/a.png"
+	mockUC.On("UpdateProfile", mock.Anything, int64(1), mock.MatchedBy(func(req *entity.UpdateProfileRequest) bool {
+		return req.AvatarURL.Set && *req.AvatarURL.Value == avatar
+	})).Return(&entity.User{ID: 1, Username: "kirk", AvatarURL: &avatar}, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodDelete, "/users/1", nil)
+	req, _ := http.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"avatar_url":"`+avatar+`"}`))
+	req.Header.Set("Content-Type", "application/json")
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-
-	var resp map[string]any
-	err := json.Unmarshal(w.Body.Bytes(), &resp)
-	assert.NoError(t, err)
-	assert.Equal(t, "success", resp["status"])
+	assert.Contains(t, w.Body.String(), "User updated successfully")
+	assert.Contains(t, w.Body.String(), avatar)
 }
 
-func TestUserController_DeleteUser_NotFound(t *testing.T) {
+func TestUserController_UpdateProfile_UsesPathID(t *testing.T) {
 	mockUC := new(testmock.MockUserUseCase)
 	ctrl := NewUserController(mockUC)
 	router := setupUserRouter(ctrl)
 
-	mockUC.On("SoftDeleteUser", mock.Anything, int64(999)).Return(domainerrors.ErrUserNotFound)
+	// An "id" in the body must never redirect the update to another user.
+	mockUC.On("UpdateProfile", mock.Anything, int64(7), mock.Anything).Return(&entity.User{ID: 7}, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodDelete, "/users/999", nil)
+	req, _ := http.NewRequest(http.MethodPatch, "/users/7", bytes.NewBufferString(`{"id":"1","avatar_url":null}`))
+	req.Header.Set("Content-Type", "application/json")
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
 }
 
-// ─── UpdateUser ───────────────────────────────────────────────────────────────
-
-func TestUserController_UpdateUser_Success(t *testing.T) {
+func TestUserController_UpdateProfile_ValidationFails(t *testing.T) {
 	mockUC := new(testmock.MockUserUseCase)
 	ctrl := NewUserController(mockUC)
 	router := setupUserRouter(ctrl)
 
-	mockUC.On("UpdateUser", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	mockUC.On("UpdateProfile", mock.Anything, int64(1), mock.Anything).Return(
+		nil, domainerrors.ErrValidationFailed.WithMessage("avatar_url must be an absolute http(s) URL"),
+	)
 
-	body := toJSON(t, entity.User{ID: 1, Username: "kirk", Email: "kirk@example.com", Password: "securepass"})
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodPut, "/users/1", body)
+	req, _ := http.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"avatar_url":"not a url"}`))
 	req.Header.Set("Content-Type", "application/json")
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), "User updated successfully")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestUserController_UpdateUser_ValidationFails(t *testing.T) {
+func TestUserController_UpdateProfile_VersionConflicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"stale If-Match", domainerrors.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		{"concurrent write", domainerrors.ErrConcurrentModification, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(testmock.MockUserUseCase)
+			ctrl := NewUserController(mockUC)
+			router := setupUserRouter(ctrl)
+
+			mockUC.On("UpdateProfile", mock.Anything, int64(1), mock.Anything).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"bio":"hi"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Empty(t, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestUserController_UpdateProfile_InvalidJSON(t *testing.T) {
 	mockUC := new(testmock.MockUserUseCase)
 	ctrl := NewUserController(mockUC)
 	router := setupUserRouter(ctrl)
 
-	mockUC.On("UpdateUser", mock.Anything, mock.AnythingOfType("*entity.User")).Return(
-		domainerrors.ErrValidationFailed.WithMessage("username cannot be empty"),
-	)
-
-	body := toJSON(t, entity.User{ID: 1, Username: "", Email: "kirk@example.com", Password: "securepass"})
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodPut, "/users/1", body)
+	req, _ := http.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{invalid`))
 	req.Header.Set("Content-Type", "application/json")
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestUserController_UpdateUser_InvalidJSON(t *testing.T) {
+func TestUserController_UpdateProfile_InvalidID(t *testing.T) {
 	mockUC := new(testmock.MockUserUseCase)
 	ctrl := NewUserController(mockUC)
 	router := setupUserRouter(ctrl)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodPut, "/users/1", bytes.NewBufferString(`{invalid`))
+	req, _ := http.NewRequest(http.MethodPatch, "/users/abc", bytes.NewBufferString(`{}`))
 	req.Header.Set("Content-Type", "application/json")
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestUserController_DeleteUser_InvalidID(t *testing.T) {
+// ─── ListUsers ────────────────────────────────────────────────────────────────
+
+func TestUserController_ListUsers_Success(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupUserRouter(ctrl)
+
+	mockUC.On("ListUsers", mock.Anything, mock.MatchedBy(func(req *entity.ListUsersRequest) bool {
+		return req.Username == "ki" && req.Order == "desc" && req.PageSize == 1
+	})).Return(&entity.ListUsersResponse{
+		Users:         []entity.User{{ID: 1, Username: "kirk"}},
+		Page:          1,
+		PageSize:      1,
+		Total:         2,
+		NextPageToken: "next-token",
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users?username=ki&order=desc&page_size=1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "kirk")
+	assert.Contains(t, w.Body.String(), `"next_page_token":"next-token"`)
+	assert.Contains(t, w.Body.String(), `"has_next":true`)
+}
+
+func TestUserController_ListUsers_InvalidQuery(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupUserRouter(ctrl)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users?order=sideways&page_size=1000", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "ListUsers")
+}
+
+func TestUserController_ListUsers_InvalidPageToken(t *testing.T) {
 	mockUC := new(testmock.MockUserUseCase)
 	ctrl := NewUserController(mockUC)
 	router := setupUserRouter(ctrl)
 
+	mockUC.On("ListUsers", mock.Anything, mock.Anything).Return(nil, domainerrors.ErrInvalidPageToken)
+
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodDelete, "/users/abc", nil)
+	req, _ := http.NewRequest(http.MethodGet, "/users?page_token=forged", nil)
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_PAGE_TOKEN")
+}
+
+// ─── Public projection ────────────────────────────────────────────────────────
+
+func setupAuthenticatedUserRouter(ctrl *UserController, currentUserID int64) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, currentUserID)
+		c.Next()
+	})
+	r.GET("/users", ctrl.ListUsers)
+	r.GET("/users/:id", ctrl.GetUser)
+	return r
+}
+
+func profileUser(id int64) *entity.User {
+	name, locale, tz := "Kirk", "en-US", "America/New_York"
+	return &entity.User{ID: id, Username: "kirk", Email: "kirk@example.com", DisplayName: &name, Locale: &locale, Timezone: &tz}
+}
+
+func TestUserController_GetUser_OtherUserSeesPublicProfile(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupAuthenticatedUserRouter(ctrl, 2)
+
+	mockUC.On("GetUserByID", mock.Anything, int64(1)).Return(profileUser(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"display_name":"Kirk"`)
+	assert.NotContains(t, w.Body.String(), "kirk@example.com")
+	assert.NotContains(t, w.Body.String(), "America/New_York")
+}
+
+func TestUserController_GetUser_SelfSeesFullProfile(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupAuthenticatedUserRouter(ctrl, 1)
+
+	mockUC.On("GetUserByID", mock.Anything, int64(1)).Return(profileUser(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "kirk@example.com")
+	assert.Contains(t, w.Body.String(), `"timezone":"America/New_York"`)
+}
+
+func TestUserController_ListUsers_ReturnsPublicProfiles(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupAuthenticatedUserRouter(ctrl, 1)
+
+	mockUC.On("ListUsers", mock.Anything, mock.Anything).Return(&entity.ListUsersResponse{
+		Users: []entity.User{*profileUser(1), *profileUser(2)}, Page: 1, PageSize: 20, Total: 2,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"display_name":"Kirk"`)
+	assert.NotContains(t, w.Body.String(), "kirk@example.com")
 }
 
 // ─── GetCurrentUser ───────────────────────────────────────────────────────────
@@ -185,7 +351,7 @@ func TestUserController_GetCurrentUser_Success(t *testing.T) {
 	router := setupCurrentUserRouter(ctrl)
 
 	mockUC.On("GetUserByID", mock.Anything, int64(42)).Return(
-		&entity.User{ID: 42, Username: "kirk"}, nil,
+		&entity.User{ID: 42, Username: "kirk", Version: 5}, nil,
 	)
 
 	w := httptest.NewRecorder()
@@ -194,6 +360,133 @@ func TestUserController_GetCurrentUser_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "kirk")
+	assert.Equal(t, `"42-5"`, w.Header().Get("ETag"), "the tag carries the user ID")
+	assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+}
+
+func setupChangeUsernameRouter(ctrl *UserController) *gin.Engine {
+	r := gin.New()
+	r.PUT("/me/username", func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, int64(42))
+		c.Next()
+	}, ctrl.ChangeUsername)
+	return r
+}
+
+func TestUserController_ChangeUsername_Success(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	r := setupChangeUsernameRouter(NewUserController(mockUC))
+
+	mockUC.On("ChangeUsername", mock.Anything, int64(42), &entity.ChangeUsernameRequest{Username: "captain"}).
+		Return(&entity.User{ID: 42, Username: "captain"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/me/username", bytes.NewBufferString(`{"username":"captain"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"username":"captain"`)
+}
+
+func TestUserController_ChangeUsername_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{"taken", domainerrors.ErrUsernameExists, http.StatusConflict, "USERNAME_ALREADY_EXISTS"},
+		{"cooldown", domainerrors.ErrUsernameChangeTooSoon, http.StatusTooManyRequests, "USERNAME_CHANGE_TOO_SOON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(testmock.MockUserUseCase)
+			r := setupChangeUsernameRouter(NewUserController(mockUC))
+			mockUC.On("ChangeUsername", mock.Anything, int64(42), mock.Anything).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, "/me/username", bytes.NewBufferString(`{"username":"spock"}`))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestUserController_ChangeUsername_MissingUsername(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	r := setupChangeUsernameRouter(NewUserController(mockUC))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/me/username", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "ChangeUsername")
+}
+
+func TestUserController_DeleteCurrentUser_Success(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	r := gin.New()
+	r.DELETE("/me", func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, int64(42))
+		c.Next()
+	}, ctrl.DeleteCurrentUser)
+
+	mockUC.On("DeleteAccount", mock.Anything, int64(42), &entity.DeleteAccountRequest{Password: "securepass"}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/me", bytes.NewBufferString(`{"password":"securepass"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp["status"])
+}
+
+func TestUserController_DeleteCurrentUser_RequiresPassword(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	r := gin.New()
+	r.DELETE("/me", func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, int64(42))
+		c.Next()
+	}, ctrl.DeleteCurrentUser)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/me", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "DeleteAccount")
+}
+
+func TestUserController_DeleteCurrentUser_WrongPassword(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	r := gin.New()
+	r.DELETE("/me", func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, int64(42))
+		c.Next()
+	}, ctrl.DeleteCurrentUser)
+
+	mockUC.On("DeleteAccount", mock.Anything, int64(42), mock.Anything).Return(domainerrors.ErrPasswordConfirmation)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/me", bytes.NewBufferString(`{"password":"wrong"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "PASSWORD_CONFIRMATION_FAILED")
 }
 
 func TestUserController_GetCurrentUser_NoAuth(t *testing.T) {
@@ -207,3 +500,84 @@ func TestUserController_GetCurrentUser_NoAuth(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+// newAvatarUploadRequest builds a multipart request carrying content in the given form field.
+func newAvatarUploadRequest(t *testing.T, target, field string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile(field, "avatar.png")
+	assert.NoError(t, err)
+	_, _ = part.Write(content)
+	assert.NoError(t, mw.Close())
+
+	req, _ := http.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestUserController_UploadAvatar_Success(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupUserRouter(ctrl)
+
+	mockUC.On("UploadAvatar", mock.Anything, int64(1), mock.MatchedBy(func(r io.Reader) bool {
+		data, _ := io.ReadAll(r)
+		return string(data) == "image-bytes"
+	})).Return(&entity.AvatarResponse{
+		AvatarURL: "https://cdn.example.com/avatars/1/x/512.png",
+		Sizes:     map[string]string{"512": "https://cdn.example.com/avatars/1/x/512.png"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newAvatarUploadRequest(t, "/users/1/avatar", "avatar", []byte("image-bytes")))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Avatar uploaded successfully")
+	assert.Contains(t, w.Body.String(), "avatars/1/x/512.png")
+	mockUC.AssertExpectations(t)
+}
+
+func TestUserController_UploadAvatar_MissingFile(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupUserRouter(ctrl)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newAvatarUploadRequest(t, "/users/1/avatar", "file", []byte("image-bytes")))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "UploadAvatar")
+}
+
+func TestUserController_UploadAvatar_BodyTooLarge(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	r := gin.New()
+	r.POST("/users/:id/avatar", middleware.BodyLimitMiddleware(1024), ctrl.UploadAvatar)
+
+	req := newAvatarUploadRequest(t, "/users/1/avatar", "avatar", bytes.Repeat([]byte("x"), 4096))
+	req.ContentLength = -1 // force the streaming limit rather than the Content-Length check
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "PAYLOAD_TOO_LARGE")
+	mockUC.AssertNotCalled(t, "UploadAvatar")
+}
+
+func TestUserController_UploadAvatar_UnsupportedType(t *testing.T) {
+	mockUC := new(testmock.MockUserUseCase)
+	ctrl := NewUserController(mockUC)
+	router := setupUserRouter(ctrl)
+
+	mockUC.On("UploadAvatar", mock.Anything, int64(1), mock.Anything).Return(
+		nil, domainerrors.ErrUnsupportedMediaType.WithMessage("Avatar must be a JPEG, PNG, GIF or WebP image"),
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newAvatarUploadRequest(t, "/users/1/avatar", "avatar", []byte("%PDF-1.4")))
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Contains(t, w.Body.String(), "UNSUPPORTED_MEDIA_TYPE")
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity/response"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+)
+
+// BodyLimitMiddleware caps the request body at limit bytes.
+// Requests that declare a larger Content-Length are rejected up front; bodies
+// without a declared length fail with *http.MaxBytesError once the cap is read.
+func BodyLimitMiddleware(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			c.JSON(http.StatusRequestEntityTooLarge, response.NewErrorResponse(domainerrors.ErrPayloadTooLarge.Message, domainerrors.ErrPayloadTooLarge))
+			c.Abort()
+			return
+		}
+
+		// 超出上限后继续读取会返回错误，防止超大请求体占满内存或临时磁盘
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
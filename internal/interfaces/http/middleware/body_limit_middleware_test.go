@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupBodyLimitedRouter(limit int64) *gin.Engine {
+	r := gin.New()
+	r.Use(BodyLimitMiddleware(limit))
+	r.POST("/test", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "too large"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"size": len(body)})
+	})
+	return r
+}
+
+func TestBodyLimit_AllowsUnderLimit(t *testing.T) {
+	router := setupBodyLimitedRouter(10)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader("hello"))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"size":5`)
+}
+
+func TestBodyLimit_RejectsDeclaredLength(t *testing.T) {
+	router := setupBodyLimitedRouter(10)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(strings.Repeat("x", 11)))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "PAYLOAD_TOO_LARGE")
+}
+
+func TestBodyLimit_CapsUndeclaredLength(t *testing.T) {
+	router := setupBodyLimitedRouter(10)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/test", io.NopCloser(strings.NewReader(strings.Repeat("x", 11))))
+	req.ContentLength = -1
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
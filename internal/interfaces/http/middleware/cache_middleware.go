@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CachePolicy is the Cache-Control policy a route declares for its successful
+// GET responses.
+type CachePolicy struct {
+	// Public allows shared caches (nginx, CDNs) to store the response.
+	// Otherwise it is marked private to the requesting client.
+	Public bool
+	// MaxAge is how long the response stays fresh. Zero means every reuse
+	// must be revalidated (no-cache), which is cheap thanks to ETags.
+	MaxAge time.Duration
+	// SharedMaxAge overrides MaxAge for shared caches (s-maxage). Zero omits it.
+	SharedMaxAge time.Duration
+	// NoStore forbids caching altogether; the other fields are ignored.
+	NoStore bool
+}
+
+var (
+	// CachePrivateRevalidate lets the client keep the response but makes it
+	// revalidate on every use. Suits per-user data.
+	CachePrivateRevalidate = CachePolicy{}
+	// CacheNoStore suits responses that must never be written to any cache.
+	CacheNoStore = CachePolicy{NoStore: true}
+)
+
+// String formats the policy as a Cache-Control header value.
+func (p CachePolicy) String() string {
+	if p.NoStore {
+		return "no-store"
+	}
+
+	directives := []string{"private"}
+	if p.Public {
+		directives[0] = "public"
+	}
+	if p.MaxAge > 0 {
+		directives = append(directives, "max-age="+strconv.Itoa(int(p.MaxAge.Seconds())))
+	} else {
+		directives = append(directives, "no-cache")
+	}
+	if p.Public && p.SharedMaxAge > 0 {
+		directives = append(directives, "s-maxage="+strconv.Itoa(int(p.SharedMaxAge.Seconds())))
+	}
+	return strings.Join(directives, ", ")
+}
+
+// SetLastModified sets the Last-Modified response header, typically from the
+// resource's UpdatedAt, so that clients can revalidate with If-Modified-Since.
+func SetLastModified(c *gin.Context, t time.Time) {
+	c.Header("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
+
+// CacheMiddleware applies policy to successful GET responses and answers
+// conditional requests.
+//
+// The response is buffered so that an ETag can be computed from the body when
+// the handler did not set one (e.g. via SetETag). If-None-Match is then
+// compared against the ETag and, only when it is absent, If-Modified-Since
+// against Last-Modified (see SetLastModified); a match turns the response
+// into a bodiless 304. A Cache-Control header set by the handler wins over
+// policy, so a handler can narrow it for a particular response.
+func CacheMiddleware(policy CachePolicy) gin.HandlerFunc {
+	cacheControl := policy.String()
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffer := &bufferedWriter{ResponseWriter: original}
+		c.Writer = buffer
+		c.Next()
+		c.Writer = original
+
+		// 只有成功的响应才参与缓存协商，错误响应原样写出
+		if !buffer.Written() || buffer.Status() != http.StatusOK {
+			buffer.flushTo(original)
+			return
+		}
+
+		header := original.Header()
+		if header.Get("Cache-Control") == "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+		if header.Get("ETag") == "" {
+			header.Set("ETag", bodyETag(buffer.body.Bytes()))
+		}
+
+		if notModified(c.Request, header) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		buffer.flushTo(original)
+	}
+}
+
+// bodyETag returns a strong entity tag derived from the response bytes.
+// Response bodies are deterministic JSON, so equal bodies mean equal tags.
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified evaluates the request's cache validators against the response
+// headers, following the precedence of RFC 9110 §13.2.2.
+func notModified(req *http.Request, header http.Header) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagListMatches(ifNoneMatch, header.Get("ETag"))
+	}
+
+	ifModifiedSince, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(ifModifiedSince)
+}
+
+// etagListMatches reports whether any tag in an If-None-Match list matches
+// etag under weak comparison. Our tags never contain commas, so splitting
+// the list on them is sufficient.
+func etagListMatches(list, etag string) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds back the status and body written by the handler until
+// CacheMiddleware has decided whether to send them.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if w.status == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.status != 0
+}
+
+// flushTo writes the buffered status and body to dst.
+func (w *bufferedWriter) flushTo(dst gin.ResponseWriter) {
+	if !w.Written() {
+		return
+	}
+	dst.WriteHeader(w.status)
+	dst.WriteHeaderNow()
+	_, _ = dst.Write(w.body.Bytes())
+}
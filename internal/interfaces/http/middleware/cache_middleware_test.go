@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// ─── Helper ───────────────────────────────────────────────────────────────────
+
+var testLastModified = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func setupCacheRouter(policy CachePolicy) *gin.Engine {
+	r := gin.New()
+	r.Use(CacheMiddleware(policy))
+	r.GET("/body", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "kirk"})
+	})
+	r.GET("/versioned", func(c *gin.Context) {
+		SetETag(c, 42, 3)
+		SetLastModified(c, testLastModified)
+		c.JSON(http.StatusOK, gin.H{"name": "kirk"})
+	})
+	r.GET("/override", func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, gin.H{"name": "kirk"})
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+	return r
+}
+
+func performGet(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// ─── Tests ────────────────────────────────────────────────────────────────────
+
+func TestCachePolicy_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy CachePolicy
+		want   string
+	}{
+		{"private revalidate", CachePrivateRevalidate, "private, no-cache"},
+		{"no store", CacheNoStore, "no-store"},
+		{"public", CachePolicy{Public: true, MaxAge: time.Minute}, "public, max-age=60"},
+		{"shared max age", CachePolicy{Public: true, MaxAge: time.Minute, SharedMaxAge: 5 * time.Minute}, "public, max-age=60, s-maxage=300"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.String())
+		})
+	}
+}
+
+func TestCacheMiddleware_SetsHeaders(t *testing.T) {
+	router := setupCacheRouter(CachePolicy{Public: true, MaxAge: time.Minute})
+
+	w := performGet(router, "/body", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{"name":"kirk"}`, w.Body.String())
+}
+
+func TestCacheMiddleware_BodyETagIsStable(t *testing.T) {
+	router := setupCacheRouter(CachePrivateRevalidate)
+
+	first := performGet(router, "/body", nil)
+	second := performGet(router, "/body", nil)
+
+	assert.Equal(t, first.Header().Get("ETag"), second.Header().Get("ETag"))
+}
+
+func TestCacheMiddleware_IfNoneMatch(t *testing.T) {
+	router := setupCacheRouter(CachePrivateRevalidate)
+	etag := performGet(router, "/body", nil).Header().Get("ETag")
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{"matching tag", etag, http.StatusNotModified},
+		{"weak form of tag", "W/" + etag, http.StatusNotModified},
+		{"tag in list", `"other", ` + etag, http.StatusNotModified},
+		{"wildcard", "*", http.StatusNotModified},
+		{"stale tag", `"other"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performGet(router, "/body", map[string]string{"If-None-Match": tt.ifNoneMatch})
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+			if tt.wantStatus == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCacheMiddleware_KeepsHandlerETag(t *testing.T) {
+	router := setupCacheRouter(CachePrivateRevalidate)
+
+	w := performGet(router, "/versioned", map[string]string{"If-None-Match": `"42-3"`})
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"42-3"`, w.Header().Get("ETag"))
+}
+
+func TestCacheMiddleware_IfModifiedSince(t *testing.T) {
+	router := setupCacheRouter(CachePrivateRevalidate)
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"not modified since", map[string]string{"If-Modified-Since": testLastModified.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": testLastModified.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusOK},
+		{"malformed date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+		// If-None-Match 存在时忽略 If-Modified-Since
+		{"If-None-Match takes precedence", map[string]string{
+			"If-None-Match":     `"2"`,
+			"If-Modified-Since": testLastModified.Format(http.TimeFormat),
+		}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performGet(router, "/versioned", tt.headers)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestCacheMiddleware_HandlerCacheControlWins(t *testing.T) {
+	router := setupCacheRouter(CachePolicy{Public: true, MaxAge: time.Minute})
+
+	w := performGet(router, "/override", nil)
+
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
+
+func TestCacheMiddleware_ErrorResponsesPassThrough(t *testing.T) {
+	router := setupCacheRouter(CachePolicy{Public: true, MaxAge: time.Minute})
+
+	w := performGet(router, "/missing", map[string]string{"If-None-Match": "*"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), "not found")
+}
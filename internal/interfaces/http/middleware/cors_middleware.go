@@ -17,7 +17,9 @@ func CORSMiddleware() gin.HandlerFunc {
 	// handles echoing the origin properly when AllowAllOrigins is true.
 	// Actually, if AllowAllOrigins is true and AllowCredentials is true, gin-contrib/cors
 	// specifically mirrors the exact origin to satisfy browsers.
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept", "X-Requested-With", "X-CSRF-Token"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept", "X-Requested-With", "X-CSRF-Token", "If-Match", "If-None-Match", "If-Modified-Since"}
+	// 浏览器默认不向脚本暴露 ETag，客户端需要读取它才能在修改时携带 If-Match
+	config.ExposeHeaders = []string{"ETag"}
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
 	config.MaxAge = 12 * time.Hour
 
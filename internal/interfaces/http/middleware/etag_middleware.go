@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity/response"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+)
+
+// FormatETag returns the strong entity tag of a version of the user with id,
+// e.g. "42-3". The ID keeps the tags of different users distinct, so a tag
+// cached for one account can never match another account's resource.
+func FormatETag(id, version int64) string {
+	return `"` + strconv.FormatInt(id, 10) + "-" + strconv.FormatInt(version, 10) + `"`
+}
+
+// SetETag sets the ETag response header to the tag of the user's version.
+// The tagged responses depend on who is authenticated, so it also adds
+// Vary: Authorization for caches that key on the request.
+func SetETag(c *gin.Context, id, version int64) {
+	c.Header("ETag", FormatETag(id, version))
+	c.Writer.Header().Add("Vary", "Authorization")
+}
+
+// parseETag returns the user ID and version carried by a strong entity tag
+// produced by FormatETag.
+func parseETag(tag string) (id, version int64, ok bool) {
+	unquoted, ok := strings.CutPrefix(tag, `"`)
+	if !ok {
+		return 0, 0, false // 弱标签（W/"..."）按 RFC 9110 不参与 If-Match 的强比较
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, 0, false
+	}
+	rawID, rawVersion, ok := strings.Cut(unquoted, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, 0, false
+	}
+	version, err = strconv.ParseInt(rawVersion, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, 0, false
+	}
+	return id, version, true
+}
+
+// IfMatchMiddleware turns an If-Match request header into an expected version
+// on the request context (see usecase.WithExpectedVersion). The use case then
+// refuses to modify a resource whose version has moved on, so a client cannot
+// overwrite changes it has not seen.
+//
+// The header is optional. "*" only requires the resource to exist, which the
+// handler checks anyway. Tags that cannot match any version (weak, malformed,
+// a list of several, or the tag of another user than the authenticated one)
+// fail immediately with 412.
+func IfMatchMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := strings.TrimSpace(c.GetHeader("If-Match"))
+		if header == "" || header == "*" {
+			c.Next()
+			return
+		}
+
+		id, version, ok := parseETag(header)
+		if currentUserID, authenticated := GetUserIDFromContext(c); authenticated && currentUserID != id {
+			ok = false
+		}
+		if !ok {
+			c.JSON(http.StatusPreconditionFailed, response.NewErrorResponse(domainerrors.ErrPreconditionFailed.Message, domainerrors.ErrPreconditionFailed))
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(usecase.WithExpectedVersion(c.Request.Context(), version))
+		c.Next()
+	}
+}
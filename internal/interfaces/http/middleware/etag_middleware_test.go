@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+)
+
+// ─── Helper ───────────────────────────────────────────────────────────────────
+
+// setupIfMatchRouter echoes the expected version seen by the handler, or
+// "none". Requests are authenticated as user 42.
+func setupIfMatchRouter() *gin.Engine {
+	r := gin.New()
+	r.PATCH("/resource", func(c *gin.Context) {
+		c.Set(ContextKeyUserID, int64(42))
+		c.Next()
+	}, IfMatchMiddleware(), func(c *gin.Context) {
+		version, ok := usecase.ExpectedVersion(c.Request.Context())
+		if !ok {
+			c.String(http.StatusOK, "none")
+			return
+		}
+		c.String(http.StatusOK, strconv.FormatInt(version, 10))
+	})
+	return r
+}
+
+func performIfMatch(router *gin.Engine, ifMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/resource", nil)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// ─── Tests ────────────────────────────────────────────────────────────────────
+
+func TestIfMatch_NoHeader(t *testing.T) {
+	w := performIfMatch(setupIfMatchRouter(), "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "none", w.Body.String())
+}
+
+func TestIfMatch_Wildcard(t *testing.T) {
+	w := performIfMatch(setupIfMatchRouter(), "*")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "none", w.Body.String())
+}
+
+func TestIfMatch_StrongTagSetsExpectedVersion(t *testing.T) {
+	w := performIfMatch(setupIfMatchRouter(), FormatETag(42, 7))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "7", w.Body.String())
+}
+
+func TestIfMatch_UnusableTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+	}{
+		{"weak tag", `W/"42-7"`},
+		{"unquoted", `42-7`},
+		{"not a version", `"42-abc"`},
+		{"zero version", `"42-0"`},
+		{"version without user", `"7"`},
+		{"another user's tag", `"43-7"`},
+		{"list of tags", `"42-7", "42-8"`},
+	}
+
+	router := setupIfMatchRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performIfMatch(router, tt.ifMatch)
+
+			assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+			assert.Contains(t, w.Body.String(), "PRECONDITION_FAILED")
+		})
+	}
+}
+
+func TestSetETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	SetETag(c, 42, 12)
+
+	assert.Equal(t, `"42-12"`, w.Header().Get("ETag"))
+	assert.Equal(t, "Authorization", w.Header().Get("Vary"), "the tag depends on the authenticated user")
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+)
+
+// ReadPreferenceMiddleware sends every read of a request that can change
+// state (anything but GET, HEAD and OPTIONS) to the primary database.
+//
+// Such requests typically read a row and then write it back (version checks,
+// uniqueness checks), so reading a lagging replica would fail them spuriously
+// or act on stale data. Safe requests keep reading from replicas.
+func ReadPreferenceMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			c.Request = c.Request.WithContext(repository.WithPrimary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
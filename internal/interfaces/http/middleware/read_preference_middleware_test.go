@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+)
+
+func TestReadPreferenceMiddleware(t *testing.T) {
+	tests := []struct {
+		method      string
+		wantPrimary bool
+	}{
+		{http.MethodGet, false},
+		{http.MethodHead, false},
+		{http.MethodOptions, false},
+		{http.MethodPost, true},
+		{http.MethodPut, true},
+		{http.MethodPatch, true},
+		{http.MethodDelete, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			var usesPrimary bool
+			r := gin.New()
+			r.Use(ReadPreferenceMiddleware())
+			r.Handle(tt.method, "/resource", func(c *gin.Context) {
+				usesPrimary = repository.UsesPrimary(c.Request.Context())
+				c.Status(http.StatusNoContent)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, "/resource", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Equal(t, tt.wantPrimary, usesPrimary)
+		})
+	}
+}
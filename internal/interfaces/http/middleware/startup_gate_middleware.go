@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity/response"
+)
+
+// startupRetryAfterSeconds is the Retry-After sent while the application is starting.
+const startupRetryAfterSeconds = "5"
+
+// StartupGateMiddleware answers 503 Service Unavailable, with a Retry-After
+// header, to every request until started reports true, i.e. until the
+// database is connected. Requests for the given paths, or below them, are
+// let through so that probes and metrics work during startup; "/" only
+// matches the root itself.
+func StartupGateMiddleware(started func() bool, paths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if started() || isStartupPath(c.Request.URL.Path, paths) {
+			c.Next()
+			return
+		}
+		c.Header("Retry-After", startupRetryAfterSeconds)
+		c.JSON(http.StatusServiceUnavailable, response.NewErrorResponse(
+			"Service is starting. Please try again later.", errors.New("service starting"),
+		))
+		c.Abort()
+	}
+}
+
+func isStartupPath(path string, paths []string) bool {
+	for _, p := range paths {
+		if path == p || (p != "/" && strings.HasPrefix(path, p+"/")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartupGateMiddleware(t *testing.T) {
+	started := false
+	r := gin.New()
+	r.Use(StartupGateMiddleware(func() bool { return started }, "/", "/health"))
+	for _, path := range []string{"/", "/health", "/health/ready", "/healthz", "/api/v1/users"} {
+		r.GET(path, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	}
+
+	tests := []struct {
+		path        string
+		wantBlocked bool
+	}{
+		{"/", false},
+		{"/health", false},
+		{"/health/ready", false},
+		{"/healthz", true},
+		{"/api/v1/users", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			started = false
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			r.ServeHTTP(w, req)
+			if tt.wantBlocked {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				assert.Equal(t, "5", w.Header().Get("Retry-After"))
+			} else {
+				assert.Equal(t, http.StatusNoContent, w.Code)
+			}
+
+			started = true
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusNoContent, w.Code, "everything is served once started")
+		})
+	}
+}
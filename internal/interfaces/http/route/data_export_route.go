@@ -0,0 +1,19 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/controller"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+)
+
+// registerDataExportRoutes registers personal-data export endpoints.
+func (r *Router) registerDataExportRoutes(group *gin.RouterGroup, ctrl *controller.DataExportController) {
+	// 申请导出当前用户的全部数据，归档在后台生成
+	users := group.Group("/users")
+	users.Use(middleware.JWTAuthMiddleware(r.authenticator))
+	users.POST("/me/export", ctrl.RequestExport)
+
+	// 下载链接自带签名和有效期，无需登录即可使用（例如从邮件中直接打开）
+	group.GET("/exports/:token", ctrl.DownloadExport)
+}
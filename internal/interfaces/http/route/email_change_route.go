@@ -0,0 +1,21 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/controller"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+)
+
+// registerEmailChangeRoutes registers the re-verified email change endpoints.
+func (r *Router) registerEmailChangeRoutes(group *gin.RouterGroup, ctrl *controller.EmailChangeController) {
+	// 申请修改邮箱：向新邮箱发送确认链接，向旧邮箱发送撤销链接
+	users := group.Group("/users")
+	users.Use(middleware.JWTAuthMiddleware(r.authenticator))
+	users.POST("/me/email", ctrl.RequestEmailChange)
+
+	// 确认与撤销凭邮件中的签名令牌完成，无需登录（旧邮箱的主人可能已无法登录）
+	changes := group.Group("/email-changes")
+	changes.POST("/confirm", ctrl.ConfirmEmailChange)
+	changes.POST("/revert", ctrl.RevertEmailChange)
+}
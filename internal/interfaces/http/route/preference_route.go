@@ -0,0 +1,16 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/controller"
+	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/middleware"
+)
+
+// registerPreferenceRoutes registers the current user's preference endpoints.
+func (r *Router) registerPreferenceRoutes(group *gin.RouterGroup, ctrl *controller.PreferenceController) {
+	users := group.Group("/users")
+	users.Use(middleware.JWTAuthMiddleware(r.authenticator))
+	users.GET("/me/preferences", middleware.CacheMiddleware(middleware.CachePrivateRevalidate), ctrl.GetPreferences)
+	users.PATCH("/me/preferences", ctrl.UpdatePreferences)
+}
@@ -32,6 +32,9 @@ func (r *Router) Setup(
 	engine *gin.Engine,
 	authCtrl *controller.AuthController,
 	userCtrl *controller.UserController,
+	dataExportCtrl *controller.DataExportController,
+	emailChangeCtrl *controller.EmailChangeController,
+	preferenceCtrl *controller.PreferenceController,
 	infraCtrl *controller.InfraController,
 ) {
 	// Global middleware
@@ -49,4 +52,10 @@ func (r *Router) Setup(
 	api := engine.Group("/v1/api")
 	r.registerAuthRoutes(api, authCtrl)
 	r.registerUserRoutes(api, userCtrl)
+	r.registerDataExportRoutes(api, dataExportCtrl)
+	r.registerEmailChangeRoutes(api, emailChangeCtrl)
+	r.registerPreferenceRoutes(api, preferenceCtrl)
+
+	// Locally stored objects (avatars, ...) are served by the app itself
+	r.registerStorageRoutes(engine)
 }
@@ -0,0 +1,36 @@
+package route
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicStoragePrefixes are the top-level key prefixes whose objects may be
+// served publicly. Everything else (e.g. data export archives) stays private
+// and is only reachable through its own authenticated endpoint.
+var publicStoragePrefixes = []string{"avatars"}
+
+// registerStorageRoutes serves files written by the local storage driver.
+// The mount path is taken from STORAGE_PUBLIC_URL so that the URLs handed
+// out by the storage backend resolve to this handler. Other drivers serve
+// objects themselves and register nothing here.
+func (r *Router) registerStorageRoutes(engine *gin.Engine) {
+	if r.config.StorageDriver != "local" {
+		return
+	}
+	u, err := url.Parse(r.config.StoragePublicURL)
+	if err != nil {
+		return
+	}
+	mount := strings.TrimRight(u.Path, "/")
+	if mount == "" {
+		return
+	}
+	// gin.Static 不会列出目录内容
+	for _, prefix := range publicStoragePrefixes {
+		engine.Static(mount+"/"+prefix, filepath.Join(r.config.StorageLocalDir, prefix))
+	}
+}
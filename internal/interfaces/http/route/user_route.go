@@ -1,6 +1,8 @@
 package route
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/controller"
@@ -8,17 +10,55 @@ import (
 	"github.com/kirklin/boot-backend-go-clean/internal/interfaces/http/utils"
 )
 
+// avatarMultipartOverhead is the slack allowed on top of the avatar size limit
+// for multipart boundaries and part headers.
+const avatarMultipartOverhead = 64 << 10
+
+// publicProfileCache lets nginx and browsers serve a public profile for a
+// minute before revalidating; edits become visible within that window.
+var publicProfileCache = middleware.CachePolicy{Public: true, MaxAge: time.Minute}
+
 // registerUserRoutes registers user endpoints.
 func (r *Router) registerUserRoutes(group *gin.RouterGroup, ctrl *controller.UserController) {
+	// 公开资料无需登录，响应与调用者无关，可由 nginx 等共享缓存存储
+	profiles := group.Group("/profiles")
+	profiles.GET("/:id", middleware.CacheMiddleware(publicProfileCache), ctrl.GetPublicProfile)
+
 	users := group.Group("/users")
 	users.Use(middleware.JWTAuthMiddleware(r.authenticator))
 	{
-		users.GET("/:id", ctrl.GetUser)
+		// 以下查询只对当前用户私有缓存，客户端每次用 If-None-Match 重新验证，未变化时返回 304
+		private := middleware.CacheMiddleware(middleware.CachePrivateRevalidate)
+
+		// 用户列表（支持过滤、排序与游标分页）
+		users.GET("", private, ctrl.ListUsers)
+
+		users.GET("/:id", private, ctrl.GetUser)
 
 		// 获取当前用户信息
-		users.GET("/current", ctrl.GetCurrentUser)
+		users.GET("/current", private, ctrl.GetCurrentUser)
+
+		// 以下修改操作均支持 If-Match：版本号与 GET 返回的 ETag 不一致时返回 412
+
+		// 修改当前用户的用户名（有冷却期，旧用户名会保留一段时间）
+		users.PUT("/me/username", middleware.IfMatchMiddleware(), ctrl.ChangeUsername)
+
+		// 注销当前账号（需密码确认），冷静期内重新登录可恢复
+		users.DELETE("/me", middleware.IfMatchMiddleware(), ctrl.DeleteCurrentUser)
+
+		// 局部更新用户资料（JSON merge-patch），仅允许用户本人
+		users.PATCH("/:id",
+			middleware.EnsureSelfMiddleware(utils.GetTargetUserIDFromParam),
+			middleware.IfMatchMiddleware(),
+			ctrl.UpdateProfile,
+		)
 
-		// 更新用户资料，仅允许用户本人
-		users.PUT("/:id", middleware.EnsureSelfMiddleware(utils.GetTargetUserIDFromParam), ctrl.UpdateUser)
+		// 上传头像（multipart 表单字段 avatar），仅允许用户本人；请求体上限额外预留 multipart 封装的开销
+		users.POST("/:id/avatar",
+			middleware.EnsureSelfMiddleware(utils.GetTargetUserIDFromParam),
+			middleware.IfMatchMiddleware(),
+			middleware.BodyLimitMiddleware(r.config.AvatarMaxUploadBytes()+avatarMultipartOverhead),
+			ctrl.UploadAvatar,
+		)
 	}
 }
@@ -0,0 +1,20 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+// NewAccountPurgeJob purges accounts whose deletion grace period has expired.
+func NewAccountPurgeJob(userUseCase usecase.UserUseCase, interval time.Duration) *Periodic {
+	return NewPeriodic("account-purge", interval, func(ctx context.Context) error {
+		purged, err := userUseCase.PurgeDeletedAccounts(ctx)
+		if purged > 0 {
+			logger.FromContext(ctx).Infof("purged %d deleted accounts", purged)
+		}
+		return err
+	})
+}
@@ -0,0 +1,26 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+// NewDataExportJob builds queued personal-data exports and removes expired ones.
+func NewDataExportJob(dataExportUseCase usecase.DataExportUseCase, interval time.Duration) *Periodic {
+	return NewPeriodic("data-export", interval, func(ctx context.Context) error {
+		log := logger.FromContext(ctx)
+		ready, processErr := dataExportUseCase.ProcessPendingExports(ctx)
+		if ready > 0 {
+			log.Infof("prepared %d data exports", ready)
+		}
+		purged, purgeErr := dataExportUseCase.PurgeExpiredExports(ctx)
+		if purged > 0 {
+			log.Infof("purged %d expired data exports", purged)
+		}
+		return errors.Join(processErr, purgeErr)
+	})
+}
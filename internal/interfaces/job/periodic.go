@@ -0,0 +1,59 @@
+// Package job contains background jobs that run alongside the HTTP server.
+// Like HTTP controllers, jobs are a delivery mechanism: they only trigger
+// use cases and never contain business rules themselves.
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+// Periodic runs a task at a fixed interval until its context is canceled.
+type Periodic struct {
+	name     string
+	interval time.Duration
+	task     func(ctx context.Context) error
+}
+
+// NewPeriodic creates a job that calls task every interval.
+func NewPeriodic(name string, interval time.Duration, task func(ctx context.Context) error) *Periodic {
+	return &Periodic{name: name, interval: interval, task: task}
+}
+
+// Run blocks until ctx is canceled. The task runs once immediately and then
+// on every tick; a failing or panicking run is logged and does not stop the job.
+func (p *Periodic) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Periodic) runOnce(ctx context.Context) {
+	log := logger.FromContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("job %s panicked: %v", p.name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := p.task(ctx); err != nil {
+		log.Errorf("job %s failed after %s: %v", p.name, time.Since(start), err)
+	}
+}
+
+// String implements fmt.Stringer.
+func (p *Periodic) String() string {
+	return fmt.Sprintf("%s (every %s)", p.name, p.interval)
+}
@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodic_RunsImmediatelyAndOnEveryTick(t *testing.T) {
+	var runs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPeriodic("test", 10*time.Millisecond, func(context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was canceled")
+	}
+}
+
+func TestPeriodic_SurvivesErrorsAndPanics(t *testing.T) {
+	var runs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := NewPeriodic("flaky", 5*time.Millisecond, func(context.Context) error {
+		switch runs.Add(1) {
+		case 1:
+			return errors.New("transient failure")
+		case 2:
+			panic("boom")
+		}
+		return nil
+	})
+
+	go p.Run(ctx)
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+}
@@ -2,8 +2,12 @@ package mock
 
 import (
 	"context"
+	"sync"
 
 	"github.com/stretchr/testify/mock"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 )
 
 // Convenience matchers for common test patterns.
@@ -19,11 +23,69 @@ var (
 //
 // The callback's error is correctly propagated: if fn returns
 // domainerrors.ErrUsernameExists, that's exactly what WithTx returns.
+// Like a real TxManager it runs repository.AfterCommit functions only when
+// fn succeeds, so tests can assert that events are dropped on failure, and
+// honours repository.WithRetry: a callback failing with ErrTxConflict runs
+// again (without waiting). Isolation and read-only options have nothing to
+// act on; assert them with TxOptionsMatching.
 func NewPassthroughTxManager() *MockTxManager {
 	m := &MockTxManager{}
-	m.On("WithTx", mock.Anything, mock.Anything).
-		Return(func(ctx context.Context, fn func(context.Context) error) error {
-			return fn(ctx)
+	m.On("WithTx", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error, opts ...repository.TxOption) error {
+			nested := ctx.Value(passthroughTxKey{}) != nil
+			txCtx := context.WithValue(ctx, passthroughTxKey{}, true)
+			run := func() error {
+				hooksCtx, end := repository.WithCommitHooks(txCtx)
+				err := fn(hooksCtx)
+				end(err == nil)
+				return err
+			}
+			if nested {
+				return run() // 嵌套调用加入外层事务，忽略自己的选项
+			}
+
+			o := repository.ApplyTxOptions(opts...)
+			o.Backoff = 0
+			return o.RunWithRetry(ctx, run)
 		})
 	return m
 }
+
+// passthroughTxKey marks a context as being inside a passthrough transaction.
+type passthroughTxKey struct{}
+
+// TxOptionsMatching matches the options argument of a WithTx call whose
+// options satisfy fn:
+//
+//	txManager.AssertCalled(t, "WithTx", mock.Anything, mock.Anything,
+//	    testmock.TxOptionsMatching(func(o repository.TxOptions) bool { return o.ReadOnly }))
+func TxOptionsMatching(fn func(repository.TxOptions) bool) any {
+	return mock.MatchedBy(func(opts []repository.TxOption) bool {
+		return fn(repository.ApplyTxOptions(opts...))
+	})
+}
+
+// EventRecorder is a gateway.EventPublisher that records the events it would
+// deliver. Like the real event bus it delivers through repository.AfterCommit,
+// so events published inside a failed WithTx are never recorded.
+type EventRecorder struct {
+	mu     sync.Mutex
+	events []event.Event
+}
+
+// Publish implements gateway.EventPublisher.
+func (r *EventRecorder) Publish(ctx context.Context, events ...event.Event) error {
+	repository.AfterCommit(ctx, func(context.Context) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = append(r.events, events...)
+	})
+	return nil
+}
+
+// Events returns the delivered events in order.
+func (r *EventRecorder) Events() []event.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]event.Event(nil), r.events...)
+}
@@ -5,6 +5,7 @@ package mock
 import (
 	context "context"
 
+	repository "github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -21,17 +22,17 @@ func (_m *MockTxManager) EXPECT() *MockTxManager_Expecter {
 	return &MockTxManager_Expecter{mock: &_m.Mock}
 }
 
-// WithTx provides a mock function with given fields: ctx, fn
-func (_m *MockTxManager) WithTx(ctx context.Context, fn func(context.Context) error) error {
-	ret := _m.Called(ctx, fn)
+// WithTx provides a mock function with given fields: ctx, fn, opts
+func (_m *MockTxManager) WithTx(ctx context.Context, fn func(context.Context) error, opts ...repository.TxOption) error {
+	ret := _m.Called(ctx, fn, opts)
 
 	if len(ret) == 0 {
 		panic("no return value specified for WithTx")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
-		r0 = rf(ctx, fn)
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error, ...repository.TxOption) error); ok {
+		r0 = rf(ctx, fn, opts...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -47,13 +48,14 @@ type MockTxManager_WithTx_Call struct {
 // WithTx is a helper method to define mock.On call
 //   - ctx context.Context
 //   - fn func(context.Context) error
-func (_e *MockTxManager_Expecter) WithTx(ctx interface{}, fn interface{}) *MockTxManager_WithTx_Call {
-	return &MockTxManager_WithTx_Call{Call: _e.mock.On("WithTx", ctx, fn)}
+//   - opts ...repository.TxOption
+func (_e *MockTxManager_Expecter) WithTx(ctx interface{}, fn interface{}, opts interface{}) *MockTxManager_WithTx_Call {
+	return &MockTxManager_WithTx_Call{Call: _e.mock.On("WithTx", ctx, fn, opts)}
 }
 
-func (_c *MockTxManager_WithTx_Call) Run(run func(ctx context.Context, fn func(context.Context) error)) *MockTxManager_WithTx_Call {
+func (_c *MockTxManager_WithTx_Call) Run(run func(ctx context.Context, fn func(context.Context) error, opts ...repository.TxOption)) *MockTxManager_WithTx_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(func(context.Context) error))
+		run(args[0].(context.Context), args[1].(func(context.Context) error), args[2].([]repository.TxOption)...)
 	})
 	return _c
 }
@@ -63,7 +65,7 @@ func (_c *MockTxManager_WithTx_Call) Return(_a0 error) *MockTxManager_WithTx_Cal
 	return _c
 }
 
-func (_c *MockTxManager_WithTx_Call) RunAndReturn(run func(context.Context, func(context.Context) error) error) *MockTxManager_WithTx_Call {
+func (_c *MockTxManager_WithTx_Call) RunAndReturn(run func(context.Context, func(context.Context) error, ...repository.TxOption) error) *MockTxManager_WithTx_Call {
 	_c.Call.Return(run)
 	return _c
 }
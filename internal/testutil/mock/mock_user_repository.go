@@ -5,7 +5,10 @@ package mock
 import (
 	context "context"
 
+	time "time"
+
 	entity "github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	repository "github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -22,6 +25,110 @@ func (_m *MockUserRepository) EXPECT() *MockUserRepository_Expecter {
 	return &MockUserRepository_Expecter{mock: &_m.Mock}
 }
 
+// Anonymize provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) Anonymize(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Anonymize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_Anonymize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Anonymize'
+type MockUserRepository_Anonymize_Call struct {
+	*mock.Call
+}
+
+// Anonymize is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockUserRepository_Expecter) Anonymize(ctx interface{}, id interface{}) *MockUserRepository_Anonymize_Call {
+	return &MockUserRepository_Anonymize_Call{Call: _e.mock.On("Anonymize", ctx, id)}
+}
+
+func (_c *MockUserRepository_Anonymize_Call) Run(run func(ctx context.Context, id int64)) *MockUserRepository_Anonymize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_Anonymize_Call) Return(_a0 error) *MockUserRepository_Anonymize_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_Anonymize_Call) RunAndReturn(run func(context.Context, int64) error) *MockUserRepository_Anonymize_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Count provides a mock function with given fields: ctx, filter
+func (_m *MockUserRepository) Count(ctx context.Context, filter repository.UserFilter) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UserFilter) (int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UserFilter) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.UserFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type MockUserRepository_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.UserFilter
+func (_e *MockUserRepository_Expecter) Count(ctx interface{}, filter interface{}) *MockUserRepository_Count_Call {
+	return &MockUserRepository_Count_Call{Call: _e.mock.On("Count", ctx, filter)}
+}
+
+func (_c *MockUserRepository_Count_Call) Run(run func(ctx context.Context, filter repository.UserFilter)) *MockUserRepository_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.UserFilter))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_Count_Call) Return(_a0 int64, _a1 error) *MockUserRepository_Count_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_Count_Call) RunAndReturn(run func(context.Context, repository.UserFilter) (int64, error)) *MockUserRepository_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, user
 func (_m *MockUserRepository) Create(ctx context.Context, user *entity.User) error {
 	ret := _m.Called(ctx, user)
@@ -246,6 +353,279 @@ func (_c *MockUserRepository_FindByUsername_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// FindDeletedByUsername provides a mock function with given fields: ctx, username
+func (_m *MockUserRepository) FindDeletedByUsername(ctx context.Context, username string) (*entity.User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDeletedByUsername")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_FindDeletedByUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDeletedByUsername'
+type MockUserRepository_FindDeletedByUsername_Call struct {
+	*mock.Call
+}
+
+// FindDeletedByUsername is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *MockUserRepository_Expecter) FindDeletedByUsername(ctx interface{}, username interface{}) *MockUserRepository_FindDeletedByUsername_Call {
+	return &MockUserRepository_FindDeletedByUsername_Call{Call: _e.mock.On("FindDeletedByUsername", ctx, username)}
+}
+
+func (_c *MockUserRepository_FindDeletedByUsername_Call) Run(run func(ctx context.Context, username string)) *MockUserRepository_FindDeletedByUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_FindDeletedByUsername_Call) Return(_a0 *entity.User, _a1 error) *MockUserRepository_FindDeletedByUsername_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_FindDeletedByUsername_Call) RunAndReturn(run func(context.Context, string) (*entity.User, error)) *MockUserRepository_FindDeletedByUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HardDelete provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) HardDelete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HardDelete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_HardDelete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HardDelete'
+type MockUserRepository_HardDelete_Call struct {
+	*mock.Call
+}
+
+// HardDelete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockUserRepository_Expecter) HardDelete(ctx interface{}, id interface{}) *MockUserRepository_HardDelete_Call {
+	return &MockUserRepository_HardDelete_Call{Call: _e.mock.On("HardDelete", ctx, id)}
+}
+
+func (_c *MockUserRepository_HardDelete_Call) Run(run func(ctx context.Context, id int64)) *MockUserRepository_HardDelete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_HardDelete_Call) Return(_a0 error) *MockUserRepository_HardDelete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_HardDelete_Call) RunAndReturn(run func(context.Context, int64) error) *MockUserRepository_HardDelete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, filter, opts
+func (_m *MockUserRepository) List(ctx context.Context, filter repository.UserFilter, opts repository.ListOptions) ([]*entity.User, error) {
+	ret := _m.Called(ctx, filter, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UserFilter, repository.ListOptions) ([]*entity.User, error)); ok {
+		return rf(ctx, filter, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UserFilter, repository.ListOptions) []*entity.User); ok {
+		r0 = rf(ctx, filter, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.UserFilter, repository.ListOptions) error); ok {
+		r1 = rf(ctx, filter, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockUserRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.UserFilter
+//   - opts repository.ListOptions
+func (_e *MockUserRepository_Expecter) List(ctx interface{}, filter interface{}, opts interface{}) *MockUserRepository_List_Call {
+	return &MockUserRepository_List_Call{Call: _e.mock.On("List", ctx, filter, opts)}
+}
+
+func (_c *MockUserRepository_List_Call) Run(run func(ctx context.Context, filter repository.UserFilter, opts repository.ListOptions)) *MockUserRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.UserFilter), args[2].(repository.ListOptions))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_List_Call) Return(_a0 []*entity.User, _a1 error) *MockUserRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_List_Call) RunAndReturn(run func(context.Context, repository.UserFilter, repository.ListOptions) ([]*entity.User, error)) *MockUserRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeletedBefore provides a mock function with given fields: ctx, before, limit
+func (_m *MockUserRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entity.User, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeletedBefore")
+	}
+
+	var r0 []*entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*entity.User, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*entity.User); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_ListDeletedBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeletedBefore'
+type MockUserRepository_ListDeletedBefore_Call struct {
+	*mock.Call
+}
+
+// ListDeletedBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - limit int
+func (_e *MockUserRepository_Expecter) ListDeletedBefore(ctx interface{}, before interface{}, limit interface{}) *MockUserRepository_ListDeletedBefore_Call {
+	return &MockUserRepository_ListDeletedBefore_Call{Call: _e.mock.On("ListDeletedBefore", ctx, before, limit)}
+}
+
+func (_c *MockUserRepository_ListDeletedBefore_Call) Run(run func(ctx context.Context, before time.Time, limit int)) *MockUserRepository_ListDeletedBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ListDeletedBefore_Call) Return(_a0 []*entity.User, _a1 error) *MockUserRepository_ListDeletedBefore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_ListDeletedBefore_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]*entity.User, error)) *MockUserRepository_ListDeletedBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) Restore(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type MockUserRepository_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockUserRepository_Expecter) Restore(ctx interface{}, id interface{}) *MockUserRepository_Restore_Call {
+	return &MockUserRepository_Restore_Call{Call: _e.mock.On("Restore", ctx, id)}
+}
+
+func (_c *MockUserRepository_Restore_Call) Run(run func(ctx context.Context, id int64)) *MockUserRepository_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_Restore_Call) Return(_a0 error) *MockUserRepository_Restore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_Restore_Call) RunAndReturn(run func(context.Context, int64) error) *MockUserRepository_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SoftDelete provides a mock function with given fields: ctx, id
 func (_m *MockUserRepository) SoftDelete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -340,6 +720,68 @@ func (_c *MockUserRepository_Update_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// UpdateFields provides a mock function with given fields: ctx, user, fields
+func (_m *MockUserRepository) UpdateFields(ctx context.Context, user *entity.User, fields ...repository.UserField) error {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, user)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFields")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User, ...repository.UserField) error); ok {
+		r0 = rf(ctx, user, fields...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_UpdateFields_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateFields'
+type MockUserRepository_UpdateFields_Call struct {
+	*mock.Call
+}
+
+// UpdateFields is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *entity.User
+//   - fields ...repository.UserField
+func (_e *MockUserRepository_Expecter) UpdateFields(ctx interface{}, user interface{}, fields ...interface{}) *MockUserRepository_UpdateFields_Call {
+	return &MockUserRepository_UpdateFields_Call{Call: _e.mock.On("UpdateFields",
+		append([]interface{}{ctx, user}, fields...)...)}
+}
+
+func (_c *MockUserRepository_UpdateFields_Call) Run(run func(ctx context.Context, user *entity.User, fields ...repository.UserField)) *MockUserRepository_UpdateFields_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]repository.UserField, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(repository.UserField)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*entity.User), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateFields_Call) Return(_a0 error) *MockUserRepository_UpdateFields_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateFields_Call) RunAndReturn(run func(context.Context, *entity.User, ...repository.UserField) error) *MockUserRepository_UpdateFields_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockUserRepository creates a new instance of MockUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUserRepository(t interface {
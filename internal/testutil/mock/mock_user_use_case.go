@@ -5,6 +5,8 @@ package mock
 import (
 	context "context"
 
+	io "io"
+
 	entity "github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -22,6 +24,114 @@ func (_m *MockUserUseCase) EXPECT() *MockUserUseCase_Expecter {
 	return &MockUserUseCase_Expecter{mock: &_m.Mock}
 }
 
+// ChangeUsername provides a mock function with given fields: ctx, id, req
+func (_m *MockUserUseCase) ChangeUsername(ctx context.Context, id int64, req *entity.ChangeUsernameRequest) (*entity.User, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeUsername")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.ChangeUsernameRequest) (*entity.User, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.ChangeUsernameRequest) *entity.User); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *entity.ChangeUsernameRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserUseCase_ChangeUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeUsername'
+type MockUserUseCase_ChangeUsername_Call struct {
+	*mock.Call
+}
+
+// ChangeUsername is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - req *entity.ChangeUsernameRequest
+func (_e *MockUserUseCase_Expecter) ChangeUsername(ctx interface{}, id interface{}, req interface{}) *MockUserUseCase_ChangeUsername_Call {
+	return &MockUserUseCase_ChangeUsername_Call{Call: _e.mock.On("ChangeUsername", ctx, id, req)}
+}
+
+func (_c *MockUserUseCase_ChangeUsername_Call) Run(run func(ctx context.Context, id int64, req *entity.ChangeUsernameRequest)) *MockUserUseCase_ChangeUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(*entity.ChangeUsernameRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_ChangeUsername_Call) Return(_a0 *entity.User, _a1 error) *MockUserUseCase_ChangeUsername_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserUseCase_ChangeUsername_Call) RunAndReturn(run func(context.Context, int64, *entity.ChangeUsernameRequest) (*entity.User, error)) *MockUserUseCase_ChangeUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAccount provides a mock function with given fields: ctx, id, req
+func (_m *MockUserUseCase) DeleteAccount(ctx context.Context, id int64, req *entity.DeleteAccountRequest) error {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.DeleteAccountRequest) error); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserUseCase_DeleteAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccount'
+type MockUserUseCase_DeleteAccount_Call struct {
+	*mock.Call
+}
+
+// DeleteAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - req *entity.DeleteAccountRequest
+func (_e *MockUserUseCase_Expecter) DeleteAccount(ctx interface{}, id interface{}, req interface{}) *MockUserUseCase_DeleteAccount_Call {
+	return &MockUserUseCase_DeleteAccount_Call{Call: _e.mock.On("DeleteAccount", ctx, id, req)}
+}
+
+func (_c *MockUserUseCase_DeleteAccount_Call) Run(run func(ctx context.Context, id int64, req *entity.DeleteAccountRequest)) *MockUserUseCase_DeleteAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(*entity.DeleteAccountRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_DeleteAccount_Call) Return(_a0 error) *MockUserUseCase_DeleteAccount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserUseCase_DeleteAccount_Call) RunAndReturn(run func(context.Context, int64, *entity.DeleteAccountRequest) error) *MockUserUseCase_DeleteAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetUserByID provides a mock function with given fields: ctx, id
 func (_m *MockUserUseCase) GetUserByID(ctx context.Context, id int64) (*entity.User, error) {
 	ret := _m.Called(ctx, id)
@@ -81,96 +191,237 @@ func (_c *MockUserUseCase_GetUserByID_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
-// SoftDeleteUser provides a mock function with given fields: ctx, id
-func (_m *MockUserUseCase) SoftDeleteUser(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// ListUsers provides a mock function with given fields: ctx, req
+func (_m *MockUserUseCase) ListUsers(ctx context.Context, req *entity.ListUsersRequest) (*entity.ListUsersResponse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SoftDeleteUser")
+		panic("no return value specified for ListUsers")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	var r0 *entity.ListUsersResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ListUsersRequest) (*entity.ListUsersResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ListUsersRequest) *entity.ListUsersResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ListUsersResponse)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.ListUsersRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockUserUseCase_SoftDeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDeleteUser'
-type MockUserUseCase_SoftDeleteUser_Call struct {
+// MockUserUseCase_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
+type MockUserUseCase_ListUsers_Call struct {
 	*mock.Call
 }
 
-// SoftDeleteUser is a helper method to define mock.On call
+// ListUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *entity.ListUsersRequest
+func (_e *MockUserUseCase_Expecter) ListUsers(ctx interface{}, req interface{}) *MockUserUseCase_ListUsers_Call {
+	return &MockUserUseCase_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx, req)}
+}
+
+func (_c *MockUserUseCase_ListUsers_Call) Run(run func(ctx context.Context, req *entity.ListUsersRequest)) *MockUserUseCase_ListUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.ListUsersRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_ListUsers_Call) Return(_a0 *entity.ListUsersResponse, _a1 error) *MockUserUseCase_ListUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserUseCase_ListUsers_Call) RunAndReturn(run func(context.Context, *entity.ListUsersRequest) (*entity.ListUsersResponse, error)) *MockUserUseCase_ListUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeDeletedAccounts provides a mock function with given fields: ctx
+func (_m *MockUserUseCase) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedAccounts")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserUseCase_PurgeDeletedAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeletedAccounts'
+type MockUserUseCase_PurgeDeletedAccounts_Call struct {
+	*mock.Call
+}
+
+// PurgeDeletedAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockUserUseCase_Expecter) PurgeDeletedAccounts(ctx interface{}) *MockUserUseCase_PurgeDeletedAccounts_Call {
+	return &MockUserUseCase_PurgeDeletedAccounts_Call{Call: _e.mock.On("PurgeDeletedAccounts", ctx)}
+}
+
+func (_c *MockUserUseCase_PurgeDeletedAccounts_Call) Run(run func(ctx context.Context)) *MockUserUseCase_PurgeDeletedAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_PurgeDeletedAccounts_Call) Return(_a0 int, _a1 error) *MockUserUseCase_PurgeDeletedAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserUseCase_PurgeDeletedAccounts_Call) RunAndReturn(run func(context.Context) (int, error)) *MockUserUseCase_PurgeDeletedAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, id, req
+func (_m *MockUserUseCase) UpdateProfile(ctx context.Context, id int64, req *entity.UpdateProfileRequest) (*entity.User, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.UpdateProfileRequest) (*entity.User, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.UpdateProfileRequest) *entity.User); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *entity.UpdateProfileRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserUseCase_UpdateProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateProfile'
+type MockUserUseCase_UpdateProfile_Call struct {
+	*mock.Call
+}
+
+// UpdateProfile is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id int64
-func (_e *MockUserUseCase_Expecter) SoftDeleteUser(ctx interface{}, id interface{}) *MockUserUseCase_SoftDeleteUser_Call {
-	return &MockUserUseCase_SoftDeleteUser_Call{Call: _e.mock.On("SoftDeleteUser", ctx, id)}
+//   - req *entity.UpdateProfileRequest
+func (_e *MockUserUseCase_Expecter) UpdateProfile(ctx interface{}, id interface{}, req interface{}) *MockUserUseCase_UpdateProfile_Call {
+	return &MockUserUseCase_UpdateProfile_Call{Call: _e.mock.On("UpdateProfile", ctx, id, req)}
 }
 
-func (_c *MockUserUseCase_SoftDeleteUser_Call) Run(run func(ctx context.Context, id int64)) *MockUserUseCase_SoftDeleteUser_Call {
+func (_c *MockUserUseCase_UpdateProfile_Call) Run(run func(ctx context.Context, id int64, req *entity.UpdateProfileRequest)) *MockUserUseCase_UpdateProfile_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(*entity.UpdateProfileRequest))
 	})
 	return _c
 }
 
-func (_c *MockUserUseCase_SoftDeleteUser_Call) Return(_a0 error) *MockUserUseCase_SoftDeleteUser_Call {
-	_c.Call.Return(_a0)
+func (_c *MockUserUseCase_UpdateProfile_Call) Return(_a0 *entity.User, _a1 error) *MockUserUseCase_UpdateProfile_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockUserUseCase_SoftDeleteUser_Call) RunAndReturn(run func(context.Context, int64) error) *MockUserUseCase_SoftDeleteUser_Call {
+func (_c *MockUserUseCase_UpdateProfile_Call) RunAndReturn(run func(context.Context, int64, *entity.UpdateProfileRequest) (*entity.User, error)) *MockUserUseCase_UpdateProfile_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateUser provides a mock function with given fields: ctx, user
-func (_m *MockUserUseCase) UpdateUser(ctx context.Context, user *entity.User) error {
-	ret := _m.Called(ctx, user)
+// UploadAvatar provides a mock function with given fields: ctx, id, file
+func (_m *MockUserUseCase) UploadAvatar(ctx context.Context, id int64, file io.Reader) (*entity.AvatarResponse, error) {
+	ret := _m.Called(ctx, id, file)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateUser")
+		panic("no return value specified for UploadAvatar")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) error); ok {
-		r0 = rf(ctx, user)
+	var r0 *entity.AvatarResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, io.Reader) (*entity.AvatarResponse, error)); ok {
+		return rf(ctx, id, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, io.Reader) *entity.AvatarResponse); ok {
+		r0 = rf(ctx, id, file)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AvatarResponse)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int64, io.Reader) error); ok {
+		r1 = rf(ctx, id, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockUserUseCase_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
-type MockUserUseCase_UpdateUser_Call struct {
+// MockUserUseCase_UploadAvatar_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UploadAvatar'
+type MockUserUseCase_UploadAvatar_Call struct {
 	*mock.Call
 }
 
-// UpdateUser is a helper method to define mock.On call
+// UploadAvatar is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *entity.User
-func (_e *MockUserUseCase_Expecter) UpdateUser(ctx interface{}, user interface{}) *MockUserUseCase_UpdateUser_Call {
-	return &MockUserUseCase_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, user)}
+//   - id int64
+//   - file io.Reader
+func (_e *MockUserUseCase_Expecter) UploadAvatar(ctx interface{}, id interface{}, file interface{}) *MockUserUseCase_UploadAvatar_Call {
+	return &MockUserUseCase_UploadAvatar_Call{Call: _e.mock.On("UploadAvatar", ctx, id, file)}
 }
 
-func (_c *MockUserUseCase_UpdateUser_Call) Run(run func(ctx context.Context, user *entity.User)) *MockUserUseCase_UpdateUser_Call {
+func (_c *MockUserUseCase_UploadAvatar_Call) Run(run func(ctx context.Context, id int64, file io.Reader)) *MockUserUseCase_UploadAvatar_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*entity.User))
+		run(args[0].(context.Context), args[1].(int64), args[2].(io.Reader))
 	})
 	return _c
 }
 
-func (_c *MockUserUseCase_UpdateUser_Call) Return(_a0 error) *MockUserUseCase_UpdateUser_Call {
-	_c.Call.Return(_a0)
+func (_c *MockUserUseCase_UploadAvatar_Call) Return(_a0 *entity.AvatarResponse, _a1 error) *MockUserUseCase_UploadAvatar_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockUserUseCase_UpdateUser_Call) RunAndReturn(run func(context.Context, *entity.User) error) *MockUserUseCase_UpdateUser_Call {
+func (_c *MockUserUseCase_UploadAvatar_Call) RunAndReturn(run func(context.Context, int64, io.Reader) (*entity.AvatarResponse, error)) *MockUserUseCase_UploadAvatar_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -10,6 +10,7 @@ import (
 	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
 
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
@@ -17,18 +18,29 @@ import (
 )
 
 type authUseCase struct {
-	userRepo      repository.UserRepository
-	authenticator gateway.Authenticator
-	txManager     repository.TxManager
-	config        *configs.AppConfig
+	userRepo        repository.UserRepository
+	usernameHistory repository.UsernameHistoryRepository
+	authenticator   gateway.Authenticator
+	txManager       repository.TxManager
+	events          gateway.EventPublisher
+	config          *configs.AppConfig
 }
 
-func NewAuthUseCase(userRepo repository.UserRepository, authenticator gateway.Authenticator, txManager repository.TxManager, config *configs.AppConfig) usecase.AuthUseCase {
+func NewAuthUseCase(
+	userRepo repository.UserRepository,
+	usernameHistory repository.UsernameHistoryRepository,
+	authenticator gateway.Authenticator,
+	txManager repository.TxManager,
+	events gateway.EventPublisher,
+	config *configs.AppConfig,
+) usecase.AuthUseCase {
 	return &authUseCase{
-		userRepo:      userRepo,
-		authenticator: authenticator,
-		txManager:     txManager,
-		config:        config,
+		userRepo:        userRepo,
+		usernameHistory: usernameHistory,
+		authenticator:   authenticator,
+		txManager:       txManager,
+		events:          events,
+		config:          config,
 	}
 }
 
@@ -64,8 +76,17 @@ func (a *authUseCase) Register(ctx context.Context, req *entity.RegisterRequest)
 			return domainerrors.ErrUsernameExists
 		}
 
-		// Check if email already exists
-		existingEmail, err := a.userRepo.FindByEmail(txCtx, req.Email)
+		// 其他用户刚释放的用户名仍在保留期内，不能被注册
+		reserved, err := a.usernameHistory.IsReserved(txCtx, req.Username, time.Now().Add(-a.config.UsernameReservationPeriod()), 0)
+		if err != nil {
+			return domainerrors.ErrInternal.Wrap(err)
+		}
+		if reserved {
+			return domainerrors.ErrUsernameExists
+		}
+
+		// Check if email already exists (Validate has normalised newUser.Email)
+		existingEmail, err := a.userRepo.FindByEmail(txCtx, newUser.Email)
 		if err != nil && !errors.Is(err, domainerrors.ErrUserNotFound) {
 			return domainerrors.ErrInternal.Wrap(err)
 		}
@@ -73,7 +94,13 @@ func (a *authUseCase) Register(ctx context.Context, req *entity.RegisterRequest)
 			return domainerrors.ErrEmailExists
 		}
 
-		return a.userRepo.Create(txCtx, newUser)
+		if err := a.userRepo.Create(txCtx, newUser); err != nil {
+			return err
+		}
+		return a.events.Publish(txCtx, event.UserRegistered{
+			UserID:   newUser.ID,
+			Username: newUser.Username,
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -84,6 +111,17 @@ func (a *authUseCase) Register(ctx context.Context, req *entity.RegisterRequest)
 
 func (a *authUseCase) Login(ctx context.Context, req *entity.LoginRequest) (*entity.LoginResponse, error) {
 	user, err := a.userRepo.FindByUsername(ctx, req.Username)
+	restore := false
+	if errors.Is(err, domainerrors.ErrUserNotFound) {
+		// 处于注销冷静期内的账号：密码正确即视为撤销注销
+		user, err = a.userRepo.FindDeletedByUsername(ctx, req.Username)
+		if err == nil {
+			if user.DeletedAt == nil || time.Since(*user.DeletedAt) >= a.config.AccountDeletionGracePeriod() {
+				return nil, domainerrors.ErrInvalidCredentials
+			}
+			restore = true
+		}
+	}
 	if err != nil {
 		if errors.Is(err, domainerrors.ErrUserNotFound) {
 			return nil, domainerrors.ErrInvalidCredentials
@@ -97,6 +135,19 @@ func (a *authUseCase) Login(ctx context.Context, req *entity.LoginRequest) (*ent
 		return nil, domainerrors.ErrInvalidCredentials
 	}
 
+	if restore {
+		err := a.txManager.WithTx(ctx, func(txCtx context.Context) error {
+			if err := a.userRepo.Restore(txCtx, user.ID); err != nil {
+				return err
+			}
+			return a.events.Publish(txCtx, event.AccountRestored{UserID: user.ID})
+		})
+		if err != nil {
+			return nil, domainerrors.ErrInternal.Wrap(err)
+		}
+		user.DeletedAt = nil
+	}
+
 	// Generate tokens
 	tokenPair, err := a.authenticator.GenerateTokenPair(user)
 	if err != nil {
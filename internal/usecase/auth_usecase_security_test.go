@@ -113,6 +113,7 @@ func TestLogin_UserNotFound_And_WrongPassword_ReturnSameError(t *testing.T) {
 	auth1 := new(testmock.MockAuthenticator)
 	uc1 := newAuthUseCase(repo1, auth1)
 	repo1.On("FindByUsername", mock.Anything, "ghost").Return(nil, domainerrors.ErrUserNotFound)
+	repo1.On("FindDeletedByUsername", mock.Anything, "ghost").Return(nil, domainerrors.ErrUserNotFound)
 
 	_, err1 := uc1.Login(context.Background(), &entity.LoginRequest{
 		Username: "ghost", Password: "whatever",
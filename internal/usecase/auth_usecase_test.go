@@ -13,16 +13,22 @@ import (
 
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
 	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
 	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
 	"github.com/kirklin/boot-backend-go-clean/pkg/configs"
 )
 
 func newAuthUseCase(repo *testmock.MockUserRepository, auth *testmock.MockAuthenticator) *authUseCase {
+	// 默认没有被保留的用户名；需要验证保留逻辑的用例自行替换 usernameHistory
+	history := new(testmock.MockUsernameHistoryRepository)
+	history.On("IsReserved", mock.Anything, mock.Anything, mock.Anything, int64(0)).Return(false, nil).Maybe()
 	return &authUseCase{
-		userRepo:      repo,
-		authenticator: auth,
-		txManager:     testmock.NewPassthroughTxManager(),
-		config:        &configs.AppConfig{RefreshTokenLifetime: 24},
+		userRepo:        repo,
+		usernameHistory: history,
+		authenticator:   auth,
+		txManager:       testmock.NewPassthroughTxManager(),
+		events:          &testmock.EventRecorder{},
+		config:          &configs.AppConfig{RefreshTokenLifetime: 24, AccountDeletionGraceDays: 30, UsernameReservationDays: 90},
 	}
 }
 
@@ -46,9 +52,31 @@ func TestAuthUseCase_Register_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, "newuser", resp.User.Username)
+	assert.Equal(t, []event.Event{event.UserRegistered{
+		UserID: resp.User.ID, Username: "newuser",
+	}}, uc.events.(*testmock.EventRecorder).Events())
 	repo.AssertExpectations(t)
 }
 
+func TestAuthUseCase_Register_EmailExistsInOtherCase(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	auth := new(testmock.MockAuthenticator)
+	uc := newAuthUseCase(repo, auth)
+
+	// 查重使用规范化后的邮箱，与库中的小写邮箱一致
+	repo.On("FindByUsername", mock.Anything, "newuser").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("FindByEmail", mock.Anything, "kirk@example.com").Return(&entity.User{ID: 1}, nil)
+
+	_, err := uc.Register(context.Background(), &entity.RegisterRequest{
+		Username: "newuser",
+		Email:    " Kirk@Example.com ",
+		Password: "securepassword1",
+	})
+
+	assert.ErrorIs(t, err, domainerrors.ErrEmailExists)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestAuthUseCase_Register_UsernameExists(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
 	auth := new(testmock.MockAuthenticator)
@@ -67,6 +95,29 @@ func TestAuthUseCase_Register_UsernameExists(t *testing.T) {
 	assert.Nil(t, resp)
 }
 
+func TestAuthUseCase_Register_UsernameReserved(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	auth := new(testmock.MockAuthenticator)
+	uc := newAuthUseCase(repo, auth)
+	history := new(testmock.MockUsernameHistoryRepository)
+	uc.usernameHistory = history
+
+	repo.On("FindByUsername", mock.Anything, "released").Return(nil, domainerrors.ErrUserNotFound)
+	history.On("IsReserved", mock.Anything, "released", mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) >= 90*24*time.Hour && time.Since(since) < 90*24*time.Hour+time.Minute
+	}), int64(0)).Return(true, nil)
+
+	resp, err := uc.Register(context.Background(), &entity.RegisterRequest{
+		Username: "released",
+		Email:    "new@example.com",
+		Password: "securepassword1",
+	})
+
+	assert.ErrorIs(t, err, domainerrors.ErrUsernameExists)
+	assert.Nil(t, resp)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 // ─── Login ────────────────────────────────────────────────────────────────────
 
 func TestAuthUseCase_Login_Success(t *testing.T) {
@@ -108,6 +159,7 @@ func TestAuthUseCase_Login_UserNotFound(t *testing.T) {
 	uc := newAuthUseCase(repo, auth)
 
 	repo.On("FindByUsername", mock.Anything, "ghost").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("FindDeletedByUsername", mock.Anything, "ghost").Return(nil, domainerrors.ErrUserNotFound)
 
 	resp, err := uc.Login(context.Background(), &entity.LoginRequest{
 		Username: "ghost",
@@ -118,6 +170,62 @@ func TestAuthUseCase_Login_UserNotFound(t *testing.T) {
 	assert.Nil(t, resp)
 }
 
+func TestAuthUseCase_Login_RestoresAccountInGracePeriod(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	auth := new(testmock.MockAuthenticator)
+	uc := newAuthUseCase(repo, auth)
+
+	hashedPw, _ := bcryptHash("securepass")
+	deletedAt := time.Now().Add(-24 * time.Hour)
+	user := &entity.User{ID: 1, Username: "kirk", Password: hashedPw, DeletedAt: &deletedAt}
+	repo.On("FindByUsername", mock.Anything, "kirk").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("FindDeletedByUsername", mock.Anything, "kirk").Return(user, nil)
+	repo.On("Restore", mock.Anything, int64(1)).Return(nil)
+	auth.On("GenerateTokenPair", user).Return(&entity.TokenPair{AccessToken: "access-token"}, nil)
+
+	resp, err := uc.Login(context.Background(), &entity.LoginRequest{Username: "kirk", Password: "securepass"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access-token", resp.AccessToken)
+	assert.Nil(t, resp.User.DeletedAt)
+	assert.Equal(t, []event.Event{event.AccountRestored{UserID: 1}}, uc.events.(*testmock.EventRecorder).Events())
+	repo.AssertExpectations(t)
+}
+
+func TestAuthUseCase_Login_DeletedAccountWrongPasswordNotRestored(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	auth := new(testmock.MockAuthenticator)
+	uc := newAuthUseCase(repo, auth)
+
+	hashedPw, _ := bcryptHash("securepass")
+	deletedAt := time.Now().Add(-time.Hour)
+	repo.On("FindByUsername", mock.Anything, "kirk").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("FindDeletedByUsername", mock.Anything, "kirk").Return(
+		&entity.User{ID: 1, Username: "kirk", Password: hashedPw, DeletedAt: &deletedAt}, nil)
+
+	_, err := uc.Login(context.Background(), &entity.LoginRequest{Username: "kirk", Password: "wrong"})
+
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+	repo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_Login_GracePeriodExpired(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	auth := new(testmock.MockAuthenticator)
+	uc := newAuthUseCase(repo, auth)
+
+	hashedPw, _ := bcryptHash("securepass")
+	deletedAt := time.Now().Add(-31 * 24 * time.Hour)
+	repo.On("FindByUsername", mock.Anything, "kirk").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("FindDeletedByUsername", mock.Anything, "kirk").Return(
+		&entity.User{ID: 1, Username: "kirk", Password: hashedPw, DeletedAt: &deletedAt}, nil)
+
+	_, err := uc.Login(context.Background(), &entity.LoginRequest{Username: "kirk", Password: "securepass"})
+
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+	repo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
+
 func TestAuthUseCase_Login_WrongPassword(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
 	auth := new(testmock.MockAuthenticator)
@@ -254,6 +362,27 @@ func TestAuthUseCase_Register_CreateFails(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
+	assert.Empty(t, uc.events.(*testmock.EventRecorder).Events(), "rolled back registrations are not announced")
+}
+
+func TestAuthUseCase_Register_ConcurrentDuplicate(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	auth := new(testmock.MockAuthenticator)
+	uc := newAuthUseCase(repo, auth)
+
+	// 另一个请求在检查之后抢先注册了同一邮箱，由数据库唯一约束发现
+	repo.On("FindByUsername", mock.Anything, "newuser").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("FindByEmail", mock.Anything, "new@example.com").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Return(domainerrors.ErrEmailExists)
+
+	resp, err := uc.Register(context.Background(), &entity.RegisterRequest{
+		Username: "newuser",
+		Email:    "new@example.com",
+		Password: "securepassword1",
+	})
+
+	assert.ErrorIs(t, err, domainerrors.ErrEmailExists)
+	assert.Nil(t, resp)
 }
 
 // ─── Login Error Branches ─────────────────────────────────────────────────────
@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	"github.com/kirklin/boot-backend-go-clean/pkg/configs"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/pagetoken"
+)
+
+const (
+	// exportBatchSize is the number of queued exports built per run.
+	exportBatchSize = 10
+
+	// exportStaleAfter is how long an export may stay in processing before
+	// another worker takes it over, e.g. after the original one crashed.
+	exportStaleAfter = 30 * time.Minute
+
+	// exportPurgeBatchSize is the number of expired exports removed per query.
+	exportPurgeBatchSize = 100
+
+	// exportDownloadPath is where the download endpoint is mounted; it must
+	// match the route registered in the HTTP layer.
+	exportDownloadPath = "/v1/api/exports/"
+
+	// exportLinkPurpose pins a signed link to the data export download, so a
+	// token issued for another purpose with the same key cannot be replayed here.
+	exportLinkPurpose = "data-export"
+)
+
+// exportLink is the payload carried by a signed download token.
+type exportLink struct {
+	Purpose string `json:"p"`
+	ID      int64  `json:"i,string"`
+	Expires int64  `json:"e"`
+}
+
+type dataExportUseCase struct {
+	exportRepo repository.DataExportRepository
+	userRepo   repository.UserRepository
+	storage    gateway.ObjectStorage
+	notifier   gateway.Notifier
+	exporters  *ExporterRegistry
+	links      *pagetoken.Codec
+	linkTTL    time.Duration
+	publicURL  string
+}
+
+func NewDataExportUseCase(
+	exportRepo repository.DataExportRepository,
+	userRepo repository.UserRepository,
+	storage gateway.ObjectStorage,
+	notifier gateway.Notifier,
+	exporters *ExporterRegistry,
+	config *configs.AppConfig,
+) usecase.DataExportUseCase {
+	return &dataExportUseCase{
+		exportRepo: exportRepo,
+		userRepo:   userRepo,
+		storage:    storage,
+		notifier:   notifier,
+		exporters:  exporters,
+		links:      pagetoken.NewCodec(config.LinkTokenSecret),
+		linkTTL:    config.DataExportLinkTTL(),
+		publicURL:  strings.TrimRight(config.PublicURL, "/"),
+	}
+}
+
+func (u *dataExportUseCase) RequestExport(ctx context.Context, userID int64) (*entity.DataExport, error) {
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// 同一用户同时只保留一个排队中的导出，重复提交直接返回已有的
+	existing, err := u.exportRepo.FindActiveByUserID(ctx, userID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, domainerrors.ErrExportNotFound) {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+
+	export := &entity.DataExport{UserID: userID, Status: entity.DataExportPending}
+	if err := u.exportRepo.Create(ctx, export); err != nil {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	return export, nil
+}
+
+func (u *dataExportUseCase) ProcessPendingExports(ctx context.Context) (int, error) {
+	staleBefore := time.Now().Add(-exportStaleAfter)
+	exports, err := u.exportRepo.ListClaimable(ctx, staleBefore, exportBatchSize)
+	if err != nil {
+		return 0, domainerrors.ErrInternal.Wrap(err)
+	}
+
+	ready := 0
+	for _, export := range exports {
+		if err := ctx.Err(); err != nil {
+			return ready, err
+		}
+		if err := u.exportRepo.Claim(ctx, export, staleBefore); err != nil {
+			if errors.Is(err, domainerrors.ErrNoRowsAffected) {
+				continue // 已被其他实例领取
+			}
+			return ready, domainerrors.ErrInternal.Wrap(err)
+		}
+
+		if err := u.build(ctx, export); err != nil {
+			if errors.Is(err, domainerrors.ErrConcurrentModification) {
+				// 处理太久，领取已过期并被其他实例接手；结果以接手的实例为准
+				logger.FromContext(ctx).Warnf("data export %d was taken over by another worker", export.ID)
+				continue
+			}
+			logger.FromContext(ctx).Errorf("data export %d failed: %v", export.ID, err)
+			u.fail(ctx, export)
+			continue
+		}
+		ready++
+	}
+	return ready, nil
+}
+
+// build writes the archive of export, uploads it and notifies the owner.
+func (u *dataExportUseCase) build(ctx context.Context, export *entity.DataExport) error {
+	user, err := u.userRepo.FindByID(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	// 先写入临时文件：对象存储需要事先知道大小，归档也不必整体放在内存里
+	tmp, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	manifest := exportManifest{UserID: user.ID, GeneratedAt: time.Now().UTC().Format(time.RFC3339)}
+	if err := u.exporters.writeArchive(ctx, tmp, user, manifest); err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key, err := newExportKey(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := u.storage.Put(ctx, key, tmp, size, "application/zip"); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(u.linkTTL)
+	export.Status = entity.DataExportReady
+	export.ObjectKey = &key
+	export.Size = size
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := u.exportRepo.Update(ctx, export); err != nil {
+		// 包括领取被接手（ErrConcurrentModification）的情况：记录指向的是接手实例的归档，这份归档无人引用
+		u.deleteArchive(ctx, key)
+		return err
+	}
+
+	link, err := u.downloadURL(export)
+	if err != nil {
+		return err
+	}
+	u.notify(ctx, user, "Your data export is ready", fmt.Sprintf(
+		"Download your data at %s\nThe link expires at %s.", link, expiresAt.Format(time.RFC1123),
+	))
+	return nil
+}
+
+// fail marks export as failed. The record is kept until the normal expiry so
+// the outcome stays visible, then purged like a finished export.
+func (u *dataExportUseCase) fail(ctx context.Context, export *entity.DataExport) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(u.linkTTL)
+	export.Status = entity.DataExportFailed
+	export.ObjectKey = nil
+	export.Size = 0
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := u.exportRepo.Update(ctx, export); err != nil {
+		// 状态未能落库时，导出会在 exportStaleAfter 之后被重新领取
+		logger.FromContext(ctx).Errorf("failed to mark data export %d as failed: %v", export.ID, err)
+		return
+	}
+
+	if user, err := u.userRepo.FindByID(ctx, export.UserID); err == nil {
+		u.notify(ctx, user, "Your data export failed",
+			"We could not prepare your data export. Please request a new one.")
+	}
+}
+
+// notify sends a message to user; delivery failures are only logged.
+func (u *dataExportUseCase) notify(ctx context.Context, user *entity.User, subject, body string) {
+	err := u.notifier.Notify(ctx, &entity.Notification{
+		UserID:  user.ID,
+		To:      user.Email,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Warnf("failed to notify user %d: %v", user.ID, err)
+	}
+}
+
+// downloadURL returns the signed, time-limited download link of a ready export.
+func (u *dataExportUseCase) downloadURL(export *entity.DataExport) (string, error) {
+	token, err := u.links.Encode(exportLink{
+		Purpose: exportLinkPurpose,
+		ID:      export.ID,
+		Expires: export.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return u.publicURL + exportDownloadPath + token, nil
+}
+
+func (u *dataExportUseCase) OpenExport(ctx context.Context, token string) (*entity.DataExport, io.ReadCloser, error) {
+	var link exportLink
+	if err := u.links.Decode(token, &link); err != nil {
+		return nil, nil, domainerrors.ErrDownloadLinkInvalid.Wrap(err)
+	}
+	now := time.Now()
+	if link.Purpose != exportLinkPurpose || now.Unix() >= link.Expires {
+		return nil, nil, domainerrors.ErrDownloadLinkInvalid
+	}
+
+	export, err := u.exportRepo.FindByID(ctx, link.ID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrExportNotFound) {
+			return nil, nil, domainerrors.ErrDownloadLinkInvalid
+		}
+		return nil, nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	if export.Status != entity.DataExportReady || export.ObjectKey == nil ||
+		export.ExpiresAt == nil || !now.Before(*export.ExpiresAt) {
+		return nil, nil, domainerrors.ErrDownloadLinkInvalid
+	}
+
+	archive, err := u.storage.Get(ctx, *export.ObjectKey)
+	if err != nil {
+		if domainerrors.HasCode(err, domainerrors.ErrObjectNotFound) {
+			return nil, nil, domainerrors.ErrDownloadLinkInvalid.Wrap(err)
+		}
+		return nil, nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	return export, archive, nil
+}
+
+func (u *dataExportUseCase) PurgeExpiredExports(ctx context.Context) (int, error) {
+	now := time.Now()
+	purged := 0
+	for {
+		exports, err := u.exportRepo.ListExpired(ctx, now, exportPurgeBatchSize)
+		if err != nil {
+			return purged, domainerrors.ErrInternal.Wrap(err)
+		}
+
+		for _, export := range exports {
+			if export.ObjectKey != nil {
+				u.deleteArchive(ctx, *export.ObjectKey)
+			}
+			if err := u.exportRepo.Delete(ctx, export.ID); err != nil {
+				return purged, domainerrors.ErrInternal.Wrap(err)
+			}
+			purged++
+		}
+
+		if len(exports) < exportPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// newExportKey returns a fresh, unguessable storage key for one export archive.
+func newExportKey(userID int64) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("exports/%d/%s.zip", userID, hex.EncodeToString(b[:])), nil
+}
+
+// deleteArchive removes an export archive on a best-effort basis and only logs failures.
+func (u *dataExportUseCase) deleteArchive(ctx context.Context, key string) {
+	if err := u.storage.Delete(ctx, key); err != nil {
+		logger.FromContext(ctx).Warnf("failed to delete data export archive %s: %v", key, err)
+	}
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/pagetoken"
+)
+
+type dataExportMocks struct {
+	exports  *testmock.MockDataExportRepository
+	users    *testmock.MockUserRepository
+	storage  *testmock.MockObjectStorage
+	notifier *testmock.MockNotifier
+}
+
+func newDataExportUseCase(exporters ...usecase.DataExporter) (*dataExportUseCase, dataExportMocks) {
+	m := dataExportMocks{
+		exports:  new(testmock.MockDataExportRepository),
+		users:    new(testmock.MockUserRepository),
+		storage:  new(testmock.MockObjectStorage),
+		notifier: new(testmock.MockNotifier),
+	}
+	if len(exporters) == 0 {
+		exporters = []usecase.DataExporter{NewProfileExporter(), NewUploadedFilesExporter(m.storage)}
+	}
+	return &dataExportUseCase{
+		exportRepo: m.exports,
+		userRepo:   m.users,
+		storage:    m.storage,
+		notifier:   m.notifier,
+		exporters:  NewExporterRegistry(exporters...),
+		links:      pagetoken.NewCodec("test-link-secret"),
+		linkTTL:    72 * time.Hour,
+		publicURL:  "https://api.example.com",
+	}, m
+}
+
+// failingExporter always fails, to exercise the error path of an export run.
+type failingExporter struct{}
+
+func (failingExporter) Category() string { return "broken" }
+func (failingExporter) Export(context.Context, *entity.User, usecase.ExportArchive) error {
+	return errors.New("exporter exploded")
+}
+
+// ─── RequestExport ────────────────────────────────────────────────────────────
+
+func TestDataExportUseCase_RequestExport_QueuesExport(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(&entity.User{ID: 42}, nil)
+	m.exports.On("FindActiveByUserID", mock.Anything, int64(42)).Return(nil, domainerrors.ErrExportNotFound)
+	m.exports.On("Create", mock.Anything, mock.MatchedBy(func(e *entity.DataExport) bool {
+		return e.UserID == 42 && e.Status == entity.DataExportPending
+	})).Return(nil)
+
+	export, err := uc.RequestExport(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.DataExportPending, export.Status)
+	m.exports.AssertExpectations(t)
+}
+
+func TestDataExportUseCase_RequestExport_ReturnsActiveExport(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	active := &entity.DataExport{ID: 7, UserID: 42, Status: entity.DataExportProcessing}
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(&entity.User{ID: 42}, nil)
+	m.exports.On("FindActiveByUserID", mock.Anything, int64(42)).Return(active, nil)
+
+	export, err := uc.RequestExport(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Same(t, active, export)
+	m.exports.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// ─── ProcessPendingExports ────────────────────────────────────────────────────
+
+// readZip returns the entries of a zip archive keyed by name.
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestDataExportUseCase_ProcessPendingExports_BuildsArchiveAndSendsLink(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	avatarKey := "avatars/42/abcd/512.png"
+	user := &entity.User{ID: 42, Username: "kirk", Email: "kirk@example.com", Password: "$2a$10$secret-hash", AvatarKey: &avatarKey}
+	export := &entity.DataExport{ID: 7, UserID: 42, Status: entity.DataExportPending}
+
+	m.exports.On("ListClaimable", mock.Anything, mock.Anything, exportBatchSize).Return([]*entity.DataExport{export}, nil)
+	m.exports.On("Claim", mock.Anything, export, mock.Anything).Return(nil)
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(user, nil)
+	// 只有最大尺寸的头像仍在存储中，其余尺寸缺失时应被跳过
+	m.storage.On("Get", mock.Anything, "avatars/42/abcd/512.png").Return(io.NopCloser(strings.NewReader("png-bytes")), nil)
+	m.storage.On("Get", mock.Anything, mock.Anything).Return(nil, domainerrors.ErrObjectNotFound.Wrap(errors.New("gone")))
+	m.storage.On("URL", mock.Anything).Return("https://cdn.example.com/avatar.png")
+
+	var archive []byte
+	m.storage.On("Put", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "exports/42/") && strings.HasSuffix(key, ".zip")
+	}), mock.Anything, mock.Anything, "application/zip").
+		Run(func(args mock.Arguments) {
+			archive, _ = io.ReadAll(args.Get(2).(io.Reader))
+			assert.Equal(t, int64(len(archive)), args.Get(3).(int64))
+		}).Return(nil)
+	m.exports.On("Update", mock.Anything, export).Return(nil)
+
+	var notification *entity.Notification
+	m.notifier.On("Notify", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { notification = args.Get(1).(*entity.Notification) }).
+		Return(nil)
+
+	ready, err := uc.ProcessPendingExports(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, ready)
+	assert.Equal(t, entity.DataExportReady, export.Status)
+	require.NotNil(t, export.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(72*time.Hour), *export.ExpiresAt, time.Minute)
+
+	files := readZip(t, archive)
+	assert.Contains(t, files["manifest.json"], `"profile"`)
+	assert.Contains(t, files["profile/profile.json"], `"kirk@example.com"`)
+	assert.NotContains(t, files["profile/profile.json"], "secret-hash", "password hash must never be exported")
+	assert.Equal(t, "png-bytes", files["files/avatar/512.png"])
+	assert.Contains(t, files["files/files.json"], `"avatar/512.png"`)
+	assert.NotContains(t, files, "files/avatar/256.png")
+
+	require.NotNil(t, notification)
+	assert.Equal(t, "kirk@example.com", notification.To)
+	assert.Contains(t, notification.Body, "https://api.example.com/v1/api/exports/")
+}
+
+func TestDataExportUseCase_ProcessPendingExports_SkipsExportClaimedElsewhere(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	export := &entity.DataExport{ID: 7, UserID: 42, Status: entity.DataExportPending}
+	m.exports.On("ListClaimable", mock.Anything, mock.Anything, exportBatchSize).Return([]*entity.DataExport{export}, nil)
+	m.exports.On("Claim", mock.Anything, export, mock.Anything).Return(domainerrors.ErrNoRowsAffected)
+
+	ready, err := uc.ProcessPendingExports(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, ready)
+	m.users.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestDataExportUseCase_ProcessPendingExports_MarksFailedExport(t *testing.T) {
+	uc, m := newDataExportUseCase(NewProfileExporter(), failingExporter{})
+	user := &entity.User{ID: 42, Email: "kirk@example.com"}
+	export := &entity.DataExport{ID: 7, UserID: 42, Status: entity.DataExportPending}
+
+	m.exports.On("ListClaimable", mock.Anything, mock.Anything, exportBatchSize).Return([]*entity.DataExport{export}, nil)
+	m.exports.On("Claim", mock.Anything, export, mock.Anything).Return(nil)
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(user, nil)
+	m.exports.On("Update", mock.Anything, export).Return(nil)
+	m.notifier.On("Notify", mock.Anything, mock.MatchedBy(func(n *entity.Notification) bool {
+		return strings.Contains(n.Subject, "failed")
+	})).Return(nil)
+
+	ready, err := uc.ProcessPendingExports(context.Background())
+
+	assert.NoError(t, err, "a failing export must not abort the run")
+	assert.Zero(t, ready)
+	assert.Equal(t, entity.DataExportFailed, export.Status)
+	assert.NotNil(t, export.ExpiresAt, "failed exports expire so they get purged")
+	m.storage.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	m.notifier.AssertExpectations(t)
+}
+
+// A worker that took too long loses its claim to another one; it must neither
+// overwrite the other worker's result nor leave its own archive behind.
+func TestDataExportUseCase_ProcessPendingExports_ClaimTakenOver(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	user := &entity.User{ID: 42, Email: "kirk@example.com"}
+	export := &entity.DataExport{ID: 7, UserID: 42, Status: entity.DataExportPending}
+
+	m.exports.On("ListClaimable", mock.Anything, mock.Anything, exportBatchSize).Return([]*entity.DataExport{export}, nil)
+	m.exports.On("Claim", mock.Anything, export, mock.Anything).Return(nil)
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(user, nil)
+	m.storage.On("Get", mock.Anything, mock.Anything).Return(nil, domainerrors.ErrObjectNotFound.Wrap(errors.New("gone")))
+	var key string
+	m.storage.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "application/zip").
+		Run(func(args mock.Arguments) { key = args.String(1) }).Return(nil)
+	m.exports.On("Update", mock.Anything, export).Return(domainerrors.ErrConcurrentModification).Once()
+	m.storage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	ready, err := uc.ProcessPendingExports(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, ready)
+	require.NotEmpty(t, key)
+	m.storage.AssertCalled(t, "Delete", mock.Anything, key)
+	m.exports.AssertNumberOfCalls(t, "Update", 1) // 不会再标记为失败
+	m.notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+}
+
+// ─── OpenExport ───────────────────────────────────────────────────────────────
+
+func readyExport(expiresIn time.Duration) *entity.DataExport {
+	key := "exports/42/abcd.zip"
+	expiresAt := time.Now().Add(expiresIn)
+	return &entity.DataExport{ID: 7, UserID: 42, Status: entity.DataExportReady, ObjectKey: &key, Size: 3, ExpiresAt: &expiresAt}
+}
+
+// tokenFromLink extracts the signed token from a download URL.
+func tokenFromLink(t *testing.T, uc *dataExportUseCase, export *entity.DataExport) string {
+	t.Helper()
+	link, err := uc.downloadURL(export)
+	require.NoError(t, err)
+	return strings.TrimPrefix(link, uc.publicURL+exportDownloadPath)
+}
+
+func TestDataExportUseCase_OpenExport_Success(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	export := readyExport(time.Hour)
+	m.exports.On("FindByID", mock.Anything, int64(7)).Return(export, nil)
+	m.storage.On("Get", mock.Anything, "exports/42/abcd.zip").Return(io.NopCloser(strings.NewReader("zip")), nil)
+
+	got, rc, err := uc.OpenExport(context.Background(), tokenFromLink(t, uc, export))
+
+	require.NoError(t, err)
+	defer rc.Close()
+	assert.Equal(t, int64(7), got.ID)
+}
+
+func TestDataExportUseCase_OpenExport_RejectsInvalidLinks(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	other, _ := newDataExportUseCase()
+
+	expired := readyExport(-time.Minute)
+	valid := readyExport(time.Hour)
+	m.exports.On("FindByID", mock.Anything, int64(7)).Return(valid, nil)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"garbage", "not-a-token"},
+		{"tampered", tokenFromLink(t, uc, valid) + "x"},
+		{"signed with another key", func() string {
+			other.links = pagetoken.NewCodec("another-secret")
+			return tokenFromLink(t, other, valid)
+		}()},
+		{"link expired", tokenFromLink(t, uc, expired)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, rc, err := uc.OpenExport(context.Background(), tt.token)
+
+			var appErr *domainerrors.AppError
+			require.True(t, errors.As(err, &appErr))
+			assert.Equal(t, domainerrors.ErrDownloadLinkInvalid.Code, appErr.Code)
+			assert.Nil(t, rc)
+		})
+	}
+	m.storage.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestDataExportUseCase_OpenExport_ExportAlreadyPurged(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	m.exports.On("FindByID", mock.Anything, int64(7)).Return(nil, domainerrors.ErrExportNotFound)
+
+	_, _, err := uc.OpenExport(context.Background(), tokenFromLink(t, uc, readyExport(time.Hour)))
+
+	assert.ErrorIs(t, err, domainerrors.ErrDownloadLinkInvalid)
+}
+
+// ─── PurgeExpiredExports ──────────────────────────────────────────────────────
+
+func TestDataExportUseCase_PurgeExpiredExports(t *testing.T) {
+	uc, m := newDataExportUseCase()
+	ready := readyExport(-time.Hour)
+	failed := &entity.DataExport{ID: 8, UserID: 42, Status: entity.DataExportFailed}
+	m.exports.On("ListExpired", mock.Anything, mock.Anything, exportPurgeBatchSize).
+		Return([]*entity.DataExport{ready, failed}, nil)
+	m.storage.On("Delete", mock.Anything, "exports/42/abcd.zip").Return(nil)
+	m.exports.On("Delete", mock.Anything, int64(7)).Return(nil)
+	m.exports.On("Delete", mock.Anything, int64(8)).Return(nil)
+
+	purged, err := uc.PurgeExpiredExports(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	m.storage.AssertNumberOfCalls(t, "Delete", 1)
+	m.exports.AssertExpectations(t)
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+)
+
+// ExporterRegistry collects the DataExporters that together make up a
+// personal-data export. Exporters run in registration order.
+type ExporterRegistry struct {
+	exporters []usecase.DataExporter
+}
+
+// NewExporterRegistry creates a registry holding the given exporters.
+func NewExporterRegistry(exporters ...usecase.DataExporter) *ExporterRegistry {
+	r := &ExporterRegistry{}
+	for _, e := range exporters {
+		r.Register(e)
+	}
+	return r
+}
+
+// Register adds e to the registry. It panics if the category is not a single
+// path element or is already taken, because two exporters sharing a directory
+// would silently overwrite each other's files.
+func (r *ExporterRegistry) Register(e usecase.DataExporter) {
+	category := e.Category()
+	if !fs.ValidPath(category) || category == "." || path.Base(category) != category {
+		panic(fmt.Sprintf("data exporter category %q must be a single path element", category))
+	}
+	for _, existing := range r.exporters {
+		if existing.Category() == category {
+			panic(fmt.Sprintf("data exporter category %q registered twice", category))
+		}
+	}
+	r.exporters = append(r.exporters, e)
+}
+
+// Categories returns the registered categories in registration order.
+func (r *ExporterRegistry) Categories() []string {
+	categories := make([]string, 0, len(r.exporters))
+	for _, e := range r.exporters {
+		categories = append(categories, e.Category())
+	}
+	return categories
+}
+
+// exportManifest is written to the archive root and describes its contents.
+type exportManifest struct {
+	UserID      int64    `json:"user_id,string"`
+	GeneratedAt string   `json:"generated_at"`
+	Categories  []string `json:"categories"`
+}
+
+// writeArchive runs every exporter for user and writes the result to w as a zip file.
+func (r *ExporterRegistry) writeArchive(ctx context.Context, w io.Writer, user *entity.User, manifest exportManifest) error {
+	zw := zip.NewWriter(w)
+	manifest.Categories = r.Categories()
+	if err := (zipArchive{zw: zw}).WriteJSON("manifest.json", manifest); err != nil {
+		return err
+	}
+	for _, e := range r.exporters {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := e.Export(ctx, user, zipArchive{zw: zw, dir: e.Category()}); err != nil {
+			return fmt.Errorf("export %s: %w", e.Category(), err)
+		}
+	}
+	return zw.Close()
+}
+
+// zipArchive is the ExportArchive handed to an exporter; every name is placed inside dir.
+type zipArchive struct {
+	zw  *zip.Writer
+	dir string
+}
+
+func (a zipArchive) create(name string) (io.Writer, error) {
+	// 拒绝绝对路径和 ..，导出器只能写入自己的目录
+	if !fs.ValidPath(name) || name == "." {
+		return nil, fmt.Errorf("invalid archive entry name %q", name)
+	}
+	return a.zw.Create(path.Join(a.dir, name))
+}
+
+func (a zipArchive) WriteJSON(name string, v any) error {
+	w, err := a.create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (a zipArchive) WriteFile(name string, r io.Reader) error {
+	w, err := a.create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// profileExporter exports the user record itself.
+type profileExporter struct{}
+
+// NewProfileExporter returns the exporter for the "profile" category.
+func NewProfileExporter() usecase.DataExporter {
+	return profileExporter{}
+}
+
+func (profileExporter) Category() string { return "profile" }
+
+func (profileExporter) Export(_ context.Context, user *entity.User, archive usecase.ExportArchive) error {
+	// 密码哈希和存储 key 带有 json:"-"，不会出现在导出中
+	return archive.WriteJSON("profile.json", user)
+}
+
+// usernameHistoryExporter exports the previous usernames of a user.
+type usernameHistoryExporter struct {
+	history repository.UsernameHistoryRepository
+}
+
+// NewUsernameHistoryExporter returns the exporter for the "username_history" category.
+func NewUsernameHistoryExporter(history repository.UsernameHistoryRepository) usecase.DataExporter {
+	return &usernameHistoryExporter{history: history}
+}
+
+func (e *usernameHistoryExporter) Category() string { return "username_history" }
+
+func (e *usernameHistoryExporter) Export(ctx context.Context, user *entity.User, archive usecase.ExportArchive) error {
+	changes, err := e.history.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	return archive.WriteJSON("username_history.json", changes)
+}
+
+// emailChangesExporter exports the email address changes of a user, including
+// the addresses involved.
+type emailChangesExporter struct {
+	changes repository.EmailChangeRepository
+}
+
+// NewEmailChangesExporter returns the exporter for the "email_changes" category.
+func NewEmailChangesExporter(changes repository.EmailChangeRepository) usecase.DataExporter {
+	return &emailChangesExporter{changes: changes}
+}
+
+func (e *emailChangesExporter) Category() string { return "email_changes" }
+
+func (e *emailChangesExporter) Export(ctx context.Context, user *entity.User, archive usecase.ExportArchive) error {
+	changes, err := e.changes.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	return archive.WriteJSON("email_changes.json", changes)
+}
+
+// preferencesExporter exports the preferences a user has set. Defaults the
+// user never changed are not personal data and are left out.
+type preferencesExporter struct {
+	prefs repository.PreferenceRepository
+}
+
+// NewPreferencesExporter returns the exporter for the "preferences" category.
+func NewPreferencesExporter(prefs repository.PreferenceRepository) usecase.DataExporter {
+	return &preferencesExporter{prefs: prefs}
+}
+
+func (e *preferencesExporter) Category() string { return "preferences" }
+
+func (e *preferencesExporter) Export(ctx context.Context, user *entity.User, archive usecase.ExportArchive) error {
+	values, err := e.prefs.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	return archive.WriteJSON("preferences.json", values)
+}
+
+// uploadedFilesExporter exports the files a user has uploaded to object storage.
+type uploadedFilesExporter struct {
+	storage gateway.ObjectStorage
+}
+
+// NewUploadedFilesExporter returns the exporter for the "files" category.
+func NewUploadedFilesExporter(storage gateway.ObjectStorage) usecase.DataExporter {
+	return &uploadedFilesExporter{storage: storage}
+}
+
+func (e *uploadedFilesExporter) Category() string { return "files" }
+
+// exportedFile is one entry of files/files.json.
+type exportedFile struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
+func (e *uploadedFilesExporter) Export(ctx context.Context, user *entity.User, archive usecase.ExportArchive) error {
+	files := []exportedFile{}
+	if user.AvatarKey != nil {
+		for _, key := range avatarObjectKeys(*user.AvatarKey) {
+			name := "avatar/" + path.Base(key)
+			found, err := e.copyObject(ctx, key, name, archive)
+			if err != nil {
+				return err
+			}
+			if found {
+				files = append(files, exportedFile{Name: name, Kind: "avatar", URL: e.storage.URL(key)})
+			}
+		}
+	}
+	return archive.WriteJSON("files.json", files)
+}
+
+// copyObject writes the object stored under key into the archive; a missing object is skipped.
+func (e *uploadedFilesExporter) copyObject(ctx context.Context, key, name string, archive usecase.ExportArchive) (bool, error) {
+	rc, err := e.storage.Get(ctx, key)
+	if err != nil {
+		if domainerrors.HasCode(err, domainerrors.ErrObjectNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer rc.Close()
+	return true, archive.WriteFile(name, rc)
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+)
+
+// namedExporter is a no-op exporter with a configurable category.
+type namedExporter string
+
+func (e namedExporter) Category() string { return string(e) }
+func (namedExporter) Export(context.Context, *entity.User, usecase.ExportArchive) error {
+	return nil
+}
+
+func TestExporterRegistry_RejectsDuplicateCategory(t *testing.T) {
+	r := NewExporterRegistry(namedExporter("profile"))
+
+	assert.Panics(t, func() { r.Register(namedExporter("profile")) })
+	assert.Equal(t, []string{"profile"}, r.Categories())
+}
+
+func TestExporterRegistry_RejectsInvalidCategory(t *testing.T) {
+	for _, category := range []string{"", ".", "..", "a/b", "/abs"} {
+		assert.Panics(t, func() { NewExporterRegistry(namedExporter(category)) }, "category %q", category)
+	}
+}
+
+func TestZipArchive_KeepsEntriesInsideCategory(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zipArchive{zw: zip.NewWriter(&buf), dir: "files"}
+
+	assert.NoError(t, archive.WriteFile("avatar/512.png", strings.NewReader("x")))
+	assert.Error(t, archive.WriteFile("../profile/profile.json", strings.NewReader("x")))
+	assert.Error(t, archive.WriteJSON("/etc/passwd", "x"))
+}
+
+func TestUsernameHistoryExporter_WritesHistory(t *testing.T) {
+	history := new(testmock.MockUsernameHistoryRepository)
+	history.On("ListByUserID", mock.Anything, int64(42)).Return([]*entity.UsernameChange{
+		{UserID: 42, OldUsername: "jim", NewUsername: "kirk"},
+	}, nil)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	e := NewUsernameHistoryExporter(history)
+	assert.NoError(t, e.Export(context.Background(), &entity.User{ID: 42}, zipArchive{zw: zw, dir: e.Category()}))
+	assert.NoError(t, zw.Close())
+
+	files := readZip(t, buf.Bytes())
+	assert.Contains(t, files["username_history/username_history.json"], `"old_username": "jim"`)
+}
@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	"github.com/kirklin/boot-backend-go-clean/pkg/configs"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/pagetoken"
+)
+
+const (
+	// emailConfirmPurpose and emailRevertPurpose pin a signed link to one step
+	// of the flow, so a revert token cannot be used to confirm and vice versa.
+	emailConfirmPurpose = "email-confirm"
+	emailRevertPurpose  = "email-revert"
+
+	// emailConfirmPagePath and emailRevertPagePath are the client pages the
+	// emailed links open. Confirming and reverting change state, so they are
+	// POST endpoints: the pages submit the token instead of the link doing it,
+	// which keeps mail scanners that prefetch links from acting on them.
+	emailConfirmPagePath = "/email-change/confirm"
+	emailRevertPagePath  = "/email-change/revert"
+)
+
+// emailChangeLink is the payload carried by a signed confirmation or revert token.
+type emailChangeLink struct {
+	Purpose string `json:"p"`
+	ID      int64  `json:"i,string"`
+	Expires int64  `json:"e"`
+}
+
+type emailChangeUseCase struct {
+	changeRepo   repository.EmailChangeRepository
+	userRepo     repository.UserRepository
+	txManager    repository.TxManager
+	notifier     gateway.Notifier
+	events       gateway.EventPublisher
+	links        *pagetoken.Codec
+	linkTTL      time.Duration
+	revertPeriod time.Duration
+	publicURL    string
+}
+
+func NewEmailChangeUseCase(
+	changeRepo repository.EmailChangeRepository,
+	userRepo repository.UserRepository,
+	txManager repository.TxManager,
+	notifier gateway.Notifier,
+	events gateway.EventPublisher,
+	config *configs.AppConfig,
+) usecase.EmailChangeUseCase {
+	return &emailChangeUseCase{
+		changeRepo:   changeRepo,
+		userRepo:     userRepo,
+		txManager:    txManager,
+		notifier:     notifier,
+		events:       events,
+		links:        pagetoken.NewCodec(config.LinkTokenSecret),
+		linkTTL:      config.EmailChangeLinkTTL(),
+		revertPeriod: config.EmailChangeRevertPeriod(),
+		publicURL:    strings.TrimRight(config.PublicURL, "/"),
+	}
+}
+
+func (u *emailChangeUseCase) RequestEmailChange(ctx context.Context, userID int64, req *entity.ChangeEmailRequest) (*entity.EmailChange, error) {
+	if err := req.Validate(); err != nil {
+		return nil, domainerrors.ErrValidationFailed.WithMessage(err.Error())
+	}
+
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return nil, domainerrors.ErrPasswordConfirmation
+	}
+	if req.Email == user.Email {
+		return nil, domainerrors.ErrValidationFailed.WithMessage("new email must differ from the current one")
+	}
+	// 提前检查一次以便及时反馈；确认时还会在事务内再次检查
+	if err := u.ensureEmailAvailable(ctx, req.Email, userID); err != nil {
+		return nil, err
+	}
+
+	change := &entity.EmailChange{
+		UserID:    userID,
+		OldEmail:  user.Email,
+		NewEmail:  req.Email,
+		Status:    entity.EmailChangePending,
+		ExpiresAt: time.Now().Add(u.linkTTL),
+	}
+	err = u.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		// 同一用户只保留最新的一次申请，旧申请的确认链接随之失效
+		if err := u.changeRepo.CancelPendingByUserID(txCtx, userID); err != nil {
+			return err
+		}
+		return u.changeRepo.Create(txCtx, change)
+	})
+	if err != nil {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+
+	confirmURL, err := u.linkURL(emailConfirmPagePath, emailConfirmPurpose, change.ID, change.ExpiresAt)
+	if err != nil {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	revertURL, err := u.linkURL(emailRevertPagePath, emailRevertPurpose, change.ID, change.CreatedAt.Add(u.revertPeriod))
+	if err != nil {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+
+	u.notify(ctx, userID, change.NewEmail, "Confirm your new email address",
+		fmt.Sprintf("Open the link below to use this address for your account. The link expires at %s.\n\n%s",
+			change.ExpiresAt.UTC().Format(time.RFC3339), confirmURL))
+	u.notify(ctx, userID, change.OldEmail, "Your email address is being changed",
+		fmt.Sprintf("A request was made to change the email address of your account to %s. "+
+			"If this was not you, open the link below to cancel or undo the change.\n\n%s",
+			change.NewEmail, revertURL))
+	return change, nil
+}
+
+func (u *emailChangeUseCase) ConfirmEmailChange(ctx context.Context, token string) (*entity.User, error) {
+	id, err := u.decodeLink(token, emailConfirmPurpose)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *entity.User
+	err = u.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		change, err := u.findChange(txCtx, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if change.Status != entity.EmailChangePending || !now.Before(change.ExpiresAt) {
+			return domainerrors.ErrEmailChangeLinkInvalid
+		}
+
+		if user, err = u.findOwner(txCtx, change); err != nil {
+			return err
+		}
+		// 在同一事务内复查并写入，避免新邮箱在申请后被他人注册或占用
+		if err := u.ensureEmailAvailable(txCtx, change.NewEmail, user.ID); err != nil {
+			return err
+		}
+		user.Email = change.NewEmail
+		if err := u.userRepo.UpdateFields(txCtx, user, repository.UserFieldEmail); err != nil {
+			return err
+		}
+
+		change.Status = entity.EmailChangeConfirmed
+		change.ConfirmedAt = &now
+		if err := u.changeRepo.Update(txCtx, change); err != nil {
+			return err
+		}
+		return u.events.Publish(txCtx, event.EmailChanged{
+			UserID:   user.ID,
+			ChangeID: change.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *emailChangeUseCase) RevertEmailChange(ctx context.Context, token string) (*entity.User, error) {
+	id, err := u.decodeLink(token, emailRevertPurpose)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *entity.User
+	err = u.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		change, err := u.findChange(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if user, err = u.findOwner(txCtx, change); err != nil {
+			return err
+		}
+
+		switch change.Status {
+		case entity.EmailChangePending:
+			// 尚未生效，直接作废即可
+			change.Status = entity.EmailChangeCancelled
+		case entity.EmailChangeConfirmed:
+			if err := u.ensureEmailAvailable(txCtx, change.OldEmail, user.ID); err != nil {
+				return err
+			}
+			// 同时作废之后发起的申请，防止冒用者再次修改
+			if err := u.changeRepo.CancelPendingByUserID(txCtx, user.ID); err != nil {
+				return err
+			}
+			user.Email = change.OldEmail
+			if err := u.userRepo.UpdateFields(txCtx, user, repository.UserFieldEmail); err != nil {
+				return err
+			}
+			change.Status = entity.EmailChangeReverted
+			if err := u.events.Publish(txCtx, event.EmailChanged{
+				UserID:   user.ID,
+				ChangeID: change.ID,
+				Reverted: true,
+			}); err != nil {
+				return err
+			}
+		default:
+			return domainerrors.ErrEmailChangeLinkInvalid
+		}
+		return u.changeRepo.Update(txCtx, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// decodeLink verifies a signed link token issued for purpose and returns the email change ID it carries.
+func (u *emailChangeUseCase) decodeLink(token, purpose string) (int64, error) {
+	var link emailChangeLink
+	if err := u.links.Decode(token, &link); err != nil {
+		return 0, domainerrors.ErrEmailChangeLinkInvalid.Wrap(err)
+	}
+	if link.Purpose != purpose || time.Now().Unix() >= link.Expires {
+		return 0, domainerrors.ErrEmailChangeLinkInvalid
+	}
+	return link.ID, nil
+}
+
+// linkURL returns a signed link to page for the email change id, valid until expires.
+func (u *emailChangeUseCase) linkURL(page, purpose string, id int64, expires time.Time) (string, error) {
+	token, err := u.links.Encode(emailChangeLink{
+		Purpose: purpose,
+		ID:      id,
+		Expires: expires.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return u.publicURL + page + "?token=" + url.QueryEscape(token), nil
+}
+
+// findChange loads the email change a link points to. A change that no
+// longer exists makes the link invalid rather than "not found".
+func (u *emailChangeUseCase) findChange(ctx context.Context, id int64) (*entity.EmailChange, error) {
+	change, err := u.changeRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrEmailChangeNotFound) {
+			return nil, domainerrors.ErrEmailChangeLinkInvalid
+		}
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	return change, nil
+}
+
+// findOwner loads the account an email change belongs to. Links of deleted
+// accounts are treated as invalid.
+func (u *emailChangeUseCase) findOwner(ctx context.Context, change *entity.EmailChange) (*entity.User, error) {
+	user, err := u.userRepo.FindByID(ctx, change.UserID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, domainerrors.ErrEmailChangeLinkInvalid
+		}
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	return user, nil
+}
+
+// ensureEmailAvailable checks that no account other than userID uses email.
+func (u *emailChangeUseCase) ensureEmailAvailable(ctx context.Context, email string, userID int64) error {
+	existing, err := u.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil
+		}
+		return domainerrors.ErrInternal.Wrap(err)
+	}
+	if existing.ID != userID {
+		return domainerrors.ErrEmailExists
+	}
+	return nil
+}
+
+// notify sends a message to an address of the user; delivery failures are only logged.
+func (u *emailChangeUseCase) notify(ctx context.Context, userID int64, to, subject, body string) {
+	err := u.notifier.Notify(ctx, &entity.Notification{
+		UserID:  userID,
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Warnf("failed to notify user %d: %v", userID, err)
+	}
+}
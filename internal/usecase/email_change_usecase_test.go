@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/pagetoken"
+)
+
+type emailChangeMocks struct {
+	changes  *testmock.MockEmailChangeRepository
+	users    *testmock.MockUserRepository
+	notifier *testmock.MockNotifier
+	events   *testmock.EventRecorder
+}
+
+func newEmailChangeUseCase() (*emailChangeUseCase, emailChangeMocks) {
+	m := emailChangeMocks{
+		changes:  new(testmock.MockEmailChangeRepository),
+		users:    new(testmock.MockUserRepository),
+		notifier: new(testmock.MockNotifier),
+		events:   &testmock.EventRecorder{},
+	}
+	return &emailChangeUseCase{
+		changeRepo:   m.changes,
+		userRepo:     m.users,
+		txManager:    testmock.NewPassthroughTxManager(),
+		notifier:     m.notifier,
+		events:       m.events,
+		links:        pagetoken.NewCodec("test-link-secret"),
+		linkTTL:      24 * time.Hour,
+		revertPeriod: 7 * 24 * time.Hour,
+		publicURL:    "https://app.example.com",
+	}, m
+}
+
+// emailChangeToken signs a link token the way the use case does.
+func emailChangeToken(t *testing.T, uc *emailChangeUseCase, purpose string, id int64, expires time.Time) string {
+	t.Helper()
+	token, err := uc.links.Encode(emailChangeLink{Purpose: purpose, ID: id, Expires: expires.Unix()})
+	require.NoError(t, err)
+	return token
+}
+
+// tokenFromNotification extracts the token of the link contained in a notification body.
+func tokenFromNotification(t *testing.T, n *entity.Notification) string {
+	t.Helper()
+	i := strings.Index(n.Body, "https://")
+	require.GreaterOrEqual(t, i, 0, "notification carries no link")
+	link, err := url.Parse(strings.TrimSpace(n.Body[i:]))
+	require.NoError(t, err)
+	return link.Query().Get("token")
+}
+
+func pendingEmailChange() *entity.EmailChange {
+	return &entity.EmailChange{
+		ID:        9,
+		UserID:    42,
+		OldEmail:  "kirk@example.com",
+		NewEmail:  "captain@example.com",
+		Status:    entity.EmailChangePending,
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+}
+
+// ─── RequestEmailChange ───────────────────────────────────────────────────────
+
+func TestEmailChangeUseCase_Request_SendsConfirmAndRevertLinks(t *testing.T) {
+	uc, m := newEmailChangeUseCase()
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("securepass"), bcrypt.MinCost)
+	m.users.On("FindByID", mock.Anything, int64(42)).
+		Return(&entity.User{ID: 42, Email: "kirk@example.com", Password: string(hashed)}, nil)
+	m.users.On("FindByEmail", mock.Anything, "captain@example.com").Return(nil, domainerrors.ErrUserNotFound)
+	m.changes.On("CancelPendingByUserID", mock.Anything, int64(42)).Return(nil)
+	m.changes.On("Create", mock.Anything, mock.AnythingOfType("*entity.EmailChange")).
+		Run(func(args mock.Arguments) {
+			c := args.Get(1).(*entity.EmailChange)
+			c.ID = 9
+			c.CreatedAt = time.Now()
+		}).Return(nil)
+
+	sent := map[string]*entity.Notification{}
+	m.notifier.On("Notify", mock.Anything, mock.AnythingOfType("*entity.Notification")).
+		Run(func(args mock.Arguments) {
+			n := args.Get(1).(*entity.Notification)
+			sent[n.To] = n
+		}).Return(nil)
+
+	change, err := uc.RequestEmailChange(context.Background(), 42, &entity.ChangeEmailRequest{
+		Email: "  Captain@Example.com ", Password: "securepass",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.EmailChangePending, change.Status)
+	assert.Equal(t, "kirk@example.com", change.OldEmail)
+	assert.Equal(t, "captain@example.com", change.NewEmail)
+	// 申请阶段不修改账号邮箱
+	m.users.AssertNotCalled(t, "UpdateFields")
+
+	require.Contains(t, sent, "captain@example.com")
+	require.Contains(t, sent, "kirk@example.com")
+	assert.Contains(t, sent["captain@example.com"].Body, "https://app.example.com"+emailConfirmPagePath+"?token=")
+	assert.Contains(t, sent["kirk@example.com"].Body, "https://app.example.com"+emailRevertPagePath+"?token=")
+
+	id, err := uc.decodeLink(tokenFromNotification(t, sent["captain@example.com"]), emailConfirmPurpose)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), id)
+	id, err = uc.decodeLink(tokenFromNotification(t, sent["kirk@example.com"]), emailRevertPurpose)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), id)
+}
+
+func TestEmailChangeUseCase_Request_Rejected(t *testing.T) {
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("securepass"), bcrypt.MinCost)
+	tests := []struct {
+		name     string
+		req      entity.ChangeEmailRequest
+		taken    bool
+		wantCode string
+	}{
+		{"wrong password", entity.ChangeEmailRequest{Email: "captain@example.com", Password: "wrongpass"}, false, domainerrors.ErrPasswordConfirmation.Code},
+		{"same address", entity.ChangeEmailRequest{Email: "KIRK@example.com", Password: "securepass"}, false, domainerrors.ErrValidationFailed.Code},
+		{"invalid address", entity.ChangeEmailRequest{Email: "not-an-email", Password: "securepass"}, false, domainerrors.ErrValidationFailed.Code},
+		{"address taken", entity.ChangeEmailRequest{Email: "captain@example.com", Password: "securepass"}, true, domainerrors.ErrEmailExists.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newEmailChangeUseCase()
+			m.users.On("FindByID", mock.Anything, int64(42)).
+				Return(&entity.User{ID: 42, Email: "kirk@example.com", Password: string(hashed)}, nil).Maybe()
+			if tt.taken {
+				m.users.On("FindByEmail", mock.Anything, "captain@example.com").Return(&entity.User{ID: 7}, nil)
+			}
+
+			_, err := uc.RequestEmailChange(context.Background(), 42, &tt.req)
+
+			assert.True(t, domainerrors.HasCode(err, &domainerrors.AppError{Code: tt.wantCode}), "got %v", err)
+			m.changes.AssertNotCalled(t, "Create")
+			m.notifier.AssertNotCalled(t, "Notify")
+		})
+	}
+}
+
+// ─── ConfirmEmailChange ───────────────────────────────────────────────────────
+
+func TestEmailChangeUseCase_Confirm_Success(t *testing.T) {
+	uc, m := newEmailChangeUseCase()
+	change := pendingEmailChange()
+	user := &entity.User{ID: 42, Email: "kirk@example.com"}
+	m.changes.On("FindByID", mock.Anything, int64(9)).Return(change, nil)
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(user, nil)
+	m.users.On("FindByEmail", mock.Anything, "captain@example.com").Return(nil, domainerrors.ErrUserNotFound)
+	m.users.On("UpdateFields", mock.Anything, user, repository.UserFieldEmail).Return(nil)
+	m.changes.On("Update", mock.Anything, change).Return(nil)
+
+	token := emailChangeToken(t, uc, emailConfirmPurpose, 9, change.ExpiresAt)
+	updated, err := uc.ConfirmEmailChange(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, "captain@example.com", updated.Email)
+	assert.Equal(t, entity.EmailChangeConfirmed, change.Status)
+	assert.NotNil(t, change.ConfirmedAt)
+	assert.Equal(t, []event.Event{event.EmailChanged{
+		UserID: 42, ChangeID: 9,
+	}}, m.events.Events())
+	m.users.AssertExpectations(t)
+	m.changes.AssertExpectations(t)
+}
+
+func TestEmailChangeUseCase_Confirm_EmailTakenMeanwhile(t *testing.T) {
+	uc, m := newEmailChangeUseCase()
+	change := pendingEmailChange()
+	m.changes.On("FindByID", mock.Anything, int64(9)).Return(change, nil)
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(&entity.User{ID: 42, Email: "kirk@example.com"}, nil)
+	m.users.On("FindByEmail", mock.Anything, "captain@example.com").Return(&entity.User{ID: 7}, nil)
+
+	token := emailChangeToken(t, uc, emailConfirmPurpose, 9, change.ExpiresAt)
+	_, err := uc.ConfirmEmailChange(context.Background(), token)
+
+	assert.ErrorIs(t, err, domainerrors.ErrEmailExists)
+	m.users.AssertNotCalled(t, "UpdateFields")
+	m.changes.AssertNotCalled(t, "Update")
+}
+
+func TestEmailChangeUseCase_Confirm_InvalidLink(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+	tests := []struct {
+		name   string
+		token  func(uc *emailChangeUseCase) string
+		change *entity.EmailChange
+	}{
+		{"forged", func(*emailChangeUseCase) string {
+			token, _ := pagetoken.NewCodec("other-secret").Encode(emailChangeLink{Purpose: emailConfirmPurpose, ID: 9, Expires: expires.Unix()})
+			return token
+		}, nil},
+		{"revert token", func(uc *emailChangeUseCase) string {
+			token, _ := uc.links.Encode(emailChangeLink{Purpose: emailRevertPurpose, ID: 9, Expires: expires.Unix()})
+			return token
+		}, nil},
+		{"expired token", func(uc *emailChangeUseCase) string {
+			token, _ := uc.links.Encode(emailChangeLink{Purpose: emailConfirmPurpose, ID: 9, Expires: time.Now().Add(-time.Minute).Unix()})
+			return token
+		}, nil},
+		{"already confirmed", func(uc *emailChangeUseCase) string {
+			token, _ := uc.links.Encode(emailChangeLink{Purpose: emailConfirmPurpose, ID: 9, Expires: expires.Unix()})
+			return token
+		}, &entity.EmailChange{ID: 9, UserID: 42, Status: entity.EmailChangeConfirmed, ExpiresAt: expires}},
+		{"superseded", func(uc *emailChangeUseCase) string {
+			token, _ := uc.links.Encode(emailChangeLink{Purpose: emailConfirmPurpose, ID: 9, Expires: expires.Unix()})
+			return token
+		}, &entity.EmailChange{ID: 9, UserID: 42, Status: entity.EmailChangeCancelled, ExpiresAt: expires}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newEmailChangeUseCase()
+			if tt.change != nil {
+				m.changes.On("FindByID", mock.Anything, int64(9)).Return(tt.change, nil)
+			}
+
+			_, err := uc.ConfirmEmailChange(context.Background(), tt.token(uc))
+
+			assert.True(t, domainerrors.HasCode(err, domainerrors.ErrEmailChangeLinkInvalid), "got %v", err)
+			m.users.AssertNotCalled(t, "UpdateFields")
+		})
+	}
+}
+
+// ─── RevertEmailChange ────────────────────────────────────────────────────────
+
+func TestEmailChangeUseCase_Revert_CancelsPendingChange(t *testing.T) {
+	uc, m := newEmailChangeUseCase()
+	change := pendingEmailChange()
+	m.changes.On("FindByID", mock.Anything, int64(9)).Return(change, nil)
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(&entity.User{ID: 42, Email: "kirk@example.com"}, nil)
+	m.changes.On("Update", mock.Anything, change).Return(nil)
+
+	token := emailChangeToken(t, uc, emailRevertPurpose, 9, time.Now().Add(time.Hour))
+	user, err := uc.RevertEmailChange(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, "kirk@example.com", user.Email)
+	assert.Equal(t, entity.EmailChangeCancelled, change.Status)
+	assert.Empty(t, m.events.Events(), "the email never changed")
+	m.users.AssertNotCalled(t, "UpdateFields")
+}
+
+func TestEmailChangeUseCase_Revert_RestoresConfirmedChange(t *testing.T) {
+	uc, m := newEmailChangeUseCase()
+	change := pendingEmailChange()
+	change.Status = entity.EmailChangeConfirmed
+	user := &entity.User{ID: 42, Email: "captain@example.com"}
+	m.changes.On("FindByID", mock.Anything, int64(9)).Return(change, nil)
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(user, nil)
+	m.users.On("FindByEmail", mock.Anything, "kirk@example.com").Return(nil, domainerrors.ErrUserNotFound)
+	m.changes.On("CancelPendingByUserID", mock.Anything, int64(42)).Return(nil)
+	m.users.On("UpdateFields", mock.Anything, user, repository.UserFieldEmail).Return(nil)
+	m.changes.On("Update", mock.Anything, change).Return(nil)
+
+	token := emailChangeToken(t, uc, emailRevertPurpose, 9, time.Now().Add(time.Hour))
+	restored, err := uc.RevertEmailChange(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, "kirk@example.com", restored.Email)
+	assert.Equal(t, entity.EmailChangeReverted, change.Status)
+	assert.Equal(t, []event.Event{event.EmailChanged{
+		UserID: 42, ChangeID: 9, Reverted: true,
+	}}, m.events.Events())
+	m.users.AssertExpectations(t)
+	m.changes.AssertExpectations(t)
+}
+
+func TestEmailChangeUseCase_Revert_AlreadyReverted(t *testing.T) {
+	uc, m := newEmailChangeUseCase()
+	change := pendingEmailChange()
+	change.Status = entity.EmailChangeReverted
+	m.changes.On("FindByID", mock.Anything, int64(9)).Return(change, nil)
+	m.users.On("FindByID", mock.Anything, int64(42)).Return(&entity.User{ID: 42, Email: "kirk@example.com"}, nil)
+
+	token := emailChangeToken(t, uc, emailRevertPurpose, 9, time.Now().Add(time.Hour))
+	_, err := uc.RevertEmailChange(context.Background(), token)
+
+	assert.True(t, domainerrors.HasCode(err, domainerrors.ErrEmailChangeLinkInvalid), "got %v", err)
+	m.changes.AssertNotCalled(t, "Update")
+}
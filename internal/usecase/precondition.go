@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"context"
+
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+)
+
+// checkExpectedVersion fails with ErrPreconditionFailed if the caller stated
+// an expected version (If-Match) that is not the resource's current one.
+// Writes made afterwards are still version-checked by the repository, which
+// catches changes racing with the request itself.
+func checkExpectedVersion(ctx context.Context, current int64) error {
+	if expected, ok := usecase.ExpectedVersion(ctx); ok && expected != current {
+		return domainerrors.ErrPreconditionFailed
+	}
+	return nil
+}
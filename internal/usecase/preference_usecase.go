@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+)
+
+type preferenceUseCase struct {
+	prefRepo  repository.PreferenceRepository
+	userRepo  repository.UserRepository
+	txManager repository.TxManager
+	registry  *PreferenceRegistry
+}
+
+func NewPreferenceUseCase(
+	prefRepo repository.PreferenceRepository,
+	userRepo repository.UserRepository,
+	txManager repository.TxManager,
+	registry *PreferenceRegistry,
+) usecase.PreferenceUseCase {
+	return &preferenceUseCase{
+		prefRepo:  prefRepo,
+		userRepo:  userRepo,
+		txManager: txManager,
+		registry:  registry,
+	}
+}
+
+func (u *preferenceUseCase) GetPreferences(ctx context.Context, userID int64) (entity.Preferences, error) {
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return u.load(ctx, userID)
+}
+
+func (u *preferenceUseCase) UpdatePreferences(ctx context.Context, userID int64, req entity.UpdatePreferencesRequest) (entity.Preferences, error) {
+	// 先校验整个补丁，任何一个键非法都不写入
+	set := make(map[string]json.RawMessage, len(req))
+	var unset []string
+	for key, raw := range req {
+		def, ok := u.registry.Lookup(key)
+		if !ok {
+			return nil, domainerrors.ErrValidationFailed.WithMessage(fmt.Sprintf("unknown preference %q", key))
+		}
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			unset = append(unset, key)
+			continue
+		}
+		value, err := def.Decode(raw)
+		if err != nil {
+			return nil, domainerrors.ErrValidationFailed.WithMessage(err.Error())
+		}
+		// 以规范化后的 JSON 存储，避免原样保存客户端的空白和格式
+		canonical, err := json.Marshal(value)
+		if err != nil {
+			return nil, domainerrors.ErrInternal.Wrap(err)
+		}
+		set[key] = canonical
+	}
+	sort.Strings(unset)
+
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	err := u.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := u.prefRepo.Set(txCtx, userID, set); err != nil {
+			return err
+		}
+		return u.prefRepo.Unset(txCtx, userID, unset)
+	})
+	if err != nil {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	return u.load(ctx, userID)
+}
+
+// load reads the stored preferences of a user and applies the defaults.
+func (u *preferenceUseCase) load(ctx context.Context, userID int64) (entity.Preferences, error) {
+	stored, err := u.prefRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	return u.registry.resolve(stored), nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+)
+
+func newPreferenceUseCase() (*preferenceUseCase, *testmock.MockPreferenceRepository, *testmock.MockUserRepository) {
+	prefs := new(testmock.MockPreferenceRepository)
+	users := new(testmock.MockUserRepository)
+	registry := NewPreferenceRegistry(append(BuiltinPreferences(),
+		entity.PreferenceDefinition{Key: "editor.tab_width", Type: entity.PreferenceInt, Default: int64(4)},
+	)...)
+	return &preferenceUseCase{
+		prefRepo:  prefs,
+		userRepo:  users,
+		txManager: testmock.NewPassthroughTxManager(),
+		registry:  registry,
+	}, prefs, users
+}
+
+// ─── PreferenceRegistry ───────────────────────────────────────────────────────
+
+func TestPreferenceRegistry_RejectsInvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name string
+		def  entity.PreferenceDefinition
+	}{
+		{"malformed key", entity.PreferenceDefinition{Key: "Theme Color", Type: entity.PreferenceString, Default: "red"}},
+		{"default of wrong type", entity.PreferenceDefinition{Key: "beta", Type: entity.PreferenceBool, Default: "yes"}},
+		{"default not allowed", entity.PreferenceDefinition{Key: "size", Type: entity.PreferenceString, Default: "xl", Allowed: []string{"s", "m"}}},
+		{"allowed on non-string", entity.PreferenceDefinition{Key: "count", Type: entity.PreferenceInt, Default: int64(1), Allowed: []string{"1"}}},
+		{"duplicate key", entity.PreferenceDefinition{Key: "theme", Type: entity.PreferenceString, Default: "system"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewPreferenceRegistry(BuiltinPreferences()...)
+			assert.Panics(t, func() { registry.Register(tt.def) })
+		})
+	}
+}
+
+// ─── GetPreferences ───────────────────────────────────────────────────────────
+
+func TestPreferenceUseCase_GetPreferences_AppliesDefaults(t *testing.T) {
+	uc, prefs, users := newPreferenceUseCase()
+	users.On("FindByID", mock.Anything, int64(42)).Return(&entity.User{ID: 42}, nil)
+	prefs.On("ListByUserID", mock.Anything, int64(42)).Return(map[string]json.RawMessage{
+		"theme":              json.RawMessage(`"dark"`),
+		"notifications.push": json.RawMessage(`false`),
+		"removed.key":        json.RawMessage(`1`),      // 已从 schema 移除的键
+		"editor.tab_width":   json.RawMessage(`"wide"`), // 类型已变更的旧值
+	}, nil)
+
+	got, err := uc.GetPreferences(context.Background(), 42)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.Preferences{
+		"theme":                   "dark",
+		"language":                "en",
+		"notifications.email":     true,
+		"notifications.push":      false,
+		"notifications.marketing": false,
+		"editor.tab_width":        int64(4),
+	}, got)
+}
+
+// ─── UpdatePreferences ────────────────────────────────────────────────────────
+
+func TestPreferenceUseCase_UpdatePreferences_SetsAndResets(t *testing.T) {
+	uc, prefs, users := newPreferenceUseCase()
+	users.On("FindByID", mock.Anything, int64(42)).Return(&entity.User{ID: 42}, nil)
+	prefs.On("Set", mock.Anything, int64(42), map[string]json.RawMessage{
+		"theme":            json.RawMessage(`"light"`),
+		"editor.tab_width": json.RawMessage(`2`),
+	}).Return(nil)
+	prefs.On("Unset", mock.Anything, int64(42), []string{"notifications.push"}).Return(nil)
+	prefs.On("ListByUserID", mock.Anything, int64(42)).Return(map[string]json.RawMessage{
+		"theme":            json.RawMessage(`"light"`),
+		"editor.tab_width": json.RawMessage(`2`),
+	}, nil)
+
+	got, err := uc.UpdatePreferences(context.Background(), 42, entity.UpdatePreferencesRequest{
+		"theme":              json.RawMessage(` "light" `),
+		"editor.tab_width":   json.RawMessage(`2`),
+		"notifications.push": json.RawMessage(`null`),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "light", got["theme"])
+	assert.Equal(t, int64(2), got["editor.tab_width"])
+	assert.Equal(t, true, got["notifications.push"])
+	prefs.AssertExpectations(t)
+}
+
+func TestPreferenceUseCase_UpdatePreferences_RejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		req  entity.UpdatePreferencesRequest
+	}{
+		{"unknown key", entity.UpdatePreferencesRequest{"font": json.RawMessage(`"mono"`)}},
+		{"wrong type", entity.UpdatePreferencesRequest{"notifications.email": json.RawMessage(`"yes"`)}},
+		{"value not allowed", entity.UpdatePreferencesRequest{"theme": json.RawMessage(`"purple"`)}},
+		{"fractional integer", entity.UpdatePreferencesRequest{"editor.tab_width": json.RawMessage(`2.5`)}},
+		{"invalid language tag", entity.UpdatePreferencesRequest{"language": json.RawMessage(`"not a language"`)}},
+		{"one bad key spoils the patch", entity.UpdatePreferencesRequest{
+			"theme": json.RawMessage(`"dark"`), "font": json.RawMessage(`"mono"`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, prefs, _ := newPreferenceUseCase()
+
+			_, err := uc.UpdatePreferences(context.Background(), 42, tt.req)
+
+			assert.True(t, domainerrors.HasCode(err, domainerrors.ErrValidationFailed), "got %v", err)
+			prefs.AssertNotCalled(t, "Set")
+			prefs.AssertNotCalled(t, "Unset")
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/language"
+
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+)
+
+// PreferenceRegistry is the server-side schema of user preferences. Only
+// registered keys can be stored, and every key has a type and a default.
+type PreferenceRegistry struct {
+	defs map[string]entity.PreferenceDefinition
+}
+
+// NewPreferenceRegistry creates a registry holding the given definitions.
+func NewPreferenceRegistry(defs ...entity.PreferenceDefinition) *PreferenceRegistry {
+	r := &PreferenceRegistry{defs: make(map[string]entity.PreferenceDefinition, len(defs))}
+	for _, d := range defs {
+		r.Register(d)
+	}
+	return r
+}
+
+// Register adds d to the registry. It panics if the definition is invalid or
+// the key is already taken: both are programming errors that must not reach
+// users as a half-working schema.
+func (r *PreferenceRegistry) Register(d entity.PreferenceDefinition) {
+	if err := d.CheckDefinition(); err != nil {
+		panic(err.Error())
+	}
+	if _, ok := r.defs[d.Key]; ok {
+		panic(fmt.Sprintf("preference %q registered twice", d.Key))
+	}
+	r.defs[d.Key] = d
+}
+
+// Keys returns the registered keys in lexical order.
+func (r *PreferenceRegistry) Keys() []string {
+	keys := make([]string, 0, len(r.defs))
+	for key := range r.defs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Lookup returns the definition of key.
+func (r *PreferenceRegistry) Lookup(key string) (entity.PreferenceDefinition, bool) {
+	d, ok := r.defs[key]
+	return d, ok
+}
+
+// resolve merges stored values over the registered defaults. Stored values
+// that no longer match the schema (removed keys, changed types) are ignored,
+// so a schema change never breaks reading preferences.
+func (r *PreferenceRegistry) resolve(stored map[string]json.RawMessage) entity.Preferences {
+	prefs := make(entity.Preferences, len(r.defs))
+	for key, d := range r.defs {
+		prefs[key] = d.Default
+		if raw, ok := stored[key]; ok {
+			if v, err := d.Decode(raw); err == nil {
+				prefs[key] = v
+			}
+		}
+	}
+	return prefs
+}
+
+// BuiltinPreferences returns the preferences every deployment supports.
+func BuiltinPreferences() []entity.PreferenceDefinition {
+	return []entity.PreferenceDefinition{
+		{Key: "theme", Type: entity.PreferenceString, Default: "system", Allowed: []string{"system", "light", "dark"}},
+		{Key: "language", Type: entity.PreferenceString, Default: "en", Validate: validateLanguageTag},
+		{Key: "notifications.email", Type: entity.PreferenceBool, Default: true},
+		{Key: "notifications.push", Type: entity.PreferenceBool, Default: true},
+		{Key: "notifications.marketing", Type: entity.PreferenceBool, Default: false},
+	}
+}
+
+// validateLanguageTag accepts BCP 47 language tags such as "zh-CN".
+func validateLanguageTag(v any) error {
+	tag, err := language.Parse(v.(string))
+	if err != nil || tag == language.Und {
+		return errors.New("must be a valid BCP 47 language tag")
+	}
+	return nil
+}
@@ -1,20 +1,88 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
+	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/gateway"
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
+	"github.com/kirklin/boot-backend-go-clean/pkg/configs"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/pagetoken"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
 )
 
+// avatarSizes are the square thumbnail edge lengths (in pixels) rendered for
+// every uploaded avatar, largest first. The largest one is the primary avatar.
+var avatarSizes = []int{512, 256, 128, 64}
+
+// purgeBatchSize is the number of expired accounts purged per query.
+const purgeBatchSize = 100
+
 type userUseCase struct {
-	userRepo repository.UserRepository
+	userRepo            repository.UserRepository
+	usernameHistory     repository.UsernameHistoryRepository
+	emailChanges        repository.EmailChangeRepository
+	preferences         repository.PreferenceRepository
+	exports             repository.DataExportRepository
+	txManager           repository.TxManager
+	storage             gateway.ObjectStorage
+	events              gateway.EventPublisher
+	pageTokens          *pagetoken.Codec
+	maxAvatarBytes      int64
+	usernameCooldown    time.Duration
+	usernameReservation time.Duration
+	gracePeriod         time.Duration
+	purgeMode           string
 }
 
-func NewUserUseCase(userRepo repository.UserRepository) usecase.UserUseCase {
+func NewUserUseCase(
+	userRepo repository.UserRepository,
+	usernameHistory repository.UsernameHistoryRepository,
+	emailChanges repository.EmailChangeRepository,
+	preferences repository.PreferenceRepository,
+	exports repository.DataExportRepository,
+	txManager repository.TxManager,
+	storage gateway.ObjectStorage,
+	events gateway.EventPublisher,
+	config *configs.AppConfig,
+) usecase.UserUseCase {
 	return &userUseCase{
-		userRepo: userRepo,
+		userRepo:            userRepo,
+		usernameHistory:     usernameHistory,
+		emailChanges:        emailChanges,
+		preferences:         preferences,
+		exports:             exports,
+		txManager:           txManager,
+		storage:             storage,
+		events:              events,
+		pageTokens:          pagetoken.NewCodec(config.PageTokenSecret),
+		maxAvatarBytes:      config.AvatarMaxUploadBytes(),
+		usernameCooldown:    config.UsernameChangeCooldown(),
+		usernameReservation: config.UsernameReservationPeriod(),
+		gracePeriod:         config.AccountDeletionGracePeriod(),
+		purgeMode:           config.AccountPurgeMode,
 	}
 }
 
@@ -22,24 +90,445 @@ func (u *userUseCase) GetUserByID(ctx context.Context, id int64) (*entity.User,
 	return u.userRepo.FindByID(ctx, id)
 }
 
-func (u *userUseCase) UpdateUser(ctx context.Context, user *entity.User) error {
-	if err := user.Validate(); err != nil {
-		return err
+// userPageCursor is the payload carried by a user listing page token.
+// Filter and Order pin the token to the query it was issued for, so a
+// cursor cannot be replayed against a different result set.
+type userPageCursor struct {
+	LastID int64  `json:"l,string"`
+	Page   int64  `json:"p"`
+	Order  string `json:"o"`
+	Filter string `json:"f"`
+}
+
+func (u *userUseCase) ListUsers(ctx context.Context, req *entity.ListUsersRequest) (*entity.ListUsersResponse, error) {
+	filter := repository.UserFilter{
+		UsernamePrefix: strings.TrimSpace(req.Username),
+		CreatedAfter:   req.CreatedAfter,
+		CreatedBefore:  req.CreatedBefore,
+	}
+	order := repository.SortAsc
+	if req.Order == string(repository.SortDesc) {
+		order = repository.SortDesc
+	}
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultUserPageSize
+	}
+	if pageSize > maxUserPageSize {
+		pageSize = maxUserPageSize
+	}
+
+	page := req.Page
+	if page <= 0 {
+		page = 1
 	}
+	// Fetch one extra row to learn whether another page follows.
+	opts := repository.ListOptions{Order: order, Limit: int(pageSize) + 1}
 
-	_, err := u.userRepo.FindByID(ctx, user.ID)
+	fingerprint := userFilterFingerprint(filter)
+	if req.PageToken != "" {
+		var cur userPageCursor
+		if err := u.pageTokens.Decode(req.PageToken, &cur); err != nil {
+			return nil, domainerrors.ErrInvalidPageToken.Wrap(err)
+		}
+		if cur.Filter != fingerprint || cur.Order != string(order) || cur.LastID == 0 {
+			return nil, domainerrors.ErrInvalidPageToken.WithMessage("Page token does not match the current query")
+		}
+		opts.AfterID = cur.LastID
+		page = cur.Page
+	} else {
+		opts.Offset = int((page - 1) * pageSize)
+	}
+
+	users, err := u.userRepo.List(ctx, filter, opts)
 	if err != nil {
-		return err
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+	total, err := u.userRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+
+	resp := &entity.ListUsersResponse{
+		Users:    make([]entity.User, 0, min(len(users), int(pageSize))),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	for i, user := range users {
+		if i == int(pageSize) {
+			break
+		}
+		resp.Users = append(resp.Users, *user)
+	}
+
+	if len(users) > int(pageSize) {
+		token, err := u.pageTokens.Encode(userPageCursor{
+			LastID: resp.Users[len(resp.Users)-1].ID,
+			Page:   page + 1,
+			Order:  string(order),
+			Filter: fingerprint,
+		})
+		if err != nil {
+			return nil, domainerrors.ErrInternal.Wrap(err)
+		}
+		resp.NextPageToken = token
+	}
+
+	return resp, nil
+}
+
+// userFilterFingerprint returns a short, stable digest of filter.
+func userFilterFingerprint(filter repository.UserFilter) string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	h := sha256.New()
+	for _, part := range []string{
+		filter.UsernamePrefix,
+		formatTime(filter.CreatedAfter),
+		formatTime(filter.CreatedBefore),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+func (u *userUseCase) UpdateProfile(ctx context.Context, id int64, req *entity.UpdateProfileRequest) (*entity.User, error) {
+	if err := req.Validate(); err != nil {
+		return nil, domainerrors.ErrValidationFailed.WithMessage(err.Error())
+	}
+
+	user, err := u.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpectedVersion(ctx, user.Version); err != nil {
+		return nil, err
+	}
+
+	// Build the field mask from the members present in the patch.
+	var fields []repository.UserField
+	apply := func(patch entity.PatchField[string], dst **string, field repository.UserField) {
+		if patch.Set {
+			*dst = patch.Value
+			fields = append(fields, field)
+		}
+	}
+	apply(req.DisplayName, &user.DisplayName, repository.UserFieldDisplayName)
+	apply(req.Bio, &user.Bio, repository.UserFieldBio)
+	apply(req.Locale, &user.Locale, repository.UserFieldLocale)
+	apply(req.Timezone, &user.Timezone, repository.UserFieldTimezone)
+	apply(req.Website, &user.Website, repository.UserFieldWebsite)
+
+	var replacedAvatarKey string
+	if req.AvatarURL.Set {
+		user.AvatarURL = req.AvatarURL.Value
+		fields = append(fields, repository.UserFieldAvatarURL)
+		// 改为外链头像后，之前上传到对象存储的头像不再被引用
+		if user.AvatarKey != nil {
+			replacedAvatarKey = *user.AvatarKey
+			user.AvatarKey = nil
+			fields = append(fields, repository.UserFieldAvatarKey)
+		}
+	}
+
+	if len(fields) == 0 {
+		return user, nil
+	}
+	if err := u.userRepo.UpdateFields(ctx, user, fields...); err != nil {
+		return nil, err
+	}
+	if replacedAvatarKey != "" {
+		u.deleteAvatarObjects(ctx, avatarObjectKeys(replacedAvatarKey))
+	}
+	return user, nil
+}
+
+func (u *userUseCase) ChangeUsername(ctx context.Context, id int64, req *entity.ChangeUsernameRequest) (*entity.User, error) {
+	if err := req.Validate(); err != nil {
+		return nil, domainerrors.ErrValidationFailed.WithMessage(err.Error())
+	}
+
+	var user *entity.User
+	err := u.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		var err error
+		user, err = u.userRepo.FindByID(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkExpectedVersion(txCtx, user.Version); err != nil {
+			return err
+		}
+		if user.Username == req.Username {
+			return nil
+		}
+
+		history, err := u.usernameHistory.ListByUserID(txCtx, id)
+		if err != nil {
+			return domainerrors.ErrInternal.Wrap(err)
+		}
+		now := time.Now()
+		if len(history) > 0 {
+			if next := history[0].ChangedAt.Add(u.usernameCooldown); now.Before(next) {
+				return domainerrors.ErrUsernameChangeTooSoon.WithMessage(
+					fmt.Sprintf("Username can be changed again after %s", next.UTC().Format(time.RFC3339)))
+			}
+		}
+
+		if err := u.ensureUsernameAvailable(txCtx, req.Username, id, now); err != nil {
+			return err
+		}
+
+		oldUsername := user.Username
+		user.Username = req.Username
+		if err := u.userRepo.UpdateFields(txCtx, user, repository.UserFieldUsername); err != nil {
+			return err
+		}
+		if err := u.usernameHistory.Create(txCtx, &entity.UsernameChange{
+			UserID:      id,
+			OldUsername: oldUsername,
+			NewUsername: req.Username,
+		}); err != nil {
+			return err
+		}
+		return u.events.Publish(txCtx, event.UsernameChanged{
+			UserID:      id,
+			OldUsername: oldUsername,
+			NewUsername: req.Username,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ensureUsernameAvailable checks that username is neither taken, held by an
+// account awaiting deletion (which may still be restored), nor inside the
+// reservation window of another user who released it. A user may always
+// take back a name they released themselves.
+func (u *userUseCase) ensureUsernameAvailable(ctx context.Context, username string, userID int64, now time.Time) error {
+	if _, err := u.userRepo.FindByUsername(ctx, username); err == nil {
+		return domainerrors.ErrUsernameExists
+	} else if !errors.Is(err, domainerrors.ErrUserNotFound) {
+		return domainerrors.ErrInternal.Wrap(err)
+	}
+
+	if _, err := u.userRepo.FindDeletedByUsername(ctx, username); err == nil {
+		return domainerrors.ErrUsernameExists
+	} else if !errors.Is(err, domainerrors.ErrUserNotFound) {
+		return domainerrors.ErrInternal.Wrap(err)
+	}
+
+	reserved, err := u.usernameHistory.IsReserved(ctx, username, now.Add(-u.usernameReservation), userID)
+	if err != nil {
+		return domainerrors.ErrInternal.Wrap(err)
+	}
+	if reserved {
+		return domainerrors.ErrUsernameExists
+	}
+	return nil
+}
+
+func (u *userUseCase) UploadAvatar(ctx context.Context, id int64, file io.Reader) (*entity.AvatarResponse, error) {
+	user, err := u.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpectedVersion(ctx, user.Version); err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the limit so an oversized upload is detected without buffering all of it.
+	data, err := io.ReadAll(io.LimitReader(file, u.maxAvatarBytes+1))
+	if err != nil {
+		return nil, domainerrors.ErrBadRequest.Wrap(err)
+	}
+	if int64(len(data)) > u.maxAvatarBytes {
+		return nil, domainerrors.ErrPayloadTooLarge.WithMessage(fmt.Sprintf("Avatar must not exceed %d bytes", u.maxAvatarBytes))
+	}
+	contentType, ok := utils.SniffImageType(data)
+	if !ok {
+		return nil, domainerrors.ErrUnsupportedMediaType.WithMessage("Avatar must be a JPEG, PNG, GIF or WebP image")
+	}
+	img, err := utils.DecodeImage(data)
+	if err != nil {
+		return nil, domainerrors.ErrInvalidImage.Wrap(err)
+	}
+
+	// 每次上传写入新的目录，URL 随之变化，客户端和 CDN 不会命中旧头像的缓存
+	dir, err := newAvatarDir(id)
+	if err != nil {
+		return nil, domainerrors.ErrInternal.Wrap(err)
+	}
+
+	resp := &entity.AvatarResponse{Sizes: make(map[string]string, len(avatarSizes))}
+	var uploaded []string
+	for _, size := range avatarSizes {
+		encoded, encodedType, err := utils.EncodeImage(utils.SquareThumbnail(img, size), contentType)
+		if err != nil {
+			u.deleteAvatarObjects(ctx, uploaded)
+			return nil, domainerrors.ErrInternal.Wrap(err)
+		}
+		key := avatarObjectKey(dir, size, encodedType)
+		if err := u.storage.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), encodedType); err != nil {
+			u.deleteAvatarObjects(ctx, uploaded)
+			return nil, domainerrors.ErrInternal.Wrap(err)
+		}
+		uploaded = append(uploaded, key)
+		resp.Sizes[strconv.Itoa(size)] = u.storage.URL(key)
+	}
+
+	primaryKey := uploaded[0]
+	resp.AvatarURL = u.storage.URL(primaryKey)
+
+	var previousKey string
+	if user.AvatarKey != nil {
+		previousKey = *user.AvatarKey
+	}
+	user.AvatarURL = &resp.AvatarURL
+	user.AvatarKey = &primaryKey
+	if err := u.userRepo.UpdateFields(ctx, user, repository.UserFieldAvatarURL, repository.UserFieldAvatarKey); err != nil {
+		u.deleteAvatarObjects(ctx, uploaded)
+		return nil, err
+	}
+
+	// 新头像已生效后再清理旧文件；清理失败只会留下孤儿文件，不影响本次上传
+	if previousKey != "" {
+		u.deleteAvatarObjects(ctx, avatarObjectKeys(previousKey))
+	}
+	return resp, nil
+}
+
+// newAvatarDir returns a fresh, unguessable storage directory for one avatar upload.
+func newAvatarDir(userID int64) (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
 	}
+	return fmt.Sprintf("avatars/%d/%s", userID, hex.EncodeToString(b[:])), nil
+}
 
-	return u.userRepo.Update(ctx, user)
+// avatarObjectKey names the thumbnail of the given size inside dir, e.g. "avatars/42/ab12/256.png".
+func avatarObjectKey(dir string, size int, contentType string) string {
+	ext := ".png"
+	if contentType == "image/jpeg" {
+		ext = ".jpg"
+	}
+	return fmt.Sprintf("%s/%d%s", dir, size, ext)
 }
 
-func (u *userUseCase) SoftDeleteUser(ctx context.Context, id int64) error {
-	_, err := u.userRepo.FindByID(ctx, id)
+// avatarObjectKeys derives the keys of every thumbnail from the primary key
+// stored on the user; all renditions share its directory and extension.
+func avatarObjectKeys(primaryKey string) []string {
+	dir, ext := path.Dir(primaryKey), path.Ext(primaryKey)
+	keys := make([]string, 0, len(avatarSizes))
+	for _, size := range avatarSizes {
+		keys = append(keys, fmt.Sprintf("%s/%d%s", dir, size, ext))
+	}
+	return keys
+}
+
+// deleteAvatarObjects removes avatar files on a best-effort basis and only logs failures.
+func (u *userUseCase) deleteAvatarObjects(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := u.storage.Delete(ctx, key); err != nil {
+			logger.FromContext(ctx).Warnf("failed to delete avatar object %s: %v", key, err)
+		}
+	}
+}
+
+// deleteExportArchive removes the data export archive stored under key on a
+// best-effort basis; failures are only logged.
+func (u *userUseCase) deleteExportArchive(ctx context.Context, key string) {
+	if err := u.storage.Delete(ctx, key); err != nil {
+		logger.FromContext(ctx).Warnf("failed to delete data export archive %s: %v", key, err)
+	}
+}
+
+func (u *userUseCase) DeleteAccount(ctx context.Context, id int64, req *entity.DeleteAccountRequest) error {
+	user, err := u.userRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	return u.userRepo.SoftDelete(ctx, id)
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return domainerrors.ErrPasswordConfirmation
+	}
+	if err := checkExpectedVersion(ctx, user.Version); err != nil {
+		return err
+	}
+
+	return u.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := u.userRepo.SoftDelete(txCtx, id); err != nil {
+			return err
+		}
+		return u.events.Publish(txCtx, event.AccountDeleted{UserID: id})
+	})
+}
+
+func (u *userUseCase) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-u.gracePeriod)
+	purged := 0
+	for {
+		users, err := u.userRepo.ListDeletedBefore(ctx, cutoff, purgeBatchSize)
+		if err != nil {
+			return purged, domainerrors.ErrInternal.Wrap(err)
+		}
+
+		for _, user := range users {
+			// 导出归档保存在对象存储中，事务提交后再删除
+			exports, err := u.exports.ListByUserID(ctx, user.ID)
+			if err != nil {
+				return purged, domainerrors.ErrInternal.Wrap(err)
+			}
+			err = u.txManager.WithTx(ctx, func(txCtx context.Context) error {
+				// 旧用户名、邮箱修改记录、偏好设置和数据导出同样属于个人信息，与账号在同一事务中清除
+				if err := u.usernameHistory.DeleteByUserID(txCtx, user.ID); err != nil {
+					return err
+				}
+				if err := u.emailChanges.DeleteByUserID(txCtx, user.ID); err != nil {
+					return err
+				}
+				if err := u.preferences.DeleteByUserID(txCtx, user.ID); err != nil {
+					return err
+				}
+				// 正在生成的导出完成时找不到记录，会自行删除刚上传的归档
+				if err := u.exports.DeleteByUserID(txCtx, user.ID); err != nil {
+					return err
+				}
+				purge := u.userRepo.HardDelete
+				if u.purgeMode == "anonymize" {
+					purge = u.userRepo.Anonymize
+				}
+				if err := purge(txCtx, user.ID); err != nil {
+					return err
+				}
+				return u.events.Publish(txCtx, event.AccountPurged{UserID: user.ID, Mode: u.purgeMode})
+			})
+			if err != nil {
+				return purged, domainerrors.ErrInternal.Wrap(err)
+			}
+			if user.AvatarKey != nil {
+				u.deleteAvatarObjects(ctx, avatarObjectKeys(*user.AvatarKey))
+			}
+			for _, export := range exports {
+				if export.ObjectKey != nil {
+					u.deleteExportArchive(ctx, *export.ObjectKey)
+				}
+			}
+			purged++
+		}
+
+		// 不足一批说明已处理完；否则继续，已处理的账号不会再被查询到
+		if len(users) < purgeBatchSize {
+			return purged, nil
+		}
+	}
 }
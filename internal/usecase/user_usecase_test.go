@@ -1,23 +1,68 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"path"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/kirklin/boot-backend-go-clean/internal/domain/entity"
 	domainerrors "github.com/kirklin/boot-backend-go-clean/internal/domain/errors"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/event"
+	"github.com/kirklin/boot-backend-go-clean/internal/domain/repository"
+	domainusecase "github.com/kirklin/boot-backend-go-clean/internal/domain/usecase"
 	testmock "github.com/kirklin/boot-backend-go-clean/internal/testutil/mock"
+	"github.com/kirklin/boot-backend-go-clean/pkg/utils/pagetoken"
 )
 
+func newUserUseCase(repo *testmock.MockUserRepository) *userUseCase {
+	// 清理账号时总会删除用户名历史；需要断言历史记录的用例使用 newUserUseCaseWithHistory
+	history := new(testmock.MockUsernameHistoryRepository)
+	history.On("DeleteByUserID", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return newUserUseCaseWithHistory(repo, history)
+}
+
+func newUserUseCaseWithHistory(repo *testmock.MockUserRepository, history *testmock.MockUsernameHistoryRepository) *userUseCase {
+	emailChanges := new(testmock.MockEmailChangeRepository)
+	emailChanges.On("DeleteByUserID", mock.Anything, mock.Anything).Return(nil).Maybe()
+	preferences := new(testmock.MockPreferenceRepository)
+	preferences.On("DeleteByUserID", mock.Anything, mock.Anything).Return(nil).Maybe()
+	exports := new(testmock.MockDataExportRepository)
+	exports.On("ListByUserID", mock.Anything, mock.Anything).Return([]*entity.DataExport{}, nil).Maybe()
+	exports.On("DeleteByUserID", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return &userUseCase{
+		userRepo:            repo,
+		usernameHistory:     history,
+		emailChanges:        emailChanges,
+		preferences:         preferences,
+		exports:             exports,
+		txManager:           testmock.NewPassthroughTxManager(),
+		events:              &testmock.EventRecorder{},
+		pageTokens:          pagetoken.NewCodec("test-page-token-secret"),
+		usernameCooldown:    30 * 24 * time.Hour,
+		usernameReservation: 90 * 24 * time.Hour,
+		gracePeriod:         30 * 24 * time.Hour,
+		purgeMode:           "anonymize",
+	}
+}
+
 // ─── GetUserByID ──────────────────────────────────────────────────────────────
 
 func TestUserUseCase_GetUserByID_Success(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	uc := newUserUseCase(repo)
 
 	expected := &entity.User{ID: 1, Username: "kirk", Email: "kirk@example.com"}
 	repo.On("FindByID", mock.Anything, int64(1)).Return(expected, nil)
@@ -31,7 +76,7 @@ func TestUserUseCase_GetUserByID_Success(t *testing.T) {
 
 func TestUserUseCase_GetUserByID_NotFound(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	uc := newUserUseCase(repo)
 
 	repo.On("FindByID", mock.Anything, int64(999)).Return(nil, domainerrors.ErrUserNotFound)
 
@@ -41,103 +86,735 @@ func TestUserUseCase_GetUserByID_NotFound(t *testing.T) {
 	assert.Nil(t, user)
 }
 
-// ─── UpdateUser ───────────────────────────────────────────────────────────────
+// ─── ListUsers ────────────────────────────────────────────────────────────────
 
-func TestUserUseCase_UpdateUser_Success(t *testing.T) {
+func makeUsers(ids ...int64) []*entity.User {
+	users := make([]*entity.User, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, &entity.User{ID: id, Username: fmt.Sprintf("user%d", id)})
+	}
+	return users
+}
+
+func TestUserUseCase_ListUsers_FirstPageIssuesToken(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	uc := newUserUseCase(repo)
 
-	user := &entity.User{ID: 1, Username: "kirk", Email: "kirk@example.com", Password: "securepass"}
+	filter := repository.UserFilter{UsernamePrefix: "user"}
+	repo.On("List", mock.Anything, filter, repository.ListOptions{Order: repository.SortAsc, Limit: 3}).
+		Return(makeUsers(1, 2, 3), nil)
+	repo.On("Count", mock.Anything, filter).Return(int64(5), nil)
+
+	resp, err := uc.ListUsers(context.Background(), &entity.ListUsersRequest{Username: "user", PageSize: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Users, 2)
+	assert.Equal(t, int64(1), resp.Page)
+	assert.Equal(t, int64(5), resp.Total)
+	assert.NotEmpty(t, resp.NextPageToken)
+}
+
+func TestUserUseCase_ListUsers_CursorContinuesAfterLastID(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	filter := repository.UserFilter{}
+	repo.On("List", mock.Anything, filter, repository.ListOptions{Order: repository.SortDesc, Limit: 3}).
+		Return(makeUsers(9, 8, 7), nil).Once()
+	repo.On("List", mock.Anything, filter, repository.ListOptions{Order: repository.SortDesc, Limit: 3, AfterID: 8}).
+		Return(makeUsers(7), nil).Once()
+	repo.On("Count", mock.Anything, filter).Return(int64(3), nil)
+
+	first, err := uc.ListUsers(context.Background(), &entity.ListUsersRequest{Order: "desc", PageSize: 2})
+	assert.NoError(t, err)
+
+	second, err := uc.ListUsers(context.Background(), &entity.ListUsersRequest{Order: "desc", PageSize: 2, PageToken: first.NextPageToken})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), second.Page)
+	assert.Len(t, second.Users, 1)
+	assert.Empty(t, second.NextPageToken)
+	repo.AssertExpectations(t)
+}
+
+func TestUserUseCase_ListUsers_OffsetPagination(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	repo.On("List", mock.Anything, repository.UserFilter{}, repository.ListOptions{Order: repository.SortAsc, Limit: 11, Offset: 20}).
+		Return(makeUsers(21, 22), nil)
+	repo.On("Count", mock.Anything, repository.UserFilter{}).Return(int64(22), nil)
+
+	resp, err := uc.ListUsers(context.Background(), &entity.ListUsersRequest{Page: 3, PageSize: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Page)
+	assert.Len(t, resp.Users, 2)
+	assert.Empty(t, resp.NextPageToken)
+}
+
+func TestUserUseCase_ListUsers_TamperedToken(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	_, err := uc.ListUsers(context.Background(), &entity.ListUsersRequest{PageToken: "eyJsIjoiMSJ9.forged"})
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "INVALID_PAGE_TOKEN", appErr.Code)
+	repo.AssertNotCalled(t, "List")
+}
+
+func TestUserUseCase_ListUsers_TokenBoundToFilter(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	token, err := uc.pageTokens.Encode(userPageCursor{
+		LastID: 10,
+		Page:   2,
+		Order:  "asc",
+		Filter: userFilterFingerprint(repository.UserFilter{UsernamePrefix: "a"}),
+	})
+	assert.NoError(t, err)
+
+	_, err = uc.ListUsers(context.Background(), &entity.ListUsersRequest{Username: "b", PageToken: token})
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "INVALID_PAGE_TOKEN", appErr.Code)
+	repo.AssertNotCalled(t, "List")
+}
+
+func TestUserUseCase_ListUsers_ClampsPageSize(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	repo.On("List", mock.Anything, repository.UserFilter{}, repository.ListOptions{Order: repository.SortAsc, Limit: maxUserPageSize + 1}).
+		Return([]*entity.User{}, nil)
+	repo.On("Count", mock.Anything, repository.UserFilter{}).Return(int64(0), nil)
+
+	resp, err := uc.ListUsers(context.Background(), &entity.ListUsersRequest{PageSize: 1000})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(maxUserPageSize), resp.PageSize)
+	assert.Empty(t, resp.Users)
+}
+
+func TestUserUseCase_ListUsers_RepositoryError(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	repo.On("List", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("db read error"))
+
+	_, err := uc.ListUsers(context.Background(), &entity.ListUsersRequest{})
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "INTERNAL_ERROR", appErr.Code)
+}
+
+// ─── UpdateProfile ────────────────────────────────────────────────────────────
+
+func profilePatch(t *testing.T, body string) *entity.UpdateProfileRequest {
+	t.Helper()
+	var req entity.UpdateProfileRequest
+	assert.NoError(t, json.Unmarshal([]byte(body), &req))
+	return &req
+}
+
+func TestUserUseCase_UpdateProfile_SetsPresentField(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	user := &entity.User{ID: 1, Username: "kirk", Email: "kirk@example.com", Password: "hashed"}
 	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
-	repo.On("Update", mock.Anything, user).Return(nil)
+	repo.On("UpdateFields", mock.Anything, user, repository.UserFieldAvatarURL).Return(nil)
 
-	err := uc.UpdateUser(context.Background(), user)
+	updated, err := uc.UpdateProfile(context.Background(), 1, profilePatch(t, `{"avatar_url":"https://cdn.example.com/a.png"}`))
 
 	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/a.png", *updated.AvatarURL)
+	// Untouched fields keep their stored values
+	assert.Equal(t, "kirk@example.com", updated.Email)
+	assert.Equal(t, "hashed", updated.Password)
 	repo.AssertExpectations(t)
 }
 
-func TestUserUseCase_UpdateUser_ValidationFails(t *testing.T) {
+func TestUserUseCase_UpdateProfile_NullClearsField(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	uc := newUserUseCase(repo)
 
-	user := &entity.User{ID: 1, Username: "", Email: "kirk@example.com", Password: "securepass"}
+	avatar := "https://cdn.example.com/old.png"
+	user := &entity.User{ID: 1, Username: "kirk", AvatarURL: &avatar}
+	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	repo.On("UpdateFields", mock.Anything, user, repository.UserFieldAvatarURL).Return(nil)
 
-	err := uc.UpdateUser(context.Background(), user)
+	updated, err := uc.UpdateProfile(context.Background(), 1, profilePatch(t, `{"avatar_url":null}`))
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "username cannot be empty")
+	assert.NoError(t, err)
+	assert.Nil(t, updated.AvatarURL)
+}
+
+func TestUserUseCase_UpdateProfile_EmptyPatchIsNoop(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	user := &entity.User{ID: 1, Username: "kirk"}
+	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+
+	updated, err := uc.UpdateProfile(context.Background(), 1, profilePatch(t, `{}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, user, updated)
+	repo.AssertNotCalled(t, "UpdateFields")
+}
+
+func TestUserUseCase_UpdateProfile_ValidationFails(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	_, err := uc.UpdateProfile(context.Background(), 1, profilePatch(t, `{"avatar_url":"javascript:alert(1)"}`))
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "VALIDATION_FAILED", appErr.Code)
 	// FindByID should NOT have been called because validation failed first
 	repo.AssertNotCalled(t, "FindByID")
 }
 
-func TestUserUseCase_UpdateUser_NotFound(t *testing.T) {
+func TestUserUseCase_UpdateProfile_NotFound(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	uc := newUserUseCase(repo)
 
-	user := &entity.User{ID: 999, Username: "kirk", Email: "kirk@example.com", Password: "securepass"}
 	repo.On("FindByID", mock.Anything, int64(999)).Return(nil, domainerrors.ErrUserNotFound)
 
-	err := uc.UpdateUser(context.Background(), user)
+	_, err := uc.UpdateProfile(context.Background(), 999, profilePatch(t, `{"avatar_url":null}`))
 
 	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
-	repo.AssertNotCalled(t, "Update")
+	repo.AssertNotCalled(t, "UpdateFields")
+}
+
+func TestUserUseCase_UpdateProfile_ExternalURLDropsUploadedAvatar(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
+
+	avatarKey := "avatars/1/old/512.png"
+	user := &entity.User{ID: 1, Username: "kirk", AvatarKey: &avatarKey}
+	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	repo.On("UpdateFields", mock.Anything, user, repository.UserFieldAvatarURL, repository.UserFieldAvatarKey).Return(nil)
+	storage.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+
+	updated, err := uc.UpdateProfile(context.Background(), 1, profilePatch(t, `{"avatar_url":"https://cdn.example.com/a.png"}`))
+
+	assert.NoError(t, err)
+	assert.Nil(t, updated.AvatarKey)
+	for _, key := range []string{"avatars/1/old/512.png", "avatars/1/old/256.png", "avatars/1/old/128.png", "avatars/1/old/64.png"} {
+		storage.AssertCalled(t, "Delete", mock.Anything, key)
+	}
+}
+
+// ─── UploadAvatar ─────────────────────────────────────────────────────────────
+
+func newUserUseCaseWithStorage(repo *testmock.MockUserRepository, storage *testmock.MockObjectStorage) *userUseCase {
+	uc := newUserUseCase(repo)
+	uc.storage = storage
+	uc.maxAvatarBytes = 1 << 20
+	return uc
+}
+
+// encodePNG renders a w×h opaque PNG for upload tests.
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
 }
 
-// ─── SoftDeleteUser ───────────────────────────────────────────────────────────
+func expectAvatarStorage(storage *testmock.MockObjectStorage) {
+	storage.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").Return(nil)
+	storage.On("URL", mock.AnythingOfType("string")).Return(func(key string) string {
+		return "https://cdn.example.com/" + key
+	})
+}
 
-func TestUserUseCase_SoftDeleteUser_Success(t *testing.T) {
+func TestUserUseCase_UploadAvatar_Success(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
 
 	user := &entity.User{ID: 1, Username: "kirk"}
 	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	repo.On("UpdateFields", mock.Anything, user, repository.UserFieldAvatarURL, repository.UserFieldAvatarKey).Return(nil)
+	expectAvatarStorage(storage)
+
+	resp, err := uc.UploadAvatar(context.Background(), 1, bytes.NewReader(encodePNG(t, 600, 400)))
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Sizes, 4)
+	assert.Equal(t, resp.Sizes["512"], resp.AvatarURL)
+	assert.Regexp(t, `^https://cdn\.example\.com/avatars/1/[0-9a-f]{16}/512\.png$`, resp.AvatarURL)
+	assert.Equal(t, resp.AvatarURL, *user.AvatarURL)
+	assert.Regexp(t, `^avatars/1/[0-9a-f]{16}/512\.png$`, *user.AvatarKey)
+	storage.AssertNumberOfCalls(t, "Put", 4)
+	storage.AssertNotCalled(t, "Delete")
+}
+
+func TestUserUseCase_UploadAvatar_ResizesToStandardSizes(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
+
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1}, nil)
+	repo.On("UpdateFields", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	sizes := map[string]image.Point{}
+	storage.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").
+		Run(func(args mock.Arguments) {
+			cfg, err := png.DecodeConfig(args.Get(2).(io.Reader))
+			assert.NoError(t, err)
+			sizes[path.Base(args.String(1))] = image.Pt(cfg.Width, cfg.Height)
+		}).Return(nil)
+	storage.On("URL", mock.AnythingOfType("string")).Return("https://cdn.example.com/x")
+
+	_, err := uc.UploadAvatar(context.Background(), 1, bytes.NewReader(encodePNG(t, 300, 900)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]image.Point{
+		"512.png": image.Pt(512, 512),
+		"256.png": image.Pt(256, 256),
+		"128.png": image.Pt(128, 128),
+		"64.png":  image.Pt(64, 64),
+	}, sizes)
+}
+
+func TestUserUseCase_UploadAvatar_DeletesPreviousAvatar(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
+
+	oldKey := "avatars/1/0011223344556677/512.jpg"
+	user := &entity.User{ID: 1, Username: "kirk", AvatarKey: &oldKey}
+	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	repo.On("UpdateFields", mock.Anything, user, repository.UserFieldAvatarURL, repository.UserFieldAvatarKey).Return(nil)
+	expectAvatarStorage(storage)
+	storage.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+
+	_, err := uc.UploadAvatar(context.Background(), 1, bytes.NewReader(encodePNG(t, 64, 64)))
+
+	assert.NoError(t, err)
+	storage.AssertNumberOfCalls(t, "Delete", 4)
+	for _, size := range []string{"512", "256", "128", "64"} {
+		storage.AssertCalled(t, "Delete", mock.Anything, "avatars/1/0011223344556677/"+size+".jpg")
+	}
+}
+
+func TestUserUseCase_UploadAvatar_TooLarge(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
+	uc.maxAvatarBytes = 100
+
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1}, nil)
+
+	_, err := uc.UploadAvatar(context.Background(), 1, bytes.NewReader(encodePNG(t, 200, 200)))
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", appErr.Code)
+	storage.AssertNotCalled(t, "Put")
+}
+
+func TestUserUseCase_UploadAvatar_RejectsNonImage(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
+
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1}, nil)
+
+	// The bytes are sniffed; a file merely named or labelled as an image is not enough
+	_, err := uc.UploadAvatar(context.Background(), 1, strings.NewReader("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "UNSUPPORTED_MEDIA_TYPE", appErr.Code)
+	storage.AssertNotCalled(t, "Put")
+}
+
+func TestUserUseCase_UploadAvatar_CorruptImage(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
+
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1}, nil)
+
+	data := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	_, err := uc.UploadAvatar(context.Background(), 1, bytes.NewReader(data))
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "INVALID_IMAGE", appErr.Code)
+	storage.AssertNotCalled(t, "Put")
+}
+
+func TestUserUseCase_UploadAvatar_UpdateFailsRemovesUploads(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
+
+	oldKey := "avatars/1/old/512.png"
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, AvatarKey: &oldKey}, nil)
+	repo.On("UpdateFields", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db down"))
+	expectAvatarStorage(storage)
+	storage.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+
+	_, err := uc.UploadAvatar(context.Background(), 1, bytes.NewReader(encodePNG(t, 64, 64)))
+
+	assert.Error(t, err)
+	// The new renditions are rolled back; the still-referenced old avatar is kept
+	storage.AssertNumberOfCalls(t, "Delete", 4)
+	storage.AssertNotCalled(t, "Delete", mock.Anything, oldKey)
+}
+
+// ─── ChangeUsername ───────────────────────────────────────────────────────────
+
+func TestUserUseCase_ChangeUsername_Success(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	history := new(testmock.MockUsernameHistoryRepository)
+	uc := newUserUseCaseWithHistory(repo, history)
+
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, Username: "kirk"}, nil)
+	// 上一次改名已超过冷却期
+	history.On("ListByUserID", mock.Anything, int64(1)).Return([]*entity.UsernameChange{
+		{UserID: 1, OldUsername: "jim", NewUsername: "kirk", ChangedAt: time.Now().Add(-31 * 24 * time.Hour)},
+	}, nil)
+	repo.On("FindByUsername", mock.Anything, "captain").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("FindDeletedByUsername", mock.Anything, "captain").Return(nil, domainerrors.ErrUserNotFound)
+	// 用户本人释放的旧用户名不受保留期限制
+	history.On("IsReserved", mock.Anything, "captain", mock.Anything, int64(1)).Return(false, nil)
+	repo.On("UpdateFields", mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
+		return u.Username == "captain"
+	}), repository.UserFieldUsername).Return(nil)
+	history.On("Create", mock.Anything, &entity.UsernameChange{UserID: 1, OldUsername: "kirk", NewUsername: "captain"}).Return(nil)
+
+	user, err := uc.ChangeUsername(context.Background(), 1, &entity.ChangeUsernameRequest{Username: "  captain "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "captain", user.Username)
+	assert.Equal(t, []event.Event{event.UsernameChanged{UserID: 1, OldUsername: "kirk", NewUsername: "captain"}},
+		uc.events.(*testmock.EventRecorder).Events())
+	repo.AssertExpectations(t)
+	history.AssertExpectations(t)
+}
+
+func TestUserUseCase_ChangeUsername_SameNameIsNoop(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	history := new(testmock.MockUsernameHistoryRepository)
+	uc := newUserUseCaseWithHistory(repo, history)
+
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, Username: "kirk"}, nil)
+
+	user, err := uc.ChangeUsername(context.Background(), 1, &entity.ChangeUsernameRequest{Username: "kirk"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "kirk", user.Username)
+	repo.AssertNotCalled(t, "UpdateFields")
+	history.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserUseCase_ChangeUsername_CooldownActive(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	history := new(testmock.MockUsernameHistoryRepository)
+	uc := newUserUseCaseWithHistory(repo, history)
+
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, Username: "kirk"}, nil)
+	history.On("ListByUserID", mock.Anything, int64(1)).Return([]*entity.UsernameChange{
+		{UserID: 1, OldUsername: "jim", NewUsername: "kirk", ChangedAt: time.Now().Add(-24 * time.Hour)},
+	}, nil)
+
+	_, err := uc.ChangeUsername(context.Background(), 1, &entity.ChangeUsernameRequest{Username: "captain"})
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, domainerrors.ErrUsernameChangeTooSoon.Code, appErr.Code)
+	repo.AssertNotCalled(t, "UpdateFields")
+}
+
+func TestUserUseCase_ChangeUsername_Unavailable(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(repo *testmock.MockUserRepository, history *testmock.MockUsernameHistoryRepository)
+	}{
+		{"taken by an active user", func(repo *testmock.MockUserRepository, _ *testmock.MockUsernameHistoryRepository) {
+			repo.On("FindByUsername", mock.Anything, "spock").Return(&entity.User{ID: 2, Username: "spock"}, nil)
+		}},
+		{"held by an account awaiting deletion", func(repo *testmock.MockUserRepository, _ *testmock.MockUsernameHistoryRepository) {
+			repo.On("FindByUsername", mock.Anything, "spock").Return(nil, domainerrors.ErrUserNotFound)
+			repo.On("FindDeletedByUsername", mock.Anything, "spock").Return(&entity.User{ID: 2, Username: "spock"}, nil)
+		}},
+		{"reserved for its previous owner", func(repo *testmock.MockUserRepository, history *testmock.MockUsernameHistoryRepository) {
+			repo.On("FindByUsername", mock.Anything, "spock").Return(nil, domainerrors.ErrUserNotFound)
+			repo.On("FindDeletedByUsername", mock.Anything, "spock").Return(nil, domainerrors.ErrUserNotFound)
+			history.On("IsReserved", mock.Anything, "spock", mock.MatchedBy(func(since time.Time) bool {
+				return time.Since(since) >= 90*24*time.Hour && time.Since(since) < 90*24*time.Hour+time.Minute
+			}), int64(1)).Return(true, nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(testmock.MockUserRepository)
+			history := new(testmock.MockUsernameHistoryRepository)
+			uc := newUserUseCaseWithHistory(repo, history)
+			repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, Username: "kirk"}, nil)
+			history.On("ListByUserID", mock.Anything, int64(1)).Return(nil, nil)
+			tt.setup(repo, history)
+
+			_, err := uc.ChangeUsername(context.Background(), 1, &entity.ChangeUsernameRequest{Username: "spock"})
+
+			assert.ErrorIs(t, err, domainerrors.ErrUsernameExists)
+			repo.AssertNotCalled(t, "UpdateFields")
+			history.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUserUseCase_ChangeUsername_InvalidUsername(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	_, err := uc.ChangeUsername(context.Background(), 1, &entity.ChangeUsernameRequest{Username: "deleted_42"})
+
+	var appErr *domainerrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, domainerrors.ErrValidationFailed.Code, appErr.Code)
+	repo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+// ─── DeleteAccount ────────────────────────────────────────────────────────────
+
+func TestUserUseCase_DeleteAccount_Success(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("securepass"), bcrypt.MinCost)
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, Password: string(hashed)}, nil)
 	repo.On("SoftDelete", mock.Anything, int64(1)).Return(nil)
 
-	err := uc.SoftDeleteUser(context.Background(), 1)
+	err := uc.DeleteAccount(context.Background(), 1, &entity.DeleteAccountRequest{Password: "securepass"})
 
 	assert.NoError(t, err)
+	assert.Equal(t, []event.Event{event.AccountDeleted{UserID: 1}}, uc.events.(*testmock.EventRecorder).Events())
 	repo.AssertExpectations(t)
 }
 
-func TestUserUseCase_SoftDeleteUser_NotFound(t *testing.T) {
+func TestUserUseCase_DeleteAccount_WrongPassword(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("securepass"), bcrypt.MinCost)
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, Password: string(hashed)}, nil)
+
+	err := uc.DeleteAccount(context.Background(), 1, &entity.DeleteAccountRequest{Password: "wrong"})
+
+	assert.ErrorIs(t, err, domainerrors.ErrPasswordConfirmation)
+	repo.AssertNotCalled(t, "SoftDelete")
+}
+
+func TestUserUseCase_DeleteAccount_NotFound(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	uc := newUserUseCase(repo)
 
 	repo.On("FindByID", mock.Anything, int64(999)).Return(nil, domainerrors.ErrUserNotFound)
 
-	err := uc.SoftDeleteUser(context.Background(), 999)
+	err := uc.DeleteAccount(context.Background(), 999, &entity.DeleteAccountRequest{Password: "x"})
 
 	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
 	repo.AssertNotCalled(t, "SoftDelete")
 }
 
+// ─── PurgeDeletedAccounts ─────────────────────────────────────────────────────
+
+func TestUserUseCase_PurgeDeletedAccounts_Anonymize(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	storage := new(testmock.MockObjectStorage)
+	uc := newUserUseCaseWithStorage(repo, storage)
+	uc.purgeMode = "anonymize"
+	exports := new(testmock.MockDataExportRepository)
+	archiveKey := "exports/1/archive.zip"
+	exports.On("ListByUserID", mock.Anything, int64(1)).Return([]*entity.DataExport{
+		{ID: 10, UserID: 1, ObjectKey: &archiveKey},
+		{ID: 11, UserID: 1}, // 尚未生成归档
+	}, nil)
+	exports.On("ListByUserID", mock.Anything, int64(2)).Return([]*entity.DataExport{}, nil)
+	exports.On("DeleteByUserID", mock.Anything, int64(1)).Return(nil)
+	exports.On("DeleteByUserID", mock.Anything, int64(2)).Return(nil)
+	uc.exports = exports
+
+	avatarKey := "avatars/2/abc/512.png"
+	repo.On("ListDeletedBefore", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		// Only accounts deleted longer ago than the grace period are eligible
+		return time.Since(before) >= uc.gracePeriod && time.Since(before) < uc.gracePeriod+time.Minute
+	}), purgeBatchSize).Return([]*entity.User{{ID: 1}, {ID: 2, AvatarKey: &avatarKey}}, nil)
+	repo.On("Anonymize", mock.Anything, int64(1)).Return(nil)
+	repo.On("Anonymize", mock.Anything, int64(2)).Return(nil)
+	storage.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+
+	purged, err := uc.PurgeDeletedAccounts(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	repo.AssertNotCalled(t, "HardDelete", mock.Anything, mock.Anything)
+	storage.AssertNumberOfCalls(t, "Delete", 5)
+	storage.AssertCalled(t, "Delete", mock.Anything, archiveKey)
+	exports.AssertExpectations(t)
+	assert.Equal(t, []event.Event{
+		event.AccountPurged{UserID: 1, Mode: "anonymize"},
+		event.AccountPurged{UserID: 2, Mode: "anonymize"},
+	}, uc.events.(*testmock.EventRecorder).Events())
+	history := uc.usernameHistory.(*testmock.MockUsernameHistoryRepository)
+	history.AssertCalled(t, "DeleteByUserID", mock.Anything, int64(1))
+	history.AssertCalled(t, "DeleteByUserID", mock.Anything, int64(2))
+	emailChanges := uc.emailChanges.(*testmock.MockEmailChangeRepository)
+	emailChanges.AssertCalled(t, "DeleteByUserID", mock.Anything, int64(1))
+	emailChanges.AssertCalled(t, "DeleteByUserID", mock.Anything, int64(2))
+	preferences := uc.preferences.(*testmock.MockPreferenceRepository)
+	preferences.AssertCalled(t, "DeleteByUserID", mock.Anything, int64(1))
+	preferences.AssertCalled(t, "DeleteByUserID", mock.Anything, int64(2))
+}
+
+func TestUserUseCase_PurgeDeletedAccounts_HardDeleteInBatches(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+	uc.purgeMode = "delete"
+
+	firstBatch := make([]*entity.User, purgeBatchSize)
+	for i := range firstBatch {
+		firstBatch[i] = &entity.User{ID: int64(i + 1)}
+	}
+	repo.On("ListDeletedBefore", mock.Anything, mock.Anything, purgeBatchSize).Return(firstBatch, nil).Once()
+	repo.On("ListDeletedBefore", mock.Anything, mock.Anything, purgeBatchSize).Return([]*entity.User{{ID: 1000}}, nil).Once()
+	repo.On("HardDelete", mock.Anything, mock.AnythingOfType("int64")).Return(nil)
+
+	purged, err := uc.PurgeDeletedAccounts(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, purgeBatchSize+1, purged)
+	repo.AssertNumberOfCalls(t, "ListDeletedBefore", 2)
+}
+
+func TestUserUseCase_PurgeDeletedAccounts_StopsOnError(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+	uc.purgeMode = "delete"
+
+	repo.On("ListDeletedBefore", mock.Anything, mock.Anything, purgeBatchSize).Return([]*entity.User{{ID: 1}, {ID: 2}}, nil)
+	repo.On("HardDelete", mock.Anything, int64(1)).Return(fmt.Errorf("db down"))
+
+	purged, err := uc.PurgeDeletedAccounts(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, purged)
+	assert.Empty(t, uc.events.(*testmock.EventRecorder).Events())
+	repo.AssertNotCalled(t, "HardDelete", mock.Anything, int64(2))
+}
+
 // ─── Error Branches ───────────────────────────────────────────────────────────
 
-func TestUserUseCase_UpdateUser_UpdateFails(t *testing.T) {
+func TestUserUseCase_UpdateProfile_UpdateFails(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	uc := newUserUseCase(repo)
 
 	user := &entity.User{ID: 1, Username: "kirk", Email: "kirk@example.com", Password: "securepass"}
 	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
-	repo.On("Update", mock.Anything, user).Return(fmt.Errorf("db write error"))
+	repo.On("UpdateFields", mock.Anything, user, repository.UserFieldAvatarURL).Return(fmt.Errorf("db write error"))
 
-	err := uc.UpdateUser(context.Background(), user)
+	_, err := uc.UpdateProfile(context.Background(), 1, profilePatch(t, `{"avatar_url":null}`))
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "db write error")
 }
 
-func TestUserUseCase_SoftDeleteUser_DeleteFails(t *testing.T) {
+func TestUserUseCase_UpdateProfile_ProfileFieldsMask(t *testing.T) {
 	repo := new(testmock.MockUserRepository)
-	uc := NewUserUseCase(repo)
+	uc := newUserUseCase(repo)
 
-	user := &entity.User{ID: 1, Username: "kirk"}
+	bio := "old bio"
+	user := &entity.User{ID: 1, Username: "kirk", Bio: &bio}
 	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
-	repo.On("SoftDelete", mock.Anything, int64(1)).Return(fmt.Errorf("db write error"))
+	repo.On("UpdateFields", mock.Anything, user,
+		repository.UserFieldDisplayName, repository.UserFieldBio, repository.UserFieldTimezone,
+	).Return(nil)
 
-	err := uc.SoftDeleteUser(context.Background(), 1)
+	updated, err := uc.UpdateProfile(context.Background(), 1,
+		profilePatch(t, `{"display_name":" Kirk ","bio":null,"timezone":"Asia/Shanghai"}`))
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "db write error")
+	assert.NoError(t, err)
+	assert.Equal(t, "Kirk", *updated.DisplayName)
+	assert.Nil(t, updated.Bio)
+	assert.Equal(t, "Asia/Shanghai", *updated.Timezone)
+	assert.Nil(t, updated.Locale)
+	repo.AssertExpectations(t)
+}
+
+// ─── Optimistic Concurrency ───────────────────────────────────────────────────
+
+func TestUserUseCase_UpdateProfile_MatchingVersion(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	user := &entity.User{ID: 1, Username: "kirk", Version: 3}
+	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	repo.On("UpdateFields", mock.Anything, user, repository.UserFieldAvatarURL).Return(nil)
+
+	ctx := domainusecase.WithExpectedVersion(context.Background(), 3)
+	_, err := uc.UpdateProfile(ctx, 1, profilePatch(t, `{"avatar_url":null}`))
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateProfile_StaleVersion(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	user := &entity.User{ID: 1, Username: "kirk", Version: 4}
+	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+
+	ctx := domainusecase.WithExpectedVersion(context.Background(), 3)
+	_, err := uc.UpdateProfile(ctx, 1, profilePatch(t, `{"avatar_url":null}`))
+
+	assert.ErrorIs(t, err, domainerrors.ErrPreconditionFailed)
+	repo.AssertNotCalled(t, "UpdateFields")
+}
+
+func TestUserUseCase_UpdateProfile_ConcurrentModification(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	// 读取之后、写入之前被其他请求修改：由仓储的版本条件检测到
+	user := &entity.User{ID: 1, Username: "kirk", Version: 3}
+	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	repo.On("UpdateFields", mock.Anything, user, repository.UserFieldAvatarURL).Return(domainerrors.ErrConcurrentModification)
+
+	_, err := uc.UpdateProfile(context.Background(), 1, profilePatch(t, `{"avatar_url":null}`))
+
+	assert.ErrorIs(t, err, domainerrors.ErrConcurrentModification)
+}
+
+func TestUserUseCase_DeleteAccount_StaleVersion(t *testing.T) {
+	repo := new(testmock.MockUserRepository)
+	uc := newUserUseCase(repo)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("securepass"), bcrypt.MinCost)
+	user := &entity.User{ID: 1, Username: "kirk", Password: string(hashed), Version: 2}
+	repo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+
+	ctx := domainusecase.WithExpectedVersion(context.Background(), 1)
+	err := uc.DeleteAccount(ctx, 1, &entity.DeleteAccountRequest{Password: "securepass"})
+
+	assert.ErrorIs(t, err, domainerrors.ErrPreconditionFailed)
+	repo.AssertNotCalled(t, "SoftDelete")
 }
@@ -0,0 +1,76 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requiredEnv are the settings without a default.
+var requiredEnv = map[string]string{
+	"APP_ENVIRONMENT":       "production",
+	"SERVER_PORT":           "8888",
+	"APP_PUBLIC_URL":        "https://api.example.com/",
+	"DB_TYPE":               "postgres",
+	"DB_HOST":               "postgres",
+	"DB_PORT":               "5432",
+	"DB_USER":               "postgres",
+	"DB_PASSWORD":           "password",
+	"DB_NAME":               "app",
+	"ACCESS_TOKEN_SECRET":   "access",
+	"REFRESH_TOKEN_SECRET":  "refresh",
+	"JWT_ISSUER":            "boot",
+	"PAGE_TOKEN_SECRET":     "page",
+	"LINK_TOKEN_SECRET":     "link",
+	"ENCRYPTION_KEYS":       "k1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+	"ENCRYPTION_ACTIVE_KEY": "k1",
+	"BLIND_INDEX_SECRET":    "blind",
+}
+
+// loadFromEnv loads the configuration from the environment only, without
+// an .env file.
+func loadFromEnv(t *testing.T, env map[string]string) (*AppConfig, error) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	return LoadConfig()
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	config, err := loadFromEnv(t, requiredEnv)
+	require.NoError(t, err, "the secrets and the database address are enough")
+
+	assert.Equal(t, "access", config.AccessTokenSecret, "read from the environment without an .env file")
+	assert.Equal(t, 100, config.DBMaxOpenConns)
+	assert.True(t, config.DBMigrateOnStart)
+	assert.Equal(t, "anonymize", config.AccountPurgeMode)
+	assert.Equal(t, 3600, config.OutboxRetryMaxSeconds)
+	assert.Equal(t, "local", config.StorageDriver)
+	assert.Equal(t, "https://api.example.com/uploads", config.StoragePublicURL)
+}
+
+func TestLoadConfig_Overrides(t *testing.T) {
+	env := map[string]string{"DB_MAX_OPEN_CONNS": "20", "STORAGE_PUBLIC_URL": "https://cdn.example.com"}
+	for key, value := range requiredEnv {
+		env[key] = value
+	}
+	config, err := loadFromEnv(t, env)
+	require.NoError(t, err)
+	assert.Equal(t, 20, config.DBMaxOpenConns)
+	assert.Equal(t, "https://cdn.example.com", config.StoragePublicURL)
+}
+
+func TestLoadConfig_RequiresSecrets(t *testing.T) {
+	_, err := loadFromEnv(t, map[string]string{"DB_TYPE": "sqlite", "DB_NAME": ":memory:"})
+	require.Error(t, err)
+	for _, key := range []string{"ACCESS_TOKEN_SECRET", "PAGE_TOKEN_SECRET", "LINK_TOKEN_SECRET", "ENCRYPTION_KEYS", "BLIND_INDEX_SECRET", "APP_PUBLIC_URL"} {
+		assert.Contains(t, err.Error(), key)
+	}
+	assert.NotContains(t, err.Error(), "OUTBOX_POLL_SECONDS", "settings with a default are not required")
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+// connectAttempts counts the attempts of ConnectWithRetry by outcome
+var connectAttempts = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "db_connect_attempts_total",
+		Help: "Total number of attempts to connect to the database, partitioned by result (success, failure).",
+	},
+	[]string{"db", "result"},
+)
+
+// RetryPolicy configures ConnectWithRetry.
+type RetryPolicy struct {
+	MaxAttempts int           // 最多尝试次数，0 表示一直重试直到 context 结束
+	BaseDelay   time.Duration // 第一次失败后的等待时间，此后每次翻倍
+	MaxDelay    time.Duration // 等待时间的上限
+}
+
+// Backoff returns the delay before the attempt following the given number of
+// failed attempts: BaseDelay doubled per failure up to MaxDelay, of which a
+// random half is kept so that instances restarted together do not retry in
+// lockstep.
+func (p RetryPolicy) Backoff(failures int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, p.MaxDelay)
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// ConnectWithRetry connects db with config, retrying failed attempts per
+// policy. It gives up when the attempts are used up or ctx is done, e.g. on
+// the startup deadline, and returns the last connection error.
+func ConnectWithRetry(ctx context.Context, db Database, config *Config, policy RetryPolicy) error {
+	log := logger.FromContext(ctx)
+	name := config.Name
+	if name == "" {
+		name = "primary"
+	}
+	for attempt := 1; ; attempt++ {
+		err := db.Connect(config)
+		if err == nil {
+			connectAttempts.WithLabelValues(name, "success").Inc()
+			if attempt > 1 {
+				log.Infof("connected to the database after %d attempts", attempt)
+			}
+			return nil
+		}
+		connectAttempts.WithLabelValues(name, "failure").Inc()
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		delay := policy.Backoff(attempt)
+		log.Warnf("database connection attempt %d failed, retrying in %s: %v", attempt, delay.Round(time.Millisecond), err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("giving up after %d attempts (%w): %w", attempt, context.Cause(ctx), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+// ─── Helper ───────────────────────────────────────────────────────────────────
+
+// flakyDB is a Database whose first failures calls to Connect fail.
+type flakyDB struct {
+	failures int
+	attempts int
+}
+
+var errRefused = errors.New("connection refused")
+
+func (f *flakyDB) Connect(*database.Config) error {
+	f.attempts++
+	if f.attempts <= f.failures {
+		return errRefused
+	}
+	return nil
+}
+
+func (f *flakyDB) Close() error { return nil }
+func (f *flakyDB) DB() *gorm.DB { return nil }
+
+func counterValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+	if m := gatherMetric(t, name, labels); m != nil {
+		return m.GetCounter().GetValue()
+	}
+	return 0
+}
+
+// ─── Tests ────────────────────────────────────────────────────────────────────
+
+func TestConnectWithRetry_RetriesUntilConnected(t *testing.T) {
+	db := &flakyDB{failures: 2}
+	config := &database.Config{Name: "connect-test"}
+	failures := counterValue(t, "db_connect_attempts_total", map[string]string{"db": "connect-test", "result": "failure"})
+
+	err := database.ConnectWithRetry(context.Background(), db, config, database.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, 3, db.attempts)
+	assert.Equal(t, failures+2, counterValue(t, "db_connect_attempts_total", map[string]string{"db": "connect-test", "result": "failure"}))
+	assert.Equal(t, 1.0, counterValue(t, "db_connect_attempts_total", map[string]string{"db": "connect-test", "result": "success"}))
+}
+
+func TestConnectWithRetry_GivesUp(t *testing.T) {
+	// 用尽尝试次数
+	db := &flakyDB{failures: 10}
+	err := database.ConnectWithRetry(context.Background(), db, &database.Config{},
+		database.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	assert.ErrorIs(t, err, errRefused)
+	assert.Equal(t, 3, db.attempts)
+
+	// 启动期限先到
+	db = &flakyDB{failures: 10}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = database.ConnectWithRetry(ctx, db, &database.Config{}, database.RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Hour})
+	assert.ErrorIs(t, err, errRefused)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, db.attempts)
+	assert.Less(t, time.Since(start), time.Second, "the wait is interrupted by the deadline")
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := database.RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		failures int
+		max      time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+		{50, time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			delay := policy.Backoff(tt.failures)
+			assert.GreaterOrEqual(t, delay, tt.max/2, "failures=%d", tt.failures)
+			assert.LessOrEqual(t, delay, tt.max, "failures=%d", tt.failures)
+		}
+	}
+	assert.Zero(t, database.RetryPolicy{}.Backoff(1))
+}
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -10,6 +12,13 @@ type Database interface {
 	DB() *gorm.DB
 }
 
+// ReadReplicas is implemented by a Database that can serve reads from read
+// replicas (see ReplicatedDB). DB still returns the primary.
+type ReadReplicas interface {
+	// Reader returns a connection for queries that tolerate replication lag.
+	Reader() *gorm.DB
+}
+
 type Config struct {
 	Host                   string
 	Port                   int
@@ -20,4 +29,51 @@ type Config struct {
 	MaxIdleConns           int
 	MaxOpenConns           int
 	ConnMaxLifetimeMinutes int
+	// ConnMaxIdleTimeMinutes closes connections idle for longer; 0 keeps them.
+	ConnMaxIdleTimeMinutes int
+	// ConnectTimeout bounds establishing a connection; 0 is the driver default.
+	ConnectTimeout time.Duration
+	// TLS complements SSLMode, which takes the libpq modes for both drivers:
+	// disable, allow, prefer, require, verify-ca or verify-full.
+	TLS TLSConfig
+	// ApplicationName identifies the application to the server (PostgreSQL
+	// application_name, MySQL program_name connection attribute).
+	ApplicationName string
+	// StatementTimeout aborts statements running longer; 0 disables it. On
+	// MySQL it is max_execution_time, which only applies to SELECT.
+	StatementTimeout time.Duration
+	// Params are extra driver parameters: run-time parameters for PostgreSQL,
+	// system variables for MySQL. The settings above take precedence.
+	Params map[string]string
+	// DSN is a complete driver connection string. When set it is used as is
+	// instead of the fields above (replicas are configured this way).
+	DSN string
+	// SlowQueryThreshold is the duration from which statements are logged as
+	// slow; zero disables it. LogSQLParams logs bind parameters unredacted.
+	// See NewGormLogger.
+	SlowQueryThreshold time.Duration
+	LogSQLParams       bool
+	// Name identifies the connection in metrics (label db); empty means
+	// "primary". See Instrument.
+	Name string
+}
+
+// TLSConfig holds the certificates of a TLS connection, as PEM files.
+type TLSConfig struct {
+	CAFile     string // 校验服务端证书的 CA，为空时使用系统 CA
+	CertFile   string // 客户端证书，与 KeyFile 同时设置
+	KeyFile    string // 客户端私钥
+	ServerName string // 服务端证书中的主机名，为空时使用 Host
+}
+
+// Discard closes the connection pool of a *gorm.DB that failed to open.
+// gorm.Open returns the pool even when its initial ping fails, and a retried
+// Connect would otherwise leak one pool per attempt. db may be nil.
+func Discard(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
 }
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+// gormLogger writes GORM's logs through the logger of the query's context
+// (logger.FromContext), so that they carry the request_id of the request
+// that ran the query and follow the application's log level:
+//
+//   - failed statements are logged at ErrorLevel (record not found is not a failure),
+//   - statements slower than the slow-query threshold at WarnLevel,
+//   - every other statement at DebugLevel.
+//
+// Unless LogSQLParams is set, text and binary bind parameters are replaced
+// with redactedParam in the logged SQL, because they hold emails, password
+// hashes and tokens. Numbers, booleans and times are kept. (GORM's DB.Scan
+// logs its SQL without asking ParamsFilter; use Find or Row instead.)
+type gormLogger struct {
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+	logParams     bool
+}
+
+// NewGormLogger returns the GORM logger for a connection opened with config.
+func NewGormLogger(config *Config) gormlogger.Interface {
+	return &gormLogger{
+		level:         gormlogger.Info,
+		slowThreshold: config.SlowQueryThreshold,
+		logParams:     config.LogSQLParams,
+	}
+}
+
+// LogMode implements gormlogger.Interface. It caps what is logged; the
+// application's log level still applies.
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		logger.FromContext(ctx).Infof(msg, data...)
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		logger.FromContext(ctx).Warnf(msg, data...)
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		logger.FromContext(ctx).Errorf(msg, data...)
+	}
+}
+
+// Trace implements gormlogger.Interface. GORM calls it after every statement.
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+
+	var level logger.LogLevel
+	msg := "sql"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		level, msg = logger.ErrorLevel, "sql failed"
+	case l.slowThreshold > 0 && elapsed >= l.slowThreshold && l.level >= gormlogger.Warn:
+		level, msg = logger.WarnLevel, "slow sql"
+	case l.level >= gormlogger.Info:
+		level = logger.DebugLevel
+	default:
+		return
+	}
+
+	log := logger.FromContext(ctx)
+	if !log.ShouldLog(level) {
+		return // 不渲染 SQL，避免无谓的开销
+	}
+
+	sql, rows := fc()
+	fields := logger.Fields{
+		"sql":        sql,
+		"elapsed_ms": float64(elapsed.Microseconds()) / 1000.0,
+	}
+	if rows >= 0 {
+		fields["rows"] = rows
+	}
+	if level == logger.ErrorLevel {
+		fields["error"] = err.Error()
+	}
+	log.Log(ctx, level, msg, fields)
+}
+
+// redactedParam replaces the redacted parameters in logged SQL.
+const redactedParam = "[REDACTED]"
+
+// ParamsFilter implements gorm.ParamsFilter: GORM renders the logged SQL
+// with the parameters it returns.
+func (l *gormLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.logParams {
+		return sql, params
+	}
+	redacted := make([]interface{}, len(params))
+	for i, param := range params {
+		switch param.(type) {
+		case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+			float32, float64, time.Time, *time.Time:
+			redacted[i] = param
+		default:
+			redacted[i] = redactedParam
+		}
+	}
+	return sql, redacted
+}
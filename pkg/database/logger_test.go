@@ -0,0 +1,119 @@
+package database_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+// ─── Helper ───────────────────────────────────────────────────────────────────
+
+// logContext returns a context carrying a request logger at level that
+// writes JSON lines to the returned buffer.
+func logContext(t *testing.T, level logger.LogLevel) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	l, err := logger.NewLogger(&logger.LoggerConfig{Level: level, Format: logger.JSONFormat, Output: &buf}, "slog")
+	require.NoError(t, err)
+	return logger.NewContext(context.Background(), l.WithTrace("req-1")), &buf
+}
+
+// logEntries decodes the JSON lines written to buf.
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func trace(l gormlogger.Interface, ctx context.Context, elapsed time.Duration, err error) {
+	l.Trace(ctx, time.Now().Add(-elapsed), func() (string, int64) { return "SELECT 1", 1 }, err)
+}
+
+// ─── Tests ────────────────────────────────────────────────────────────────────
+
+func TestGormLogger_Levels(t *testing.T) {
+	l := database.NewGormLogger(&database.Config{SlowQueryThreshold: 100 * time.Millisecond})
+
+	ctx, buf := logContext(t, logger.DebugLevel)
+	trace(l, ctx, time.Millisecond, nil)
+	trace(l, ctx, time.Second, nil)
+	trace(l, ctx, time.Millisecond, errors.New("syntax error"))
+	trace(l, ctx, time.Millisecond, gormlogger.ErrRecordNotFound)
+
+	entries := logEntries(t, buf)
+	require.Len(t, entries, 4)
+	assert.Equal(t, "DEBUG", entries[0]["level"])
+	assert.Equal(t, "SELECT 1", entries[0]["sql"])
+	assert.Equal(t, "req-1", entries[0]["traceID"], "carries the request's trace")
+	assert.Equal(t, "WARN", entries[1]["level"])
+	assert.Equal(t, "slow sql", entries[1]["msg"])
+	assert.Equal(t, "ERROR", entries[2]["level"])
+	assert.Equal(t, "syntax error", entries[2]["error"])
+	assert.Equal(t, "DEBUG", entries[3]["level"], "record not found is not a failure")
+}
+
+func TestGormLogger_FollowsApplicationLevel(t *testing.T) {
+	l := database.NewGormLogger(&database.Config{SlowQueryThreshold: 100 * time.Millisecond})
+
+	ctx, buf := logContext(t, logger.WarnLevel)
+	rendered := false
+	l.Trace(ctx, time.Now(), func() (string, int64) { rendered = true; return "SELECT 1", 1 }, nil)
+	trace(l, ctx, time.Second, nil)
+
+	assert.False(t, rendered, "SQL of skipped statements is not rendered")
+	entries := logEntries(t, buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "slow sql", entries[0]["msg"])
+
+	// LogMode 进一步限制 GORM 的日志
+	ctx, buf = logContext(t, logger.DebugLevel)
+	trace(l.LogMode(gormlogger.Error), ctx, time.Second, nil)
+	trace(l.LogMode(gormlogger.Silent), ctx, time.Second, errors.New("failed"))
+	assert.Empty(t, buf.String())
+}
+
+func TestGormLogger_RedactsParameters(t *testing.T) {
+	// loggedSQL runs a query on a connection opened with logParams and
+	// returns the SQL that was logged for it.
+	loggedSQL := func(logParams bool) string {
+		db := sqlite.NewSQLiteDB()
+		require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName, LogSQLParams: logParams}))
+		t.Cleanup(func() { _ = db.Close() })
+
+		ctx, buf := logContext(t, logger.DebugLevel)
+		var n int
+		require.NoError(t, db.DB().WithContext(ctx).
+			Raw("SELECT count(*) FROM sqlite_master WHERE name = ? OR rootpage = ?", "kirk@example.com", 42).
+			Row().Scan(&n))
+		entries := logEntries(t, buf)
+		require.NotEmpty(t, entries)
+		return entries[len(entries)-1]["sql"].(string)
+	}
+
+	sql := loggedSQL(false)
+	assert.Contains(t, sql, "[REDACTED]")
+	assert.Contains(t, sql, "42", "numbers are kept")
+	assert.NotContains(t, sql, "kirk@example.com")
+
+	assert.Contains(t, loggedSQL(true), "kirk@example.com")
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+var (
+	// queryDuration is a histogram that tracks the latency of statements run through GORM
+	queryDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Latency of database statements in seconds, partitioned by table and operation (create, query, update, delete, row, raw).",
+			Buckets: []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+		},
+		[]string{"table", "operation"},
+	)
+
+	// poolStats exports the connection pool statistics of every instrumented connection
+	poolStats = newPoolCollector()
+)
+
+func init() {
+	prometheus.MustRegister(poolStats)
+}
+
+// Instrument exports the pool statistics of db under the label db=name and
+// records the duration of its statements. The drivers call it on Connect.
+// Instrumenting another connection under the same name replaces it.
+func Instrument(db *gorm.DB, name string) error {
+	if name == "" {
+		name = "primary"
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance for metrics: %w", err)
+	}
+	if err := registerQueryCallbacks(db); err != nil {
+		return fmt.Errorf("failed to register metrics callbacks: %w", err)
+	}
+	poolStats.add(name, sqlDB)
+	return nil
+}
+
+const queryStartKey = "metrics:query_start"
+
+// registerQueryCallbacks times every statement from before it is built
+// until it has run.
+func registerQueryCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startQuery),
+		cb.Create().After("gorm:create").Register("metrics:after_create", observeQuery("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startQuery),
+		cb.Query().After("gorm:query").Register("metrics:after_query", observeQuery("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startQuery),
+		cb.Update().After("gorm:update").Register("metrics:after_update", observeQuery("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startQuery),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", observeQuery("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startQuery),
+		cb.Row().After("gorm:row").Register("metrics:after_row", observeQuery("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startQuery),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", observeQuery("raw")),
+	)
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func observeQuery(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		start, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		// Raw 与 Exec 语句没有模型，不解析 SQL 以免标签基数失控
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		queryDuration.WithLabelValues(table, operation).Observe(time.Since(start.(time.Time)).Seconds())
+	}
+}
+
+// poolCollector is a prometheus.Collector reporting sql.DBStats for a set
+// of named connections, so that connections can come and go (replicas,
+// reconnects, tests) without registering collectors again.
+type poolCollector struct {
+	mu  sync.RWMutex
+	dbs map[string]*sql.DB
+
+	maxOpen      *prometheus.Desc
+	open         *prometheus.Desc
+	inUse        *prometheus.Desc
+	idle         *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+}
+
+func newPoolCollector() *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(name, help, []string{"db"}, nil)
+	}
+	return &poolCollector{
+		dbs:          make(map[string]*sql.DB),
+		maxOpen:      desc("db_max_open_connections", "Maximum number of open connections to the database."),
+		open:         desc("db_open_connections", "Number of established connections, in use and idle."),
+		inUse:        desc("db_in_use_connections", "Number of connections currently in use."),
+		idle:         desc("db_idle_connections", "Number of idle connections."),
+		waitCount:    desc("db_wait_count_total", "Total number of connections waited for because the pool was exhausted."),
+		waitDuration: desc("db_wait_duration_seconds_total", "Total time in seconds blocked waiting for a new connection."),
+	}
+}
+
+func (c *poolCollector) add(name string, db *sql.DB) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dbs[name] = db
+}
+
+// Describe implements prometheus.Collector.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+}
+
+// Collect implements prometheus.Collector.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for name, db := range c.dbs {
+		stats := db.Stats()
+		ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections), name)
+		ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections), name)
+		ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse), name)
+		ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle), name)
+		ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount), name)
+		ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds(), name)
+	}
+}
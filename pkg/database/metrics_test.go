@@ -0,0 +1,93 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+)
+
+// ─── Helper ───────────────────────────────────────────────────────────────────
+
+// gatherMetric returns the metric of family name whose labels include
+// labels, or nil.
+func gatherMetric(t *testing.T, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range family.GetMetric() {
+			for _, pair := range m.GetLabel() {
+				if want, ok := labels[pair.GetName()]; ok && want != pair.GetValue() {
+					continue metrics
+				}
+			}
+			return m
+		}
+	}
+	return nil
+}
+
+// ─── Tests ────────────────────────────────────────────────────────────────────
+
+func TestInstrument_PoolStats(t *testing.T) {
+	db := sqlite.NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName, Name: "metrics-test"}))
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, db.DB().Exec("SELECT 1").Error)
+
+	maxOpen := gatherMetric(t, "db_max_open_connections", map[string]string{"db": "metrics-test"})
+	require.NotNil(t, maxOpen)
+	assert.Equal(t, 1.0, maxOpen.GetGauge().GetValue())
+
+	open := gatherMetric(t, "db_open_connections", map[string]string{"db": "metrics-test"})
+	require.NotNil(t, open)
+	assert.Equal(t, 1.0, open.GetGauge().GetValue())
+	assert.NotNil(t, gatherMetric(t, "db_wait_count_total", map[string]string{"db": "metrics-test"}))
+}
+
+func TestInstrument_QueryDurations(t *testing.T) {
+	db := sqlite.NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	t.Cleanup(func() { _ = db.Close() })
+
+	type metricsProbe struct {
+		ID   int64
+		Name string
+	}
+	gdb := db.DB()
+	require.NoError(t, gdb.Exec("CREATE TABLE metrics_probes (id INTEGER PRIMARY KEY, name TEXT)").Error)
+
+	countOf := func(operation string) uint64 {
+		m := gatherMetric(t, "db_query_duration_seconds", map[string]string{"table": "metrics_probes", "operation": operation})
+		if m == nil {
+			return 0
+		}
+		return m.GetHistogram().GetSampleCount()
+	}
+	before := map[string]uint64{}
+	for _, op := range []string{"create", "query", "update", "delete"} {
+		before[op] = countOf(op)
+	}
+
+	probe := &metricsProbe{ID: 1, Name: "a"}
+	require.NoError(t, gdb.Create(probe).Error)
+	require.NoError(t, gdb.First(&metricsProbe{}, 1).Error)
+	require.NoError(t, gdb.Model(probe).Update("name", "b").Error)
+	require.NoError(t, gdb.Delete(probe).Error)
+
+	for _, op := range []string{"create", "query", "update", "delete"} {
+		assert.Equal(t, before[op]+1, countOf(op), op)
+	}
+	assert.NotNil(t, gatherMetric(t, "db_query_duration_seconds", map[string]string{"table": "unknown", "operation": "raw"}),
+		"statements without a model")
+}
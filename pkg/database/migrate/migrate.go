@@ -0,0 +1,382 @@
+// Package migrate applies versioned SQL migrations to a database.
+//
+// Migrations are pairs of files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, usually embedded into the binary. Applied
+// versions are recorded in the schema_migrations table together with a
+// checksum of their up script, so that editing a migration after it has
+// shipped is detected instead of silently diverging between environments.
+// Every command runs under a database-wide lock, so replicas starting at the
+// same time apply each migration exactly once.
+//
+// A migration can be held back until the data allows it (see Migrator.Hold),
+// e.g. dropping a constraint only after a background job has backfilled its
+// replacement. Up stops in front of a held migration instead of failing.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrChecksumMismatch is returned when an applied migration's up script no
+// longer matches the checksum recorded when it was applied.
+var ErrChecksumMismatch = errors.New("migrate: applied migration has been modified")
+
+// ErrUnknownVersion is returned by Down and Redo when the database has a
+// version applied that the binary does not know, typically because a newer
+// build has already migrated it. Up and Check tolerate such versions so that
+// older replicas keep working during a rolling deploy.
+var ErrUnknownVersion = errors.New("migrate: database has a migration this binary does not know")
+
+// ErrPending is returned by Check when migrations are waiting to be applied.
+var ErrPending = errors.New("migrate: pending migrations")
+
+// ErrBlocked is returned by Up and Check when the next pending migration is
+// held back by its precondition (see Migrator.Hold). Nothing is wrong with
+// the database; the migration is applied by a later Up once the data allows.
+var ErrBlocked = errors.New("migrate: migration is held back")
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // hex SHA-256 of Up
+}
+
+// String returns the migration's file name stem, e.g. 000001_create_users.
+func (m Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+// AppliedMigration is a row of the schema_migrations table.
+type AppliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Status describes one migration as reported by Migrator.Status.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time // zero if not applied
+	Modified  bool      // applied, but the up script has changed since
+}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in the root of fsys, sorted by version.
+// Every version needs both an up and a down script.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migrate: %s: file name must look like 000001_create_users.up.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migrate: %s: invalid version", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Clean(entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: read %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d is used by both %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migrate: version %d (%s) needs both an up and a down script", m.Version, m.Name)
+		}
+		sum := sha256.Sum256([]byte(m.Up))
+		m.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Store is the database side of a Migrator. NewSQLStore implements it for
+// the supported SQL dialects.
+type Store interface {
+	// Lock blocks until the caller holds the migration lock of the database.
+	Lock(ctx context.Context) error
+	// Unlock releases the lock taken by Lock.
+	Unlock(ctx context.Context) error
+	// EnsureVersionTable creates the schema_migrations table if needed.
+	EnsureVersionTable(ctx context.Context) error
+	// Applied returns the recorded migrations, sorted by version.
+	Applied(ctx context.Context) ([]AppliedMigration, error)
+	// Apply runs m's up script and records m as applied.
+	Apply(ctx context.Context, m Migration) error
+	// Revert runs m's down script and removes its record.
+	Revert(ctx context.Context, m Migration) error
+	// Count runs query, which must return a single integer, e.g. a COUNT(*).
+	Count(ctx context.Context, query string) (int64, error)
+}
+
+// Migrator applies a fixed set of migrations through a Store.
+type Migrator struct {
+	store      Store
+	migrations []Migration
+	holds      map[int64]hold
+}
+
+// hold is the precondition of a migration registered with Migrator.Hold.
+type hold struct {
+	query  string
+	reason string
+}
+
+// New creates a Migrator for migrations, which must be sorted by version as
+// returned by Load.
+func New(store Store, migrations []Migration) *Migrator {
+	return &Migrator{store: store, migrations: migrations, holds: make(map[int64]hold)}
+}
+
+// Hold keeps the migration with the given version pending while countQuery
+// returns a non-zero count. Up stops in front of it, since later migrations
+// may depend on it, and reports ErrBlocked with reason.
+func (m *Migrator) Hold(version int64, countQuery, reason string) {
+	m.holds[version] = hold{query: countQuery, reason: reason}
+}
+
+// blocked returns an error wrapping ErrBlocked if migration is held back.
+func (m *Migrator) blocked(ctx context.Context, migration Migration) error {
+	h, ok := m.holds[migration.Version]
+	if !ok {
+		return nil
+	}
+	n, err := m.store.Count(ctx, h.query)
+	if err != nil {
+		return fmt.Errorf("migrate: check precondition of %s: %w", migration, err)
+	}
+	if n > 0 {
+		return fmt.Errorf("%w: %s: %s (%d remaining)", ErrBlocked, migration, h.reason, n)
+	}
+	return nil
+}
+
+// Up applies up to n pending migrations in version order, or all of them if
+// n <= 0. It returns the migrations that were applied; if it stopped in front
+// of a held migration, the error wraps ErrBlocked.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, true, func(applied map[int64]AppliedMigration) error {
+		for _, migration := range m.migrations {
+			if n > 0 && len(done) == n {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.blocked(ctx, migration); err != nil {
+				return err
+			}
+			if err := m.store.Apply(ctx, migration); err != nil {
+				return fmt.Errorf("migrate: apply %s: %w", migration, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the n most recently applied migrations (at least one).
+// It returns the migrations that were reverted.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n <= 0 {
+		n = 1
+	}
+	var done []Migration
+	err := m.locked(ctx, false, func(applied map[int64]AppliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < n; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.store.Revert(ctx, migration); err != nil {
+				return fmt.Errorf("migrate: revert %s: %w", migration, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Redo reverts and re-applies the most recently applied migration, which is
+// handy while writing one. It returns that migration, or nil if none is
+// applied.
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var redone *Migration
+	err := m.locked(ctx, false, func(applied map[int64]AppliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.store.Revert(ctx, migration); err != nil {
+				return fmt.Errorf("migrate: revert %s: %w", migration, err)
+			}
+			if err := m.store.Apply(ctx, migration); err != nil {
+				return fmt.Errorf("migrate: apply %s: %w", migration, err)
+			}
+			redone = &migration
+			return nil
+		}
+		return nil
+	})
+	return redone, err
+}
+
+// Status reports every known migration and whether it is applied. Unlike
+// the other commands it does not fail on modified migrations, so that they
+// can be inspected.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.store.EnsureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.store.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]AppliedMigration, len(applied))
+	for _, a := range applied {
+		byVersion[a.Version] = a
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if a, ok := byVersion[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = a.AppliedAt
+			status.Modified = a.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Check verifies, without taking the lock, that every known migration is
+// applied unmodified. It returns ErrPending if some are not applied yet, or
+// an error wrapping ErrBlocked if the only ones left are behind a held
+// migration, which Up could not apply either.
+func (m *Migrator) Check(ctx context.Context) error {
+	if err := m.store.EnsureVersionTable(ctx); err != nil {
+		return err
+	}
+	applied, err := m.store.Applied(ctx)
+	if err != nil {
+		return err
+	}
+	byVersion, err := m.verify(applied, true)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, migration := range m.migrations {
+		if _, ok := byVersion[migration.Version]; ok {
+			continue
+		}
+		if pending == 0 {
+			if err := m.blocked(ctx, migration); err != nil {
+				return err
+			}
+		}
+		pending++
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d not applied", ErrPending, pending)
+	}
+	return nil
+}
+
+// locked runs fn while holding the migration lock, after checking that the
+// applied migrations are consistent with the known ones.
+func (m *Migrator) locked(ctx context.Context, allowUnknown bool, fn func(applied map[int64]AppliedMigration) error) (err error) {
+	if err := m.store.Lock(ctx); err != nil {
+		return fmt.Errorf("migrate: acquire lock: %w", err)
+	}
+	defer func() {
+		// 用独立的 context 释放锁，避免调用方的 context 已取消导致锁无法释放
+		if unlockErr := m.store.Unlock(context.WithoutCancel(ctx)); unlockErr != nil && err == nil {
+			err = fmt.Errorf("migrate: release lock: %w", unlockErr)
+		}
+	}()
+
+	if err := m.store.EnsureVersionTable(ctx); err != nil {
+		return err
+	}
+	applied, err := m.store.Applied(ctx)
+	if err != nil {
+		return err
+	}
+	byVersion, err := m.verify(applied, allowUnknown)
+	if err != nil {
+		return err
+	}
+	return fn(byVersion)
+}
+
+// verify checks the applied migrations against the known ones and returns
+// the known applied migrations by version.
+func (m *Migrator) verify(applied []AppliedMigration, allowUnknown bool) (map[int64]AppliedMigration, error) {
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	byVersion := make(map[int64]AppliedMigration, len(applied))
+	for _, a := range applied {
+		migration, ok := known[a.Version]
+		if !ok {
+			if allowUnknown {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %06d_%s", ErrUnknownVersion, a.Version, a.Name)
+		}
+		if migration.Checksum != a.Checksum {
+			return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, migration)
+		}
+		byVersion[a.Version] = a
+	}
+	return byVersion, nil
+}
@@ -0,0 +1,287 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── Helper ───────────────────────────────────────────────────────────────────
+
+// fakeStore records the commands run against it instead of executing SQL.
+type fakeStore struct {
+	applied  map[int64]AppliedMigration
+	locked   bool
+	unlocks  int
+	log      []string
+	applyErr error
+	counts   map[string]int64 // Count 的返回值，按查询语句
+}
+
+func newFakeStore(applied ...Migration) *fakeStore {
+	s := &fakeStore{applied: make(map[int64]AppliedMigration)}
+	for _, m := range applied {
+		s.applied[m.Version] = AppliedMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum, AppliedAt: time.Now()}
+	}
+	return s
+}
+
+func (s *fakeStore) Lock(context.Context) error {
+	s.locked = true
+	return nil
+}
+
+func (s *fakeStore) Unlock(context.Context) error {
+	s.locked = false
+	s.unlocks++
+	return nil
+}
+
+func (s *fakeStore) EnsureVersionTable(context.Context) error { return nil }
+
+func (s *fakeStore) Applied(context.Context) ([]AppliedMigration, error) {
+	var applied []AppliedMigration
+	for _, a := range s.applied {
+		applied = append(applied, a)
+	}
+	return applied, nil
+}
+
+func (s *fakeStore) Apply(_ context.Context, m Migration) error {
+	if !s.locked {
+		return errors.New("apply without lock")
+	}
+	if s.applyErr != nil {
+		return s.applyErr
+	}
+	s.log = append(s.log, "up "+m.Name)
+	s.applied[m.Version] = AppliedMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum}
+	return nil
+}
+
+func (s *fakeStore) Count(_ context.Context, query string) (int64, error) {
+	return s.counts[query], nil
+}
+
+func (s *fakeStore) Revert(_ context.Context, m Migration) error {
+	if !s.locked {
+		return errors.New("revert without lock")
+	}
+	s.log = append(s.log, "down "+m.Name)
+	delete(s.applied, m.Version)
+	return nil
+}
+
+func testMigrations(t *testing.T) []Migration {
+	t.Helper()
+	migrations, err := Load(fstest.MapFS{
+		"000001_create_users.up.sql":      {Data: []byte("CREATE TABLE users (id BIGINT);")},
+		"000001_create_users.down.sql":    {Data: []byte("DROP TABLE users;")},
+		"000002_create_posts.up.sql":      {Data: []byte("CREATE TABLE posts (id BIGINT);")},
+		"000002_create_posts.down.sql":    {Data: []byte("DROP TABLE posts;")},
+		"000003_add_users_phone.up.sql":   {Data: []byte("ALTER TABLE users ADD phone TEXT;")},
+		"000003_add_users_phone.down.sql": {Data: []byte("ALTER TABLE users DROP phone;")},
+	})
+	require.NoError(t, err)
+	return migrations
+}
+
+// ─── Load ─────────────────────────────────────────────────────────────────────
+
+func TestLoad_SortsAndChecksums(t *testing.T) {
+	migrations := testMigrations(t)
+
+	require.Len(t, migrations, 3)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_users", migrations[0].Name)
+	assert.Equal(t, "add_users_phone", migrations[2].Name)
+	assert.Len(t, migrations[0].Checksum, 64)
+	assert.NotEqual(t, migrations[0].Checksum, migrations[1].Checksum)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"missing down", fstest.MapFS{
+			"000001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id BIGINT);")},
+		}},
+		{"bad file name", fstest.MapFS{
+			"create_users.sql": {Data: []byte("CREATE TABLE users (id BIGINT);")},
+		}},
+		{"version reused", fstest.MapFS{
+			"000001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id BIGINT);")},
+			"000001_create_posts.down.sql": {Data: []byte("DROP TABLE posts;")},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+// ─── Migrator ─────────────────────────────────────────────────────────────────
+
+func TestMigrator_UpAppliesPendingInOrder(t *testing.T) {
+	migrations := testMigrations(t)
+	store := newFakeStore(migrations[0])
+
+	applied, err := New(store, migrations).Up(context.Background(), 0)
+
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+	assert.Equal(t, []string{"up create_posts", "up add_users_phone"}, store.log)
+	assert.False(t, store.locked)
+	assert.Equal(t, 1, store.unlocks)
+}
+
+func TestMigrator_UpLimitedToN(t *testing.T) {
+	migrations := testMigrations(t)
+	store := newFakeStore()
+
+	_, err := New(store, migrations).Up(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"up create_users"}, store.log)
+}
+
+func TestMigrator_RefusesModifiedMigration(t *testing.T) {
+	migrations := testMigrations(t)
+	modified := migrations[0]
+	modified.Checksum = "stale"
+	store := newFakeStore(modified)
+
+	_, err := New(store, migrations).Up(context.Background(), 0)
+
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Empty(t, store.log)
+	assert.False(t, store.locked, "lock must be released on failure")
+}
+
+func TestMigrator_UnknownVersion(t *testing.T) {
+	migrations := testMigrations(t)
+	// 数据库已由更新的版本迁移过
+	newer := Migration{Version: 4, Name: "from_newer_build", Checksum: "x"}
+	store := newFakeStore(migrations[0], migrations[1], migrations[2], newer)
+	migrator := New(store, migrations)
+
+	_, err := migrator.Up(context.Background(), 0)
+	assert.NoError(t, err, "older replicas must still start")
+
+	_, err = migrator.Down(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+	assert.Empty(t, store.log)
+}
+
+func TestMigrator_ApplyFailureStops(t *testing.T) {
+	migrations := testMigrations(t)
+	store := newFakeStore()
+	store.applyErr = errors.New("syntax error")
+
+	applied, err := New(store, migrations).Up(context.Background(), 0)
+
+	assert.ErrorContains(t, err, "000001")
+	assert.ErrorContains(t, err, "syntax error")
+	assert.Empty(t, applied)
+	assert.False(t, store.locked)
+}
+
+func TestMigrator_DownRevertsLatest(t *testing.T) {
+	migrations := testMigrations(t)
+	store := newFakeStore(migrations...)
+
+	reverted, err := New(store, migrations).Down(context.Background(), 2)
+
+	require.NoError(t, err)
+	assert.Len(t, reverted, 2)
+	assert.Equal(t, []string{"down add_users_phone", "down create_posts"}, store.log)
+	assert.Contains(t, store.applied, int64(1))
+}
+
+func TestMigrator_Redo(t *testing.T) {
+	migrations := testMigrations(t)
+	store := newFakeStore(migrations[0], migrations[1])
+
+	redone, err := New(store, migrations).Redo(context.Background())
+
+	require.NoError(t, err)
+	require.NotNil(t, redone)
+	assert.Equal(t, "create_posts", redone.Name)
+	assert.Equal(t, []string{"down create_posts", "up create_posts"}, store.log)
+}
+
+func TestMigrator_Status(t *testing.T) {
+	migrations := testMigrations(t)
+	modified := migrations[1]
+	modified.Checksum = "stale"
+	store := newFakeStore(migrations[0], modified)
+
+	statuses, err := New(store, migrations).Status(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[0].Modified)
+	assert.True(t, statuses[1].Modified)
+	assert.False(t, statuses[2].Applied)
+}
+
+func TestMigrator_Check(t *testing.T) {
+	migrations := testMigrations(t)
+
+	err := New(newFakeStore(migrations[0]), migrations).Check(context.Background())
+	assert.ErrorIs(t, err, ErrPending)
+
+	err = New(newFakeStore(migrations...), migrations).Check(context.Background())
+	assert.NoError(t, err)
+}
+
+// ─── Hold ─────────────────────────────────────────────────────────────────────
+
+func TestMigrator_HoldStopsUpInFrontOfMigration(t *testing.T) {
+	migrations := testMigrations(t)
+	store := newFakeStore()
+	store.counts = map[string]int64{"SELECT COUNT(*) FROM posts": 3}
+	migrator := New(store, migrations)
+	migrator.Hold(2, "SELECT COUNT(*) FROM posts", "posts are still being copied")
+
+	done, err := migrator.Up(context.Background(), 0)
+	require.ErrorIs(t, err, ErrBlocked)
+	assert.Contains(t, err.Error(), "000002_create_posts: posts are still being copied (3 remaining)")
+	require.Len(t, done, 1)
+	// 后续迁移可能依赖被挡住的迁移，同样不执行
+	assert.Equal(t, []string{"up create_users"}, store.log)
+	assert.Equal(t, 1, store.unlocks)
+
+	err = migrator.Check(context.Background())
+	assert.ErrorIs(t, err, ErrBlocked, "nothing that Up could apply is pending")
+	assert.NotErrorIs(t, err, ErrPending)
+
+	store.counts = nil
+	done, err = migrator.Up(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Len(t, done, 2)
+	assert.NoError(t, migrator.Check(context.Background()))
+}
+
+func TestMigrator_CheckReportsPendingBeforeHold(t *testing.T) {
+	migrations := testMigrations(t)
+	store := newFakeStore()
+	store.counts = map[string]int64{"SELECT 1": 1}
+	migrator := New(store, migrations)
+	migrator.Hold(2, "SELECT 1", "held")
+
+	// 000001 未被挡住，Up 可以执行它
+	err := migrator.Check(context.Background())
+	assert.ErrorIs(t, err, ErrPending)
+}
@@ -0,0 +1,94 @@
+package migrate
+
+import "strings"
+
+// splitStatements splits a script into its statements on semicolons outside
+// of string literals, quoted identifiers, comments and Postgres dollar-quoted
+// bodies. Statements are executed one at a time because neither driver runs
+// multi-statement scripts by default.
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		start      int
+	)
+	flush := func(end int) {
+		if statement := strings.TrimSpace(script[start:end]); statement != "" && !onlyComments(statement) {
+			statements = append(statements, statement)
+		}
+	}
+
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(script, i, c)
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			i = skipUntil(script, i, "\n")
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = skipUntil(script, i+2, "*/")
+		case c == '$':
+			if tag, ok := dollarTag(script[i:]); ok {
+				i = skipUntil(script, i+len(tag), tag)
+			}
+		case c == ';':
+			flush(i)
+			start = i + 1
+		}
+	}
+	flush(len(script))
+	return statements
+}
+
+// skipQuoted returns the index of the quote closing the literal opened at i.
+// A doubled quote inside the literal is an escaped quote.
+func skipQuoted(script string, i int, quote byte) int {
+	for j := i + 1; j < len(script); j++ {
+		if script[j] == quote {
+			if j+1 < len(script) && script[j+1] == quote {
+				j++
+				continue
+			}
+			return j
+		}
+	}
+	return len(script) - 1
+}
+
+// skipUntil returns the index of the last byte of the first occurrence of end
+// at or after i, or the end of the script.
+func skipUntil(script string, i int, end string) int {
+	if j := strings.Index(script[i:], end); j >= 0 {
+		return i + j + len(end) - 1
+	}
+	return len(script) - 1
+}
+
+// dollarTag returns the dollar-quote tag ($$ or $name$) that s starts with.
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		if c == '$' {
+			return s[:j+1], true
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > 1 && c >= '0' && c <= '9') {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// onlyComments reports whether statement consists of comments only.
+func onlyComments(statement string) bool {
+	for {
+		statement = strings.TrimSpace(statement)
+		switch {
+		case statement == "":
+			return true
+		case strings.HasPrefix(statement, "--"):
+			_, statement, _ = strings.Cut(statement, "\n")
+		case strings.HasPrefix(statement, "/*"):
+			_, statement, _ = strings.Cut(statement, "*/")
+		default:
+			return false
+		}
+	}
+}
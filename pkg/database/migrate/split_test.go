@@ -0,0 +1,50 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "plain statements",
+			script: "CREATE TABLE a (id INT);\n\nCREATE INDEX idx ON a (id);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE INDEX idx ON a (id)"},
+		},
+		{
+			name:   "semicolon in string literal",
+			script: "INSERT INTO a VALUES ('x;y', 'it''s');",
+			want:   []string{"INSERT INTO a VALUES ('x;y', 'it''s')"},
+		},
+		{
+			name:   "comments",
+			script: "-- create; the table\nCREATE TABLE a (id INT); /* trailing; */\n-- only a comment\n",
+			want:   []string{"-- create; the table\nCREATE TABLE a (id INT)"},
+		},
+		{
+			name:   "dollar-quoted body",
+			script: "CREATE FUNCTION f() RETURNS void AS $body$ BEGIN PERFORM 1; END $body$ LANGUAGE plpgsql;\nSELECT 1;",
+			want: []string{
+				"CREATE FUNCTION f() RETURNS void AS $body$ BEGIN PERFORM 1; END $body$ LANGUAGE plpgsql",
+				"SELECT 1",
+			},
+		},
+		{
+			name:   "backtick identifiers",
+			script: "CREATE TABLE `a;b` (`key` INT)",
+			want:   []string{"CREATE TABLE `a;b` (`key` INT)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitStatements(tt.script))
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// dialect holds the SQL that differs between the supported databases.
+type dialect struct {
+	createTable string
+	lock        string
+	unlock      string
+	insert      string
+	delete      string
+	// transactionalDDL reports whether schema changes can be rolled back.
+	// MySQL commits implicitly around every DDL statement.
+	transactionalDDL bool
+}
+
+// lockKey identifies the migration lock among the application's Postgres
+// advisory locks.
+const lockKey = 7_354_120_118
+
+var dialects = map[string]dialect{
+	"postgres": {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+		)`,
+		// pg_advisory_lock 返回 void，放在 FROM 中使查询返回一行 1，与 GET_LOCK 的成功结果一致
+		lock:             fmt.Sprintf("SELECT 1 FROM pg_advisory_lock(%d)", lockKey),
+		unlock:           fmt.Sprintf("SELECT pg_advisory_unlock(%d)", lockKey),
+		insert:           "INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+		delete:           "DELETE FROM schema_migrations WHERE version = $1",
+		transactionalDDL: true,
+	},
+	"mysql": {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)
+		) ENGINE=InnoDB`,
+		// GET_LOCK 的锁名在整个 MySQL 实例内共享，加上库名避免不同库的迁移互相阻塞；超时 -1 表示一直等待
+		lock:   "SELECT GET_LOCK(CONCAT(DATABASE(), '.schema_migrations'), -1)",
+		unlock: "SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.schema_migrations'))",
+		insert: "INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+		delete: "DELETE FROM schema_migrations WHERE version = ?",
+	},
+	"sqlite": {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		// SQLite 没有会话级咨询锁；数据库文件只属于单个进程，且每次迁移在各自的事务中执行，无需额外加锁
+		lock:             "SELECT 1",
+		unlock:           "SELECT 1",
+		insert:           "INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+		delete:           "DELETE FROM schema_migrations WHERE version = ?",
+		transactionalDDL: true,
+	},
+}
+
+// SQLStore is the Store of a Postgres, MySQL or SQLite database.
+//
+// The migration lock is a session-level advisory lock, so the store pins one
+// connection from Lock until Unlock and runs everything in between on it.
+type SQLStore struct {
+	db      *sql.DB
+	dialect dialect
+	conn    *sql.Conn
+}
+
+// NewSQLStore creates a Store for db. dialectName is "postgres", "mysql" or
+// "sqlite".
+func NewSQLStore(db *sql.DB, dialectName string) (*SQLStore, error) {
+	d, ok := dialects[dialectName]
+	if !ok {
+		return nil, fmt.Errorf("migrate: unsupported dialect %q", dialectName)
+	}
+	return &SQLStore{db: db, dialect: d}, nil
+}
+
+// Lock pins a connection and takes the migration lock on it.
+func (s *SQLStore) Lock(ctx context.Context) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, s.dialect.lock).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	if acquired.Int64 != 1 {
+		_ = conn.Close()
+		return errors.New("lock not granted")
+	}
+	s.conn = conn
+	return nil
+}
+
+// Unlock releases the migration lock and returns the pinned connection.
+func (s *SQLStore) Unlock(ctx context.Context) error {
+	if s.conn == nil {
+		return nil
+	}
+	conn := s.conn
+	s.conn = nil
+	_, err := conn.ExecContext(ctx, s.dialect.unlock)
+	if closeErr := conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// EnsureVersionTable creates schema_migrations if it does not exist.
+func (s *SQLStore) EnsureVersionTable(ctx context.Context) error {
+	_, err := s.execer().ExecContext(ctx, s.dialect.createTable)
+	return err
+}
+
+// Applied returns the recorded migrations, sorted by version.
+func (s *SQLStore) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	rows, err := s.execer().QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied = append(applied, a)
+	}
+	return applied, rows.Err()
+}
+
+// Apply runs m's up script and records it, atomically where the database
+// supports transactional DDL.
+func (s *SQLStore) Apply(ctx context.Context, m Migration) error {
+	return s.run(ctx, m.Up, s.dialect.insert, m.Version, m.Name, m.Checksum)
+}
+
+// Count runs query and scans its single integer result.
+func (s *SQLStore) Count(ctx context.Context, query string) (int64, error) {
+	var n int64
+	err := s.execer().QueryRowContext(ctx, query).Scan(&n)
+	return n, err
+}
+
+// Revert runs m's down script and removes its record.
+func (s *SQLStore) Revert(ctx context.Context, m Migration) error {
+	return s.run(ctx, m.Down, s.dialect.delete, m.Version)
+}
+
+// run executes script followed by the bookkeeping statement record.
+func (s *SQLStore) run(ctx context.Context, script, record string, args ...any) error {
+	statements := splitStatements(script)
+	if !s.dialect.transactionalDDL {
+		// MySQL 的 DDL 会隐式提交，事务没有意义；失败时需人工处理已执行的部分
+		for _, statement := range statements {
+			if _, err := s.execer().ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+		_, err := s.execer().ExecContext(ctx, record, args...)
+		return err
+	}
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqlExecutor is the common part of *sql.DB and *sql.Conn.
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// execer returns the pinned connection while the lock is held, else the pool.
+func (s *SQLStore) execer() sqlExecutor {
+	if s.conn != nil {
+		return s.conn
+	}
+	return s.db
+}
+
+func (s *SQLStore) beginTx(ctx context.Context) (*sql.Tx, error) {
+	if s.conn != nil {
+		return s.conn.BeginTx(ctx, nil)
+	}
+	return s.db.BeginTx(ctx, nil)
+}
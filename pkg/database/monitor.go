@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/logger"
+)
+
+var (
+	// dbUp reports whether the last ping of a connection succeeded
+	dbUp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "db_up",
+			Help: "Whether the last health check ping of the database succeeded (1) or failed (0).",
+		},
+		[]string{"db"},
+	)
+
+	// connectionLost counts the times a healthy connection stopped answering
+	connectionLost = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_connection_lost_total",
+			Help: "Total number of times the database stopped answering health check pings.",
+		},
+		[]string{"db"},
+	)
+
+	// reconnected counts the times the pool got through to the server again
+	reconnected = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_reconnected_total",
+			Help: "Total number of times the database answered health check pings again after failing them.",
+		},
+		[]string{"db"},
+	)
+)
+
+// ConnectionMonitor pings a connected database to report whether it is
+// reachable. The connection pool of database/sql reconnects by itself when
+// the server comes back; the monitor makes losing and regaining the server
+// visible in the logs and in the db_up, db_connection_lost_total and
+// db_reconnected_total metrics.
+type ConnectionMonitor struct {
+	db   Database
+	name string
+
+	mu   sync.Mutex
+	down bool
+}
+
+// NewConnectionMonitor creates a ConnectionMonitor for db, labelled
+// db=name ("primary" if empty) in metrics.
+func NewConnectionMonitor(db Database, name string) *ConnectionMonitor {
+	if name == "" {
+		name = "primary"
+	}
+	return &ConnectionMonitor{db: db, name: name}
+}
+
+// Check pings the database and records the result. It returns the ping
+// error. It is meant to be run periodically as a job.
+func (m *ConnectionMonitor) Check(ctx context.Context) error {
+	err := ping(ctx, m.db)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	log := logger.FromContext(ctx)
+	switch {
+	case err != nil && !m.down:
+		connectionLost.WithLabelValues(m.name).Inc()
+		log.Warnf("lost connection to database %s: %v", m.name, err)
+	case err == nil && m.down:
+		reconnected.WithLabelValues(m.name).Inc()
+		log.Infof("reconnected to database %s", m.name)
+	}
+	m.down = err != nil
+	if m.down {
+		dbUp.WithLabelValues(m.name).Set(0)
+	} else {
+		dbUp.WithLabelValues(m.name).Set(1)
+	}
+	return err
+}
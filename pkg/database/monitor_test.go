@@ -0,0 +1,46 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+)
+
+func TestConnectionMonitor(t *testing.T) {
+	live := sqlite.NewSQLiteDB()
+	require.NoError(t, live.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	t.Cleanup(func() { _ = live.Close() })
+	dead := sqlite.NewSQLiteDB()
+	require.NoError(t, dead.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	require.NoError(t, dead.Close())
+
+	// 模拟服务端断开后恢复
+	db := &switchableDB{Database: live}
+	monitor := database.NewConnectionMonitor(db, "monitor-test")
+	labels := map[string]string{"db": "monitor-test"}
+	ctx := context.Background()
+
+	require.NoError(t, monitor.Check(ctx))
+	assert.Equal(t, 1.0, gatherMetric(t, "db_up", labels).GetGauge().GetValue())
+
+	db.Database = dead
+	assert.Error(t, monitor.Check(ctx))
+	assert.Error(t, monitor.Check(ctx))
+	assert.Equal(t, 0.0, gatherMetric(t, "db_up", labels).GetGauge().GetValue())
+	assert.Equal(t, 1.0, counterValue(t, "db_connection_lost_total", labels), "counted once per outage")
+
+	db.Database = live
+	require.NoError(t, monitor.Check(ctx))
+	assert.Equal(t, 1.0, gatherMetric(t, "db_up", labels).GetGauge().GetValue())
+	assert.Equal(t, 1.0, counterValue(t, "db_reconnected_total", labels))
+}
+
+// switchableDB delegates to a Database that the test can swap.
+type switchableDB struct {
+	database.Database
+}
@@ -1,9 +1,18 @@
 package mysql
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"os"
+	"strconv"
 	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
@@ -19,28 +28,165 @@ func NewMySQLDB() database.Database {
 }
 
 func (m *MySQLDB) Connect(config *database.Config) error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=UTC",
-		config.User, config.Password, config.Host, config.Port, config.DBName)
+	dialector := mysql.Open(config.DSN)
+	if config.DSN == "" {
+		// 自定义 TLS 配置无法写进 DSN 字符串，通过 Connector 打开连接池
+		dsnConfig, err := newDSNConfig(config)
+		if err != nil {
+			return fmt.Errorf("invalid database configuration: %w", err)
+		}
+		connector, err := mysqldriver.NewConnector(dsnConfig)
+		if err != nil {
+			return fmt.Errorf("invalid database configuration: %w", err)
+		}
+		dialector = mysql.New(mysql.Config{Conn: sql.OpenDB(connector), DSNConfig: dsnConfig})
+	}
 
-	var err error
-	m.db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: database.NewGormLogger(config)})
 	if err != nil {
+		database.Discard(db)
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
+	m.db = db
 
 	sqlDB, err := m.db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance for pooling config: %w", err)
 	}
+	if err := database.Instrument(m.db, config.Name); err != nil {
+		return err
+	}
 
 	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetimeMinutes) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(config.ConnMaxIdleTimeMinutes) * time.Minute)
 
 	return nil
 }
 
+// newDSNConfig returns the driver configuration of config. Params are sent
+// as system variables (SET key=value), so string values must be quoted.
+func newDSNConfig(config *database.Config) (*mysqldriver.Config, error) {
+	dsnConfig := mysqldriver.NewConfig()
+	dsnConfig.User = config.User
+	dsnConfig.Passwd = config.Password
+	dsnConfig.Net = "tcp"
+	dsnConfig.Addr = net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	dsnConfig.DBName = config.DBName
+	dsnConfig.ParseTime = true
+	dsnConfig.Loc = time.UTC
+	dsnConfig.Timeout = config.ConnectTimeout
+	if err := dsnConfig.Apply(mysqldriver.Charset("utf8mb4", "")); err != nil {
+		return nil, err
+	}
+
+	dsnConfig.Params = maps.Clone(config.Params)
+	if config.StatementTimeout > 0 {
+		if dsnConfig.Params == nil {
+			dsnConfig.Params = make(map[string]string)
+		}
+		dsnConfig.Params["max_execution_time"] = strconv.FormatInt(config.StatementTimeout.Milliseconds(), 10)
+	}
+	if config.ApplicationName != "" {
+		dsnConfig.ConnectionAttributes = "program_name:" + config.ApplicationName
+	}
+
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dsnConfig.TLS = tlsConfig
+	// allow / prefer：服务端不支持 TLS 时使用明文连接
+	dsnConfig.AllowFallbackToPlaintext = config.SSLMode == "allow" || config.SSLMode == "prefer"
+	return dsnConfig, nil
+}
+
+// newTLSConfig maps config.SSLMode to a TLS configuration with the libpq
+// semantics: allow, prefer and require encrypt without verifying the server
+// (require verifies its certificate when a CA is given), verify-ca verifies
+// the certificate, and verify-full its host name too. It returns nil for
+// disable.
+func newTLSConfig(config *database.Config) (*tls.Config, error) {
+	verify := ""
+	switch config.SSLMode {
+	case "", "disable":
+		return nil, nil
+	case "allow", "prefer":
+	case "require":
+		if config.TLS.CAFile != "" {
+			verify = "verify-ca"
+		}
+	case "verify-ca", "verify-full":
+		verify = config.SSLMode
+	default:
+		return nil, fmt.Errorf("unsupported SSL mode %q", config.SSLMode)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.TLS.CertFile != "" || config.TLS.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.TLS.CertFile, config.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if config.TLS.CAFile != "" {
+		pem, err := os.ReadFile(config.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", config.TLS.CAFile)
+		}
+	}
+
+	switch verify {
+	case "":
+		tlsConfig.InsecureSkipVerify = true
+	case "verify-ca":
+		// 只校验证书链，不校验主机名
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyChain(tlsConfig.RootCAs)
+	case "verify-full":
+		tlsConfig.ServerName = config.TLS.ServerName
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = config.Host
+		}
+	}
+	return tlsConfig, nil
+}
+
+// verifyChain returns a VerifyPeerCertificate function checking that the
+// server certificate chains to roots (the system roots if nil), whatever
+// its host name.
+func verifyChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("server sent no certificate")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs[i] = cert
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+		return err
+	}
+}
+
 func (m *MySQLDB) Close() error {
+	if m.db == nil {
+		return nil // 从未连接成功
+	}
 	sqlDB, err := m.db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
@@ -0,0 +1,133 @@
+package mysql
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+// writeCertificate writes a self-signed certificate for host and its key as
+// PEM files, and returns their paths and the DER certificate.
+func writeCertificate(t *testing.T, host string) (certFile, keyFile string, der []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: host},
+		DNSNames:              []string{host},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err = x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile, der
+}
+
+func TestNewDSNConfig(t *testing.T) {
+	dsnConfig, err := newDSNConfig(&database.Config{
+		Host:             "db.example.com",
+		Port:             3306,
+		User:             "app",
+		Password:         "secret",
+		DBName:           "shop",
+		ApplicationName:  "boot-backend",
+		StatementTimeout: 5 * time.Second,
+		ConnectTimeout:   3 * time.Second,
+		Params:           map[string]string{"sql_mode": "'TRADITIONAL'"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "db.example.com:3306", dsnConfig.Addr)
+	assert.Equal(t, "shop", dsnConfig.DBName)
+	assert.True(t, dsnConfig.ParseTime)
+	assert.Equal(t, time.UTC, dsnConfig.Loc)
+	assert.Equal(t, 3*time.Second, dsnConfig.Timeout)
+	assert.Equal(t, "program_name:boot-backend", dsnConfig.ConnectionAttributes)
+	assert.Equal(t, map[string]string{"sql_mode": "'TRADITIONAL'", "max_execution_time": "5000"}, dsnConfig.Params)
+	assert.Nil(t, dsnConfig.TLS, "TLS is off unless SSLMode asks for it")
+	assert.False(t, dsnConfig.AllowFallbackToPlaintext)
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	certFile, keyFile, der := writeCertificate(t, "db.example.com")
+
+	t.Run("modes", func(t *testing.T) {
+		tlsConfig, err := newTLSConfig(&database.Config{SSLMode: "disable"})
+		require.NoError(t, err)
+		assert.Nil(t, tlsConfig)
+
+		dsnConfig, err := newDSNConfig(&database.Config{Host: "db", Port: 3306, SSLMode: "prefer"})
+		require.NoError(t, err)
+		require.NotNil(t, dsnConfig.TLS)
+		assert.True(t, dsnConfig.TLS.InsecureSkipVerify, "prefer only encrypts")
+		assert.True(t, dsnConfig.AllowFallbackToPlaintext)
+
+		tlsConfig, err = newTLSConfig(&database.Config{SSLMode: "require"})
+		require.NoError(t, err)
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+		assert.Nil(t, tlsConfig.VerifyPeerCertificate)
+
+		tlsConfig, err = newTLSConfig(&database.Config{Host: "10.0.0.5", SSLMode: "verify-full"})
+		require.NoError(t, err)
+		assert.False(t, tlsConfig.InsecureSkipVerify)
+		assert.Equal(t, "10.0.0.5", tlsConfig.ServerName)
+
+		_, err = newTLSConfig(&database.Config{SSLMode: "on"})
+		assert.Error(t, err)
+	})
+
+	t.Run("certificates", func(t *testing.T) {
+		tlsConfig, err := newTLSConfig(&database.Config{
+			Host:    "10.0.0.5",
+			SSLMode: "verify-full",
+			TLS:     database.TLSConfig{CAFile: certFile, CertFile: certFile, KeyFile: keyFile, ServerName: "db.example.com"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "db.example.com", tlsConfig.ServerName)
+		assert.Len(t, tlsConfig.Certificates, 1)
+		require.NotNil(t, tlsConfig.RootCAs)
+
+		_, err = newTLSConfig(&database.Config{SSLMode: "verify-ca", TLS: database.TLSConfig{CAFile: keyFile}})
+		assert.Error(t, err, "no certificate in the CA file")
+		_, err = newTLSConfig(&database.Config{SSLMode: "require", TLS: database.TLSConfig{CertFile: certFile}})
+		assert.Error(t, err, "client certificate without its key")
+	})
+
+	t.Run("verify-ca checks the chain but not the host name", func(t *testing.T) {
+		// libpq 语义：require 配合 CA 时等同于 verify-ca
+		tlsConfig, err := newTLSConfig(&database.Config{Host: "10.0.0.5", SSLMode: "require", TLS: database.TLSConfig{CAFile: certFile}})
+		require.NoError(t, err)
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+		require.NotNil(t, tlsConfig.VerifyPeerCertificate)
+		assert.NoError(t, tlsConfig.VerifyPeerCertificate([][]byte{der}, nil))
+
+		_, _, otherDER := writeCertificate(t, "db.example.com")
+		assert.Error(t, tlsConfig.VerifyPeerCertificate([][]byte{otherDER}, nil), "signed by another CA")
+		assert.Error(t, tlsConfig.VerifyPeerCertificate(nil, nil))
+	})
+}
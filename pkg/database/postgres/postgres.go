@@ -2,8 +2,13 @@ package postgres
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
@@ -19,28 +24,115 @@ func NewPostgresDB() database.Database {
 }
 
 func (p *PostgresDB) Connect(config *database.Config) error {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=UTC",
-		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode)
+	dsn := config.DSN
+	if dsn == "" {
+		dsn = buildDSN(config)
+	}
+	dialector := postgres.Open(dsn)
+	if config.DSN == "" && config.TLS.ServerName != "" {
+		// pgx 总是以 host 校验服务端证书，需要在解析后的配置上改写 ServerName
+		connConfig, err := pgx.ParseConfig(dsn)
+		if err != nil {
+			return fmt.Errorf("invalid database configuration: %w", err)
+		}
+		setServerName(connConfig, config.TLS.ServerName)
+		dialector = postgres.New(postgres.Config{Conn: stdlib.OpenDB(*connConfig)})
+	}
 
-	var err error
-	p.db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: database.NewGormLogger(config)})
 	if err != nil {
+		database.Discard(db)
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
+	p.db = db
 
 	sqlDB, err := p.db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance for pooling config: %w", err)
 	}
+	if err := database.Instrument(p.db, config.Name); err != nil {
+		return err
+	}
 
 	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetimeMinutes) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(config.ConnMaxIdleTimeMinutes) * time.Minute)
 
 	return nil
 }
 
+// buildDSN returns the keyword/value connection string of config. Params
+// cannot override the settings that have a field of their own.
+func buildDSN(config *database.Config) string {
+	settings := make(map[string]string, len(config.Params)+12)
+	for key, value := range config.Params {
+		settings[key] = value
+	}
+	settings["host"] = config.Host
+	settings["port"] = strconv.Itoa(config.Port)
+	settings["user"] = config.User
+	settings["password"] = config.Password
+	settings["dbname"] = config.DBName
+	settings["TimeZone"] = "UTC"
+	optional := map[string]string{
+		"sslmode":          config.SSLMode,
+		"sslrootcert":      config.TLS.CAFile,
+		"sslcert":          config.TLS.CertFile,
+		"sslkey":           config.TLS.KeyFile,
+		"application_name": config.ApplicationName,
+	}
+	if config.ConnectTimeout > 0 {
+		// connect_timeout 以秒为单位，不足一秒按一秒计
+		optional["connect_timeout"] = strconv.Itoa(int((config.ConnectTimeout + time.Second - 1) / time.Second))
+	}
+	if config.StatementTimeout > 0 {
+		optional["statement_timeout"] = strconv.FormatInt(config.StatementTimeout.Milliseconds(), 10)
+	}
+	for key, value := range optional {
+		if value != "" {
+			settings[key] = value
+		}
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+quote(settings[key]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// quote quotes a keyword/value connection string value when needed. Plain
+// values stay unquoted: gorm reads TimeZone from the DSN as is.
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '=\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// setServerName makes TLS connections, including the fallbacks of the
+// allow and prefer modes, expect serverName in the server certificate.
+func setServerName(connConfig *pgx.ConnConfig, serverName string) {
+	if connConfig.TLSConfig != nil {
+		connConfig.TLSConfig.ServerName = serverName
+	}
+	for _, fallback := range connConfig.Fallbacks {
+		if fallback.TLSConfig != nil {
+			fallback.TLSConfig.ServerName = serverName
+		}
+	}
+}
+
 func (p *PostgresDB) Close() error {
+	if p.db == nil {
+		return nil // 从未连接成功
+	}
 	sqlDB, err := p.db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
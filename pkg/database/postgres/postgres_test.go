@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+func TestBuildDSN(t *testing.T) {
+	config := &database.Config{
+		Host:             "db.example.com",
+		Port:             5432,
+		User:             "app",
+		Password:         `it's a \secret`,
+		DBName:           "shop",
+		SSLMode:          "disable",
+		ApplicationName:  "boot backend",
+		StatementTimeout: 5 * time.Second,
+		ConnectTimeout:   1500 * time.Millisecond,
+		Params:           map[string]string{"search_path": "shop", "dbname": "other"},
+	}
+
+	dsn := buildDSN(config)
+	assert.Contains(t, dsn, "TimeZone=UTC", "unquoted, gorm reads it from the DSN")
+	assert.Contains(t, dsn, "connect_timeout=2", "rounded up to seconds")
+
+	// 解析结果与配置一致，说明引号与转义正确
+	parsed, err := pgx.ParseConfig(dsn)
+	require.NoError(t, err)
+	assert.Equal(t, "db.example.com", parsed.Host)
+	assert.Equal(t, uint16(5432), parsed.Port)
+	assert.Equal(t, `it's a \secret`, parsed.Password)
+	assert.Equal(t, "shop", parsed.Database, "params do not override settings")
+	assert.Equal(t, "boot backend", parsed.RuntimeParams["application_name"])
+	assert.Equal(t, "5000", parsed.RuntimeParams["statement_timeout"])
+	assert.Equal(t, "shop", parsed.RuntimeParams["search_path"])
+	assert.Equal(t, 2*time.Second, parsed.ConnectTimeout)
+}
+
+func TestBuildDSN_TLS(t *testing.T) {
+	// pgx 解析时即读取证书文件，这里只检查参数
+	dsn := buildDSN(&database.Config{
+		Host:    "10.0.0.5",
+		SSLMode: "verify-full",
+		TLS: database.TLSConfig{
+			CAFile:     "/certs/ca.pem",
+			CertFile:   "/certs/client.pem",
+			KeyFile:    "/certs/client.key",
+			ServerName: "db.example.com",
+		},
+	})
+	assert.Contains(t, dsn, "sslmode=verify-full")
+	assert.Contains(t, dsn, "sslrootcert=/certs/ca.pem")
+	assert.Contains(t, dsn, "sslcert=/certs/client.pem")
+	assert.Contains(t, dsn, "sslkey=/certs/client.key")
+	assert.NotContains(t, dsn, "db.example.com", "not a libpq parameter")
+
+	connConfig, err := pgx.ParseConfig("host=10.0.0.5 sslmode=prefer")
+	require.NoError(t, err)
+	setServerName(connConfig, "db.example.com")
+	assert.Equal(t, "db.example.com", connConfig.TLSConfig.ServerName)
+	require.Len(t, connConfig.Fallbacks, 1)
+	assert.Nil(t, connConfig.Fallbacks[0].TLSConfig, "plaintext fallback stays plaintext")
+}
+
+func TestBuildDSN_Defaults(t *testing.T) {
+	dsn := buildDSN(&database.Config{Host: "localhost", Port: 5432, User: "postgres", Password: "password", DBName: "app", SSLMode: "disable"})
+	assert.Equal(t, "TimeZone=UTC dbname=app host=localhost password=password port=5432 sslmode=disable user=postgres", dsn)
+}
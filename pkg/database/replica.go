@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// pingTimeout bounds each health check ping.
+const pingTimeout = 2 * time.Second
+
+// ReplicatedDB is a Database made of a primary and any number of read
+// replicas. DB returns the primary, which serves every write and every
+// transaction; Reader spreads read queries round-robin over the replicas that
+// passed their last health check and falls back to the primary when none did.
+//
+// Replicas lag behind the primary, so callers that must see their own writes
+// read from the primary instead (see repository.WithPrimary).
+type ReplicatedDB struct {
+	primary    Database
+	newReplica func() Database
+	dsns       []string
+	replicas   []*replica
+	next       atomic.Uint64
+}
+
+type replica struct {
+	db      Database
+	healthy atomic.Bool
+}
+
+// NewReplicatedDB creates a ReplicatedDB. Connect connects primary and then
+// one replica per DSN, each created by newReplica (the same driver as the
+// primary).
+func NewReplicatedDB(primary Database, newReplica func() Database, replicaDSNs []string) *ReplicatedDB {
+	return &ReplicatedDB{primary: primary, newReplica: newReplica, dsns: replicaDSNs}
+}
+
+// Connect connects the primary with config and every replica with config's
+// pool settings and its own DSN.
+func (r *ReplicatedDB) Connect(config *Config) error {
+	if err := r.primary.Connect(config); err != nil {
+		return err
+	}
+	for i, dsn := range r.dsns {
+		replicaConfig := *config
+		replicaConfig.DSN = dsn
+		replicaConfig.Name = fmt.Sprintf("replica-%d", i)
+		db := r.newReplica()
+		// 日志与错误中只用序号标识副本，DSN 可能包含密码
+		if err := db.Connect(&replicaConfig); err != nil {
+			_ = r.Close()
+			return fmt.Errorf("replica %d: %w", i, err)
+		}
+		rep := &replica{db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	return nil
+}
+
+// Close closes the replicas and the primary.
+func (r *ReplicatedDB) Close() error {
+	var errs []error
+	for i, rep := range r.replicas {
+		if err := rep.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("replica %d: %w", i, err))
+		}
+	}
+	r.replicas = nil
+	if err := r.primary.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// DB returns the primary.
+func (r *ReplicatedDB) DB() *gorm.DB {
+	return r.primary.DB()
+}
+
+// Reader returns the next healthy replica, or the primary if none is healthy.
+func (r *ReplicatedDB) Reader() *gorm.DB {
+	n := uint64(len(r.replicas))
+	if n == 0 {
+		return r.primary.DB()
+	}
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep.db.DB()
+		}
+	}
+	return r.primary.DB()
+}
+
+// CheckHealth pings every replica, ejecting those that fail from Reader's
+// rotation and readmitting those that answer again. It returns the failures
+// of this round.
+func (r *ReplicatedDB) CheckHealth(ctx context.Context) error {
+	var errs []error
+	for i, rep := range r.replicas {
+		err := ping(ctx, rep.db)
+		rep.healthy.Store(err == nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("replica %d is unhealthy: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HealthyReplicas returns how many replicas are in rotation, out of total.
+func (r *ReplicatedDB) HealthyReplicas() (healthy, total int) {
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy, len(r.replicas)
+}
+
+func ping(ctx context.Context, db Database) error {
+	sqlDB, err := db.DB().DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,118 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+	"github.com/kirklin/boot-backend-go-clean/pkg/database/sqlite"
+)
+
+// ─── Helper ───────────────────────────────────────────────────────────────────
+
+// fakeDB hands out an in-memory SQLite connection that the test can break
+// and repair, and records the DSN it was connected with.
+type fakeDB struct {
+	db  *gorm.DB
+	dsn string
+}
+
+func (f *fakeDB) Connect(config *database.Config) error {
+	f.dsn = config.DSN
+	return nil
+}
+
+func (f *fakeDB) Close() error { return nil }
+
+func (f *fakeDB) DB() *gorm.DB { return f.db }
+
+func openMemory(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := sqlite.NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{DBName: sqlite.MemoryDBName}))
+	t.Cleanup(func() { _ = db.Close() })
+	return db.DB()
+}
+
+func closeConn(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+}
+
+func newReplicated(t *testing.T, replicas int) (*database.ReplicatedDB, *fakeDB, []*fakeDB) {
+	t.Helper()
+	primary := &fakeDB{db: openMemory(t)}
+	fakes := make([]*fakeDB, replicas)
+	dsns := make([]string, replicas)
+	for i := range fakes {
+		fakes[i] = &fakeDB{db: openMemory(t)}
+		dsns[i] = "replica-" + string(rune('a'+i))
+	}
+	next := 0
+	db := database.NewReplicatedDB(primary, func() database.Database {
+		f := fakes[next]
+		next++
+		return f
+	}, dsns)
+	require.NoError(t, db.Connect(&database.Config{DSN: "primary"}))
+	return db, primary, fakes
+}
+
+// ─── Tests ────────────────────────────────────────────────────────────────────
+
+func TestReplicatedDB_Connect(t *testing.T) {
+	db, primary, replicas := newReplicated(t, 2)
+
+	assert.Equal(t, "primary", primary.dsn)
+	assert.Equal(t, "replica-a", replicas[0].dsn)
+	assert.Equal(t, "replica-b", replicas[1].dsn)
+	assert.Same(t, primary.db, db.DB())
+}
+
+func TestReplicatedDB_ReaderRoundRobin(t *testing.T) {
+	db, _, replicas := newReplicated(t, 2)
+
+	first, second, third := db.Reader(), db.Reader(), db.Reader()
+
+	assert.NotSame(t, first, second)
+	assert.Same(t, first, third)
+	for _, reader := range []*gorm.DB{first, second} {
+		assert.True(t, reader == replicas[0].db || reader == replicas[1].db)
+	}
+}
+
+func TestReplicatedDB_EjectsAndReadmitsReplicas(t *testing.T) {
+	db, _, replicas := newReplicated(t, 2)
+	ctx := context.Background()
+
+	closeConn(t, replicas[0].db)
+	assert.Error(t, db.CheckHealth(ctx))
+
+	healthy, total := db.HealthyReplicas()
+	assert.Equal(t, 1, healthy)
+	assert.Equal(t, 2, total)
+	for range 3 {
+		assert.Same(t, replicas[1].db, db.Reader())
+	}
+
+	// 副本恢复后重新加入轮询
+	replicas[0].db = openMemory(t)
+	assert.NoError(t, db.CheckHealth(ctx))
+	healthy, _ = db.HealthyReplicas()
+	assert.Equal(t, 2, healthy)
+}
+
+func TestReplicatedDB_FallsBackToPrimary(t *testing.T) {
+	db, primary, replicas := newReplicated(t, 1)
+
+	closeConn(t, replicas[0].db)
+	assert.Error(t, db.CheckHealth(context.Background()))
+
+	assert.Same(t, primary.db, db.Reader())
+}
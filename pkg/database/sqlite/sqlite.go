@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+// MemoryDBName selects a private in-memory database instead of a file.
+const MemoryDBName = ":memory:"
+
+// SQLiteDB stores data in a local SQLite file, or in memory, so that the
+// application can run without a database server (local development, tests).
+// It is not meant for production: SQLite allows a single writer at a time
+// and the driver needs cgo, which the release image is built without.
+type SQLiteDB struct {
+	db *gorm.DB
+}
+
+func NewSQLiteDB() database.Database {
+	return &SQLiteDB{}
+}
+
+// Connect opens the database at config.DBName, a file path or MemoryDBName.
+// Host, port and credentials are ignored.
+func (s *SQLiteDB) Connect(config *database.Config) error {
+	memory := config.DBName == MemoryDBName
+
+	db, err := gorm.Open(sqlite.Open(dsn(config.DBName)), &gorm.Config{Logger: database.NewGormLogger(config)})
+	if err != nil {
+		database.Discard(db)
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	s.db = db
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance for pooling config: %w", err)
+	}
+	if err := database.Instrument(s.db, config.Name); err != nil {
+		return err
+	}
+
+	if memory {
+		// 每个连接都会打开各自独立的内存数据库，只能保留唯一一个连接且不能让它过期
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		return nil
+	}
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetimeMinutes) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(config.ConnMaxIdleTimeMinutes) * time.Minute)
+
+	return nil
+}
+
+// dsn adds the connection options to name. Writers wait for each other
+// instead of failing with SQLITE_BUSY, and file databases use WAL so that
+// readers do not block the writer.
+func dsn(name string) string {
+	options := "_busy_timeout=5000&_foreign_keys=on"
+	if name != MemoryDBName {
+		options += "&_journal_mode=WAL"
+	}
+	separator := "?"
+	if strings.Contains(name, "?") {
+		separator = "&"
+	}
+	return name + separator + options
+}
+
+func (s *SQLiteDB) Close() error {
+	if s.db == nil {
+		return nil // 从未连接成功
+	}
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %w", err)
+	}
+	return sqlDB.Close()
+}
+
+func (s *SQLiteDB) DB() *gorm.DB {
+	return s.db
+}
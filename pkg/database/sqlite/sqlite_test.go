@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirklin/boot-backend-go-clean/pkg/database"
+)
+
+func TestSQLiteDB_ConnectMemory(t *testing.T) {
+	db := NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{DBName: MemoryDBName}))
+	defer db.Close()
+
+	// 内存库只有一个连接，建表后的查询必须能看到同一份数据
+	require.NoError(t, db.DB().Exec("CREATE TABLE items (name TEXT)").Error)
+	require.NoError(t, db.DB().Exec("INSERT INTO items (name) VALUES ('a')").Error)
+
+	var count int64
+	require.NoError(t, db.DB().Table("items").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestSQLiteDB_ConnectFile(t *testing.T) {
+	db := NewSQLiteDB()
+	require.NoError(t, db.Connect(&database.Config{
+		DBName:                 filepath.Join(t.TempDir(), "app.db"),
+		MaxIdleConns:           2,
+		MaxOpenConns:           4,
+		ConnMaxLifetimeMinutes: 60,
+	}))
+	defer db.Close()
+
+	var journalMode string
+	require.NoError(t, db.DB().Raw("PRAGMA journal_mode").Scan(&journalMode).Error)
+	assert.Equal(t, "wal", journalMode)
+
+	var foreignKeys int
+	require.NoError(t, db.DB().Raw("PRAGMA foreign_keys").Scan(&foreignKeys).Error)
+	assert.Equal(t, 1, foreignKeys)
+}
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"memory", MemoryDBName, ":memory:?_busy_timeout=5000&_foreign_keys=on"},
+		{"file", "data/app.db", "data/app.db?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL"},
+		{"file with options", "data/app.db?cache=shared", "data/app.db?cache=shared&_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dsn(tt.in))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Package fieldcrypt encrypts individual database values, such as the email
+// column of a table, and computes blind indexes so that encrypted values can
+// still be looked up by exact match.
+//
+// Values are envelope encrypted with AES-256-GCM: each value gets a fresh
+// data key, which is itself encrypted with a key-encryption key (KEK) from
+// the Keyring. The ID of that KEK is stored in the value, so keys can be
+// rotated: add a new KEK, make it active, re-encrypt the values that
+// NeedsRotation reports, then drop the old key.
+//
+// Every value is bound to where it is stored (see Location): the table,
+// column and row ID are authenticated as additional data, so a ciphertext
+// copied into another row or column does not decrypt.
+//
+// An encrypted value looks like
+//
+//	enc:<key ID>:<base64 wrapped data key>:<base64 ciphertext>
+//
+// Values without the enc: prefix are treated as legacy plaintext: Decrypt
+// returns them unchanged and NeedsRotation reports them, so that existing
+// rows can be encrypted in the background.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// prefix marks encrypted values.
+const prefix = "enc:"
+
+// keySize is the size of KEKs and data keys: AES-256.
+const keySize = 32
+
+// ErrMalformed is returned by Decrypt for a value that has the enc: prefix
+// but cannot be decrypted with the keyring.
+var ErrMalformed = errors.New("fieldcrypt: malformed or unknown encrypted value")
+
+// keyIDPattern restricts key IDs to characters that need no escaping in the
+// value format, the configuration or a LIKE pattern.
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]{1,32}$`)
+
+// Location identifies the database cell a value is stored in.
+type Location struct {
+	Table  string
+	Column string
+	RowID  int64
+}
+
+// aad returns the additional authenticated data of values stored at l.
+func (l Location) aad() []byte {
+	return []byte(l.Table + "." + l.Column + "#" + strconv.FormatInt(l.RowID, 10))
+}
+
+// Keyring holds the KEKs values are encrypted with and the key of the blind
+// index. A Keyring is safe for concurrent use.
+type Keyring struct {
+	keks     map[string]cipher.AEAD
+	active   string
+	indexKey []byte
+}
+
+// NewKeyring creates a Keyring. keys maps key IDs to 32-byte KEKs; new
+// values are encrypted with the one named active. indexKey keys the blind
+// index; unlike the KEKs it cannot be rotated without recomputing every index.
+func NewKeyring(keys map[string][]byte, active string, indexKey []byte) (*Keyring, error) {
+	if len(indexKey) == 0 {
+		return nil, errors.New("fieldcrypt: blind index key is empty")
+	}
+	k := &Keyring{keks: make(map[string]cipher.AEAD, len(keys)), active: active, indexKey: indexKey}
+	for id, key := range keys {
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("fieldcrypt: invalid key ID %q (want 1-32 letters, digits or dashes)", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("fieldcrypt: key %q is %d bytes, want %d", id, len(key), keySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keks[id] = aead
+	}
+	if _, ok := k.keks[active]; !ok {
+		return nil, fmt.Errorf("fieldcrypt: active key %q is not in the keyring", active)
+	}
+	return k, nil
+}
+
+// ParseKeys parses a comma-separated list of id:base64 pairs, the format of
+// the ENCRYPTION_KEYS setting.
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("fieldcrypt: key entry %q is not id:base64", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: key %q is not valid base64: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("fieldcrypt: duplicate key ID %q", id)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// ActiveKeyID returns the ID of the KEK new values are encrypted with.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// EncryptedPrefix returns the prefix every value encrypted with the active
+// key starts with.
+func (k *Keyring) EncryptedPrefix() string {
+	return prefix + k.active + ":"
+}
+
+// Encrypt encrypts plaintext, to be stored at loc, with a fresh data key
+// wrapped by the active KEK. Encrypting the same plaintext twice gives
+// different values; use BlindIndex to look values up.
+func (k *Keyring) Encrypt(plaintext string, loc Location) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("fieldcrypt: failed to generate data key: %w", err)
+	}
+	wrapped, err := seal(k.keks[k.active], dataKey, nil)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(aead, []byte(plaintext), loc.aad())
+	if err != nil {
+		return "", err
+	}
+	return k.EncryptedPrefix() +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt returns the plaintext of a value produced by Encrypt with any key
+// of the keyring for the same loc. Values without the enc: prefix are
+// returned unchanged.
+func (k *Keyring) Decrypt(value string, loc Location) (string, error) {
+	rest, encrypted := strings.CutPrefix(value, prefix)
+	if !encrypted {
+		return value, nil
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	kek, ok := k.keks[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: key %q is not in the keyring", ErrMalformed, parts[0])
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := open(kek, wrapped, nil)
+	if err != nil || len(dataKey) != keySize {
+		return "", ErrMalformed
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, ciphertext, loc.aad())
+	if err != nil {
+		return "", ErrMalformed
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether value is plaintext or encrypted with a key
+// other than the active one.
+func (k *Keyring) NeedsRotation(value string) bool {
+	return !strings.HasPrefix(value, k.EncryptedPrefix())
+}
+
+// BlindIndex returns a keyed hash of value (hex HMAC-SHA256) for exact-match
+// lookups of encrypted values. Normalize value first, e.g. lower-case emails,
+// so that equal values hash alike.
+func (k *Keyring) BlindIndex(value string) string {
+	h := hmac.New(sha256.New, k.indexKey)
+	h.Write([]byte(value))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: %w", err)
+	}
+	return aead, nil
+}
+
+// seal encrypts plaintext and authenticates additionalData with a random
+// nonce, which it prepends.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("fieldcrypt: failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open reverses seal.
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
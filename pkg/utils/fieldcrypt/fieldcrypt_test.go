@@ -0,0 +1,147 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func newTestKeyring(t *testing.T, active string) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(map[string][]byte{"old": testKey(1), "new": testKey(2)}, active, []byte("index-secret"))
+	require.NoError(t, err)
+	return k
+}
+
+// testLocation is where the values of the tests are stored.
+var testLocation = Location{Table: "users", Column: "email", RowID: 42}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	k := newTestKeyring(t, "new")
+
+	value, err := k.Encrypt("kirk@example.com", testLocation)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(value, "enc:new:"))
+	assert.NotContains(t, value, "kirk")
+
+	again, err := k.Encrypt("kirk@example.com", testLocation)
+	require.NoError(t, err)
+	assert.NotEqual(t, value, again, "fresh data key and nonce per value")
+
+	plaintext, err := k.Decrypt(value, testLocation)
+	require.NoError(t, err)
+	assert.Equal(t, "kirk@example.com", plaintext)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old := newTestKeyring(t, "old")
+	value, err := old.Encrypt("kirk@example.com", testLocation)
+	require.NoError(t, err)
+
+	k := newTestKeyring(t, "new")
+	assert.True(t, k.NeedsRotation(value))
+	assert.True(t, k.NeedsRotation("kirk@example.com"), "legacy plaintext")
+	plaintext, err := k.Decrypt(value, testLocation)
+	require.NoError(t, err)
+	assert.Equal(t, "kirk@example.com", plaintext, "retired keys still decrypt")
+
+	rotated, err := k.Encrypt(plaintext, testLocation)
+	require.NoError(t, err)
+	assert.False(t, k.NeedsRotation(rotated))
+
+	// 旧密钥移出 keyring 后无法再解密
+	withoutOld, err := NewKeyring(map[string][]byte{"new": testKey(2)}, "new", []byte("index-secret"))
+	require.NoError(t, err)
+	_, err = withoutOld.Decrypt(value, testLocation)
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestKeyring_DecryptRejectsTampering(t *testing.T) {
+	k := newTestKeyring(t, "new")
+	value, err := k.Encrypt("kirk@example.com", testLocation)
+	require.NoError(t, err)
+
+	plaintext, err := k.Decrypt("kirk@example.com", testLocation)
+	require.NoError(t, err)
+	assert.Equal(t, "kirk@example.com", plaintext, "plaintext passes through")
+
+	tampered := value[:len(value)-2] + "AA"
+	if tampered == value {
+		tampered = value[:len(value)-2] + "BB"
+	}
+	for _, bad := range []string{tampered, "enc:new:only-two", "enc:new:!!:!!"} {
+		_, err := k.Decrypt(bad, testLocation)
+		assert.ErrorIs(t, err, ErrMalformed, bad)
+	}
+}
+
+// A value only decrypts in the table, column and row it was encrypted for,
+// so it cannot be moved to another user or column.
+func TestKeyring_DecryptChecksLocation(t *testing.T) {
+	k := newTestKeyring(t, "new")
+	value, err := k.Encrypt("kirk@example.com", testLocation)
+	require.NoError(t, err)
+
+	for _, loc := range []Location{
+		{Table: "users", Column: "email", RowID: 43},
+		{Table: "users", Column: "username", RowID: 42},
+		{Table: "email_changes", Column: "email", RowID: 42},
+	} {
+		_, err := k.Decrypt(value, loc)
+		assert.ErrorIs(t, err, ErrMalformed, "%+v", loc)
+	}
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	k := newTestKeyring(t, "new")
+	other, err := NewKeyring(map[string][]byte{"new": testKey(2)}, "new", []byte("other-secret"))
+	require.NoError(t, err)
+
+	assert.Equal(t, k.BlindIndex("kirk@example.com"), newTestKeyring(t, "old").BlindIndex("kirk@example.com"),
+		"independent of the encryption keys")
+	assert.NotEqual(t, k.BlindIndex("kirk@example.com"), k.BlindIndex("spock@example.com"))
+	assert.NotEqual(t, k.BlindIndex("kirk@example.com"), other.BlindIndex("kirk@example.com"))
+	assert.Len(t, k.BlindIndex("kirk@example.com"), 64)
+}
+
+func TestNewKeyring_Validation(t *testing.T) {
+	index := []byte("index-secret")
+	tests := []struct {
+		name   string
+		keys   map[string][]byte
+		active string
+		index  []byte
+	}{
+		{"active key missing", map[string][]byte{"k1": testKey(1)}, "k2", index},
+		{"short key", map[string][]byte{"k1": testKey(1)[:16]}, "k1", index},
+		{"invalid key ID", map[string][]byte{"k:1": testKey(1)}, "k:1", index},
+		{"empty index key", map[string][]byte{"k1": testKey(1)}, "k1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyring(tt.keys, tt.active, tt.index)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testKey(1))
+
+	keys, err := ParseKeys("k1:" + encoded + ", k2:" + encoded + ",")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"k1": testKey(1), "k2": testKey(1)}, keys)
+
+	for _, bad := range []string{"k1", "k1:not-base64!", "k1:" + encoded + ",k1:" + encoded} {
+		_, err := ParseKeys(bad)
+		assert.Error(t, err, bad)
+	}
+}
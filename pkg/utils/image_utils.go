@@ -1,5 +1,23 @@
 package utils
 
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // 注册 GIF 解码器
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码器
+)
+
+// MaxImagePixels bounds the decoded size of an uploaded image (width × height)
+// to protect against decompression bombs.
+const MaxImagePixels = 40_000_000
+
 // IsValidImageType 验证文件类型是否为图片
 func IsValidImageType(contentType string) bool {
 	validTypes := map[string]bool{
@@ -11,3 +29,58 @@ func IsValidImageType(contentType string) bool {
 	}
 	return validTypes[contentType]
 }
+
+// SniffImageType 根据文件头（magic bytes）识别图片类型，不信任客户端声明的 Content-Type。
+// It returns the detected MIME type and whether it is an accepted image type.
+func SniffImageType(data []byte) (string, bool) {
+	contentType := http.DetectContentType(data)
+	return contentType, IsValidImageType(contentType)
+}
+
+// DecodeImage decodes data after checking its declared dimensions against MaxImagePixels.
+func DecodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, errors.New("image has no pixels")
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d exceed the limit", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// SquareThumbnail center-crops src to a square and scales it to size×size.
+// Images smaller than size are scaled up so every thumbnail has the same dimensions.
+func SquareThumbnail(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		b.Min.X+(b.Dx()-side)/2,
+		b.Min.Y+(b.Dy()-side)/2,
+	))
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// EncodeImage encodes img as JPEG when contentType is "image/jpeg" and as PNG otherwise,
+// which keeps transparency for PNG, GIF and WebP sources.
+// It returns the encoded bytes and the MIME type actually used.
+func EncodeImage(img image.Image, contentType string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
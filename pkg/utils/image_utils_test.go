@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniffImageType(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+		ok   bool
+	}{
+		{"png", []byte("\x89PNG\r\n\x1a\n"), "image/png", true},
+		{"jpeg", []byte("\xff\xd8\xff\xe0"), "image/jpeg", true},
+		{"gif", []byte("GIF89a"), "image/gif", true},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "image/webp", true},
+		{"svg is rejected", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), "text/plain; charset=utf-8", false},
+		{"pdf", []byte("%PDF-1.4"), "application/pdf", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SniffImageType(tt.data)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestDecodeImage_RejectsOversizedDimensions(t *testing.T) {
+	// A valid PNG header declaring 10000×10000 pixels; the body is never decoded.
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+	data := buf.Bytes()
+	// IHDR width/height live at byte offsets 16..24
+	copy(data[16:24], []byte{0, 0, 0x27, 0x10, 0, 0, 0x27, 0x10})
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	_, err := DecodeImage(data)
+
+	assert.ErrorContains(t, err, "exceed the limit")
+}
+
+func TestSquareThumbnail_CentersCrop(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	thumb := SquareThumbnail(src, 64)
+
+	assert.Equal(t, image.Rect(0, 0, 64, 64), thumb.Bounds())
+}
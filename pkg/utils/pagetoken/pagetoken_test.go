@@ -0,0 +1,56 @@
+package pagetoken
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cursor struct {
+	LastID int64 `json:"l"`
+	Page   int64 `json:"p"`
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := NewCodec("secret")
+
+	token, err := codec.Encode(cursor{LastID: 42, Page: 3})
+	require.NoError(t, err)
+
+	var got cursor
+	require.NoError(t, codec.Decode(token, &got))
+	assert.Equal(t, cursor{LastID: 42, Page: 3}, got)
+}
+
+func TestCodec_RejectsTamperedPayload(t *testing.T) {
+	codec := NewCodec("secret")
+	token, err := codec.Encode(cursor{LastID: 42, Page: 3})
+	require.NoError(t, err)
+
+	// Re-encode a different payload but keep the original signature.
+	forged, err := codec.Encode(cursor{LastID: 1, Page: 3})
+	require.NoError(t, err)
+	body, _, _ := strings.Cut(forged, ".")
+	_, sig, _ := strings.Cut(token, ".")
+
+	var got cursor
+	assert.ErrorIs(t, codec.Decode(body+"."+sig, &got), ErrInvalidToken)
+}
+
+func TestCodec_RejectsTokenFromOtherSecret(t *testing.T) {
+	token, err := NewCodec("secret-a").Encode(cursor{LastID: 42})
+	require.NoError(t, err)
+
+	var got cursor
+	assert.ErrorIs(t, NewCodec("secret-b").Decode(token, &got), ErrInvalidToken)
+}
+
+func TestCodec_RejectsMalformedTokens(t *testing.T) {
+	codec := NewCodec("secret")
+	for _, token := range []string{"", "no-dot", ".", "abc.!!!", "!!!.abc"} {
+		var got cursor
+		assert.ErrorIs(t, codec.Decode(token, &got), ErrInvalidToken, "token %q", token)
+	}
+}
@@ -1,14 +1,25 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
-	"time"
+	"path/filepath"
+	"regexp"
+	"strconv"
 )
 
+// migrationsDir holds one directory of migrations per database dialect.
+const migrationsDir = "internal/infrastructure/persistence/migrations"
+
+var (
+	dialects    = []string{"postgres", "mysql", "sqlite"}
+	namePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+	filePattern = regexp.MustCompile(`^(\d+)_[a-z0-9_]+\.(up|down)\.sql$`)
+)
+
+// Creates empty up/down migration files with the next version number for
+// every dialect, e.g. `go run scripts/generate_migration.go add_users_phone`.
 func main() {
 	if len(os.Args) < 2 {
 		log.Println("Please provide a migration name")
@@ -16,18 +27,48 @@ func main() {
 	}
 
 	migrationName := os.Args[1]
-	timestamp := time.Now().Unix()
-	fileName := fmt.Sprintf("%d_%s", timestamp, migrationName)
-
-	cmd := exec.CommandContext(context.Background(), "migrate", "create", "-ext", "sql", "-dir", "migrations", "-seq", fileName)
+	if !namePattern.MatchString(migrationName) {
+		log.Println("Migration name may only contain lowercase letters, digits and underscores")
+		os.Exit(1)
+	}
 
-	output, err := cmd.CombinedOutput()
+	version, err := nextVersion()
 	if err != nil {
-		log.Printf("Error creating migration: %v\n", err)
-		log.Println(string(output))
+		log.Printf("Error reading migrations: %v\n", err)
 		os.Exit(1)
 	}
 
+	for _, dialect := range dialects {
+		for _, direction := range []string{"up", "down"} {
+			fileName := filepath.Join(migrationsDir, dialect, fmt.Sprintf("%06d_%s.%s.sql", version, migrationName, direction))
+			if err := os.WriteFile(fileName, nil, 0o644); err != nil {
+				log.Printf("Error creating migration: %v\n", err)
+				os.Exit(1)
+			}
+			log.Println(fileName)
+		}
+	}
+
 	log.Println("Migration files created successfully")
-	log.Println(string(output))
+}
+
+// nextVersion returns one more than the highest version of any dialect.
+func nextVersion() (int64, error) {
+	var highest int64
+	for _, dialect := range dialects {
+		entries, err := os.ReadDir(filepath.Join(migrationsDir, dialect))
+		if err != nil {
+			return 0, err
+		}
+		for _, entry := range entries {
+			match := filePattern.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+			if version, _ := strconv.ParseInt(match[1], 10, 64); version > highest {
+				highest = version
+			}
+		}
+	}
+	return highest + 1, nil
 }